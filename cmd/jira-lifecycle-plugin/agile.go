@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/andygrunwald/go-jira"
+
+	jiraclient "k8s.io/test-infra/prow/jira"
+)
+
+// agileClient holds the Jira API calls used by the plugin that are not part of the Jira client
+// from test-infra, most of which belong to the Jira Agile API.
+type agileClient interface {
+	// IsIssueOnBoard determines whether the issue is on the board with the given ID
+	IsIssueOnBoard(boardID int, issueKey string) (bool, error)
+	// ProjectVersions lists the names of the versions of the project that are not archived
+	ProjectVersions(projectKey string) ([]string, error)
+	// IssueMatchesJQL determines whether the issue is returned by a search with the given JQL filter
+	IssueMatchesJQL(issueKey, jql string) (bool, error)
+	// BoardColumn returns the name of the column of the board with the given ID that the status
+	// with the given ID is mapped to, or an empty string if it is not mapped to any column
+	BoardColumn(boardID int, statusID string) (string, error)
+}
+
+// jiraAgileClient implements the agileClient using the underlying client of a Jira client.
+type jiraAgileClient struct {
+	jc jiraclient.Client
+}
+
+func (c *jiraAgileClient) IsIssueOnBoard(boardID int, issueKey string) (bool, error) {
+	client := c.jc.JiraClient()
+	query := url.Values{
+		"jql":        []string{fmt.Sprintf("key = %q", issueKey)},
+		"fields":     []string{"key"},
+		"maxResults": []string{"1"},
+	}
+	req, err := client.NewRequest(http.MethodGet, fmt.Sprintf("rest/agile/1.0/board/%d/issue?%s", boardID, query.Encode()), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	var result struct {
+		Total int `json:"total"`
+	}
+	resp, err := client.Do(req, &result)
+	if err != nil {
+		return false, jira.NewJiraError(resp, err)
+	}
+	return result.Total > 0, nil
+}
+
+func (c *jiraAgileClient) ProjectVersions(projectKey string) ([]string, error) {
+	project, resp, err := c.jc.JiraClient().Project.Get(projectKey)
+	if err != nil {
+		return nil, jira.NewJiraError(resp, err)
+	}
+	var versions []string
+	for _, version := range project.Versions {
+		if version.Archived != nil && *version.Archived {
+			continue
+		}
+		versions = append(versions, version.Name)
+	}
+	return versions, nil
+}
+
+func (c *jiraAgileClient) IssueMatchesJQL(issueKey, jql string) (bool, error) {
+	issues, resp, err := c.jc.JiraClient().Issue.Search(fmt.Sprintf("key = %q AND (%s)", issueKey, jql), &jira.SearchOptions{
+		MaxResults: 1,
+		Fields:     []string{"key"},
+	})
+	if err != nil {
+		return false, jira.NewJiraError(resp, err)
+	}
+	return len(issues) > 0, nil
+}
+
+func (c *jiraAgileClient) BoardColumn(boardID int, statusID string) (string, error) {
+	client := c.jc.JiraClient()
+	req, err := client.NewRequest(http.MethodGet, fmt.Sprintf("rest/agile/1.0/board/%d/configuration", boardID), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	var result struct {
+		ColumnConfig struct {
+			Columns []struct {
+				Name     string `json:"name"`
+				Statuses []struct {
+					ID string `json:"id"`
+				} `json:"statuses"`
+			} `json:"columns"`
+		} `json:"columnConfig"`
+	}
+	resp, err := client.Do(req, &result)
+	if err != nil {
+		return "", jira.NewJiraError(resp, err)
+	}
+	for _, column := range result.ColumnConfig.Columns {
+		for _, status := range column.Statuses {
+			if status.ID == statusID {
+				return column.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
@@ -2,8 +2,15 @@ package main
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/andygrunwald/go-jira"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+
+	"github.com/openshift-eng/jira-lifecycle-plugin/pkg/helpers"
+	"github.com/openshift-eng/jira-lifecycle-plugin/pkg/labels"
 )
 
 // Jira holds the config for the jira plugin.
@@ -13,6 +20,10 @@ type Config struct {
 	Default map[string]JiraBranchOptions `json:"default,omitempty"`
 	// Options for specific orgs. The `*` wildcard will apply to all orgs.
 	Orgs map[string]JiraOrgOptions `json:"orgs,omitempty"`
+	// TrackedProjects lists the Jira projects whose issues are managed by this plugin.
+	// Pull requests referencing issues in other projects get a comment and no labels.
+	// If empty, issues from all projects are managed.
+	TrackedProjects []string `json:"tracked_projects,omitempty"`
 }
 
 // JiraOrgOptions holds options for checking Jira bugs for an org.
@@ -22,6 +33,9 @@ type JiraOrgOptions struct {
 	Default map[string]JiraBranchOptions `json:"default,omitempty"`
 	// Options for specific repos. The `*` wildcard will apply to all repos.
 	Repos map[string]JiraRepoOptions `json:"repos,omitempty"`
+	// JiraURL overrides the Jira URL used when rendering links to issues in
+	// comments for this org. Defaults to the URL of the configured Jira server.
+	JiraURL string `json:"jira_url,omitempty"`
 }
 
 // JiraRepoOptions holds options for checking Jira bugs for a repo.
@@ -29,6 +43,17 @@ type JiraRepoOptions struct {
 	// Options for specific branches in this repo.
 	// The `*` wildcard will apply to all branches.
 	Branches map[string]JiraBranchOptions `json:"branches,omitempty"`
+	// PrivilegedCommandUsers lists the GitHub logins allowed to run privileged commands,
+	// e.g. `/jira verify`, in this repo. If neither this nor PrivilegedCommandTeams is set,
+	// anyone passing the command's own checks may run it.
+	PrivilegedCommandUsers []string `json:"privileged_command_users,omitempty"`
+	// PrivilegedCommandTeams lists the slugs of the GitHub teams of the org whose members
+	// are allowed to run privileged commands in this repo.
+	PrivilegedCommandTeams []string `json:"privileged_command_teams,omitempty"`
+	// ExemptBotLogins lists the GitHub logins of bots, e.g. dependency-bump bots, whose pull
+	// requests are exempt from the Jira requirements of this repo: the bugs they reference are
+	// neither validated nor labeled, and only an informational comment is left when they open one.
+	ExemptBotLogins []string `json:"exempt_bot_logins,omitempty"`
 }
 
 // JiraBugState describes bug states in the Jira plugin config, used
@@ -73,10 +98,106 @@ type JiraBranchOptions struct {
 
 	// IsOpen determines whether a bug needs to be open to be valid
 	IsOpen *bool `json:"is_open,omitempty"`
+	// RequireKnownTargetVersion determines whether the target version of the bug needs to be
+	// a known version of its project, which catches typos in the target version. The versions
+	// are listed in KnownTargetVersions or, if unset, fetched from the project in Jira, in which
+	// case archived versions are not known.
+	RequireKnownTargetVersion *bool `json:"require_known_target_version,omitempty"`
+	// KnownTargetVersions lists the versions checked by RequireKnownTargetVersion
+	KnownTargetVersions *[]string `json:"known_target_versions,omitempty"`
 	// SkipTargetVersionCheck exclude branch from the TargetVersion check
 	SkipTargetVersionCheck *bool `json:"skip_target_version_check,omitempty"`
 	// TargetVersion determines which release a bug needs to target to be valid
 	TargetVersion *string `json:"target_version,omitempty"`
+	// TargetVersions determines a list of releases, any of which a bug may target to be valid.
+	// If TargetVersion is also set, it is treated as one more acceptable release.
+	TargetVersions *[]string `json:"target_versions,omitempty"`
+	// ValidateBranchTargetConsistency determines whether the bug's target version must be
+	// consistent with the branch the pull request merges into, as defined by BranchTargetVersions
+	ValidateBranchTargetConsistency *bool `json:"validate_branch_target_consistency,omitempty"`
+	// BranchTargetVersions maps branch names to the version that bugs on that branch are
+	// expected to target (e.g. `master: "4.14"`, `release-4.13: "4.13"`). Branches that are
+	// not listed are not checked.
+	BranchTargetVersions map[string]string `json:"branch_target_versions,omitempty"`
+	// MergeRepos maps Jira projects to additional repos, in `org/repo` form, whose pull requests
+	// linked to bugs of that project count toward merge completion, e.g. for backports to related
+	// repos that the plugin is not enabled for. Pull requests in other repos are ignored.
+	MergeRepos map[string][]string `json:"merge_repos,omitempty"`
+	// IssueTypeBranches maps issue types, e.g. `Epic`, to regular expressions matching the whole
+	// name of the branches that issues of the type may be fixed on, e.g. `main|master` to keep
+	// epics from being backported to z-streams. Issues of types that are not listed are allowed
+	// on any branch. The patterns of a type replace those inherited for the same type.
+	IssueTypeBranches map[string][]string `json:"issue_type_branches,omitempty"`
+	// AllowedReporters is a list of the names or account IDs of the Jira users that may report
+	// bugs for this branch. If set, bugs reported by other users are not valid.
+	AllowedReporters []string `json:"allowed_reporters,omitempty"`
+	// RejectResolutions is a list of resolutions, e.g. `Won't Do` or `Not a Bug`, that mean a
+	// bug should not be fixed. Bugs closed with one of these resolutions are not valid.
+	RejectResolutions []string `json:"reject_resolutions,omitempty"`
+	// RejectIfAlreadyFixedInTarget determines whether bugs that are already resolved with a
+	// fix version matching the branch's target version are rejected, to avoid redundant backports
+	RejectIfAlreadyFixedInTarget *bool `json:"reject_if_already_fixed_in_target,omitempty"`
+	// RetitleCommand is the command used to retitle a pull request after the bugs it
+	// references have been cloned. Defaults to `/retitle`.
+	RetitleCommand *string `json:"retitle_command,omitempty"`
+	// RequiredBoardID is the ID of the Jira Agile board, e.g. a team's sprint board, that the
+	// bug needs to be on to be valid
+	RequiredBoardID *int `json:"required_board_id,omitempty"`
+	// RequireVerificationField determines whether the bug's verification field, e.g. a
+	// linked test case, needs to be populated for the bug to be valid
+	RequireVerificationField *bool `json:"require_verification_field,omitempty"`
+	// VerificationField is the ID of the custom field checked by RequireVerificationField,
+	// e.g. `customfield_12345`, as it differs between Jira instances
+	VerificationField *string `json:"verification_field,omitempty"`
+	// RequireReleaseNoteType determines whether the bug's release note type needs to be set
+	// for the bug to be valid, e.g. for docs teams to know whether a release note is needed
+	RequireReleaseNoteType *bool `json:"require_release_note_type,omitempty"`
+	// ReleaseNoteTypeField is the ID of the custom field checked by RequireReleaseNoteType,
+	// e.g. `customfield_12345`, as it differs between Jira instances
+	ReleaseNoteTypeField *string `json:"release_note_type_field,omitempty"`
+	// RequireStoryPoints determines whether the bug needs to be estimated, i.e. have its story
+	// points set, to be valid, e.g. for feature branches
+	RequireStoryPoints *bool `json:"require_story_points,omitempty"`
+	// StoryPointsField is the ID of the custom field checked by RequireStoryPoints,
+	// e.g. `customfield_12345`, as it differs between Jira instances
+	StoryPointsField *string `json:"story_points_field,omitempty"`
+	// RejectFlaggedBlocked determines whether bugs that are flagged, which marks them as
+	// blocked in Jira, are not valid, as linking a fix to them may be premature
+	RejectFlaggedBlocked *bool `json:"reject_flagged_blocked,omitempty"`
+	// FlaggedField is the ID of the custom field holding the Jira flag checked by
+	// RejectFlaggedBlocked, e.g. `customfield_12345`, as it differs between Jira instances
+	FlaggedField *string `json:"flagged_field,omitempty"`
+	// ContributorsField is the ID of the multi-user custom field listing the contributors of
+	// the bug, e.g. `customfield_12345`. If set, the contributors are checked to include the
+	// author of the pull request, by resolving their public emails to GitHub logins
+	ContributorsField *string `json:"contributors_field,omitempty"`
+	// RequireAuthorInContributors determines whether a bug whose contributors do not include
+	// the author of the pull request is invalid, instead of only warning about it
+	RequireAuthorInContributors *bool `json:"require_author_in_contributors,omitempty"`
+	// TestRepos are regular expressions matching the whole `org/repo` name of the repos holding
+	// the tests for fixes, e.g. `openshift/origin`. If set, the bug is checked to link a pull
+	// request in one of these repos through its remote links
+	TestRepos *[]string `json:"test_repos,omitempty"`
+	// RequireTestPR determines whether a bug that does not link a pull request in one of the
+	// TestRepos is invalid, instead of only warning about it
+	RequireTestPR *bool `json:"require_test_pr,omitempty"`
+	// ValidationJQL is a JQL filter, e.g. `labels = triaged AND priority is not EMPTY`, that the
+	// bug needs to match to be valid. It allows expressing requirements not covered by the other
+	// options; the bug is searched for by its key combined with the filter
+	ValidationJQL *string `json:"validation_jql,omitempty"`
+	// ValidationJQLMessage is the reason given when the bug does not match ValidationJQL.
+	// Defaults to a generic message, as the filter itself may not be meaningful to users
+	ValidationJQLMessage *string `json:"validation_jql_message,omitempty"`
+	// CustomFields overrides the IDs of the custom fields holding the target version, severity
+	// and QA contact of bugs, for Jira instances where they differ from the defaults
+	CustomFields *helpers.FieldMap `json:"custom_fields,omitempty"`
+	// AccountIDLogins maps the account IDs of Jira users to their GitHub logins. It is used to
+	// request reviews from QA contacts that cannot be resolved through their public email, e.g.
+	// on Jira Cloud, where many users do not list one.
+	AccountIDLogins map[string]string `json:"account_id_logins,omitempty"`
+	// SeverityLabelPrefix replaces the prefix of the severity labels added to pull requests,
+	// e.g. `sev/` to add `sev/critical` instead of `jira/severity-critical`
+	SeverityLabelPrefix *string `json:"severity_label_prefix,omitempty"`
 	// ValidStates determine states in which the bug may be to be valid
 	ValidStates *[]JiraBugState `json:"valid_states,omitempty"`
 
@@ -87,6 +208,45 @@ type JiraBranchOptions struct {
 	// versions for dependent bugs.  If set, all blockers must have a
 	// valid target version.
 	DependentBugTargetVersions *[]string `json:"dependent_bug_target_versions,omitempty"`
+	// RequireDependentPRsMerged determines whether all pull requests linked to a bug's
+	// dependent bugs via the external bug tracker need to be merged to deem the bug valid
+	RequireDependentPRsMerged *bool `json:"require_dependent_prs_merged,omitempty"`
+	// RequireDependentSameComponent determines whether a bug's dependent bugs need to be
+	// filed against the same components as the bug to deem the bug valid, which catches
+	// dependencies that were linked across components by mistake
+	RequireDependentSameComponent *bool `json:"require_dependent_same_component,omitempty"`
+	// RequireDependentsResolved determines whether all of a bug's dependent bugs need to be
+	// resolved to deem the bug valid, regardless of their specific state. It is a simpler
+	// alternative to enumerating the DependentBugStates.
+	RequireDependentsResolved *bool `json:"require_dependents_resolved,omitempty"`
+	// ResolvedStates are the states in which a dependent bug is considered resolved for
+	// RequireDependentsResolved. If unset, dependent bugs are resolved if they have a
+	// resolution or are CLOSED.
+	ResolvedStates *[]JiraBugState `json:"resolved_states,omitempty"`
+	// ShowHasDependents determines whether the informational "bug has dependents" line is listed
+	// in the validations of a bug with dependents when none of the dependent bug validations are
+	// configured. Defaults to true.
+	ShowHasDependents *bool `json:"show_has_dependents,omitempty"`
+	// RequireOriginalBug determines whether a bug needs to be the original bug rather than a
+	// clone of another bug to deem the bug valid. It is meant for the main branch, where fixes
+	// should reference the original bug while backports to release branches reference clones.
+	RequireOriginalBug *bool `json:"require_original_bug,omitempty"`
+	// RequireEnvironmentField determines whether a bug needs to have the environment field
+	// set to deem the bug valid. It is meant for platform-specific branches, where the field
+	// records the affected platform and needs to be triaged before a fix is backported.
+	RequireEnvironmentField *bool `json:"require_environment_field,omitempty"`
+	// RequireQaContact determines whether a bug needs to have a QA contact to deem the bug
+	// valid, for QE teams that gate fixes on a QA contact being assigned before they merge.
+	RequireQaContact *bool `json:"require_qa_contact,omitempty"`
+	// ExpectedProject is the key of the Jira project, e.g. OCPBUGS, that a bug needs to be in
+	// to deem the bug valid. It prevents pull requests from referencing bugs of unrelated
+	// projects that happen to be tracked by the plugin.
+	ExpectedProject *string `json:"expected_project,omitempty"`
+	// RequiredLabels is a list of Jira labels that a bug needs to have, all of them, to be
+	// deemed valid, e.g. to make sure that the bug has been triaged.
+	RequiredLabels []string `json:"required_labels,omitempty"`
+	// ForbiddenLabels is a list of Jira labels that a bug must not have to be deemed valid.
+	ForbiddenLabels []string `json:"forbidden_labels,omitempty"`
 
 	// StateAfterValidation is the state to which the bug will be moved after being
 	// deemed valid and linked to a PR. Will implicitly be considered a part of `ValidStates`
@@ -100,13 +260,86 @@ type JiraBranchOptions struct {
 	// AddExternalLink determines whether the pull request will be added to the Jira
 	// bug using the ExternalBug tracker API after being validated
 	AddExternalLink *bool `json:"add_external_link,omitempty"`
+	// LinkedPullRequestsWarningThreshold is the number of pull requests a bug may already be
+	// linked to before a warning listing them is posted when linking another pull request.
+	// Only applies when AddExternalLink is set.
+	LinkedPullRequestsWarningThreshold *int `json:"linked_pull_requests_warning_threshold,omitempty"`
+	// CommentOnLinkRemoval determines whether a private comment is added to the Jira bug
+	// when the link to a pull request is removed because it was closed without merging.
+	// Only applies when AddExternalLink is set.
+	CommentOnLinkRemoval *bool `json:"comment_on_link_removal,omitempty"`
+	// PublishCheckRun determines whether the outcome of the bug validation is published
+	// as a GitHub check-run on the head commit of the pull request, so that branch
+	// protection can require a valid bug. Pushes publish the last outcome on the new head
+	PublishCheckRun *bool `json:"publish_check_run,omitempty"`
+	// SkipValidityLabels determines whether the valid and invalid bug labels are left
+	// untouched, for repos that rely solely on the check-run from PublishCheckRun
+	SkipValidityLabels *bool `json:"skip_validity_labels,omitempty"`
+	// HideEmptyValidations determines whether the details block listing the validations run
+	// on a valid bug is omitted from the comment when no validations were run.
+	HideEmptyValidations *bool `json:"hide_empty_validations,omitempty"`
+	// MaxListedValidations is the number of validations or invalidity reasons listed for a bug
+	// in a comment before the rest are summarized as "...and N more". Defaults to 50.
+	MaxListedValidations *int `json:"max_listed_validations,omitempty"`
+	// CherrypickCommandOverridesTitle determines whether the bugs given to `/jira cherrypick`
+	// take precedence over a different bug already referenced in the pull request title. By
+	// default, the command is rejected in that case.
+	CherrypickCommandOverridesTitle *bool `json:"cherrypick_command_overrides_title,omitempty"`
+	// CreateBlocksLinkOnClone determines whether a clone created for a cherrypick is linked
+	// to the original bug with a `Blocks` link in addition to the `Cloners` link. Defaults to true.
+	CreateBlocksLinkOnClone *bool `json:"create_blocks_link_on_clone,omitempty"`
+	// MinParentDescriptionLength is the minimum length of the description of a bug being
+	// cloned for a cherrypick. Clones inherit the description, so a warning is added to the
+	// comment when the original bug's description is shorter. It does not block the clone.
+	MinParentDescriptionLength *int `json:"min_parent_description_length,omitempty"`
+	// CloneTargetProject is the key of the Jira project that clones created for a cherrypick
+	// are filed in, for teams tracking backports in a separate project. Defaults to the
+	// project of the original bug.
+	CloneTargetProject *string `json:"clone_target_project,omitempty"`
+	// AllowCherrypickClone determines whether bugs are cloned for cherrypicks to the branch.
+	// Defaults to true, so that bugs are cloned on all branches with a TargetVersion.
+	AllowCherrypickClone *bool `json:"allow_cherrypick_clone,omitempty"`
+	// RequireBackportReady determines whether bugs are only cloned for cherrypicks to the branch
+	// once they are marked as ready for backport, for teams that gate backports on the bug.
+	RequireBackportReady *bool `json:"require_backport_ready,omitempty"`
+	// BackportReadyField is the ID of the custom field holding the ready for backport flag checked
+	// by RequireBackportReady, e.g. `customfield_12345`, as it differs between Jira instances
+	BackportReadyField *string `json:"backport_ready_field,omitempty"`
+	// ValidationTransitionComment is a Go template for a comment added to the bug in Jira when
+	// it is moved to StateAfterValidation, e.g. `Moved to {{.Status}} as it is fixed by {{.PullRequestURL}}`.
+	// The template has access to the Key of the bug, the PullRequestURL and the new Status.
+	// If unset, no comment is added to the bug.
+	ValidationTransitionComment *string `json:"validation_transition_comment,omitempty"`
+	// FieldEditURLTemplate is a Go template for a URL that opens the edit view of a field of
+	// an issue, e.g. `https://jira.example.com/secure/EditIssue!default.jspa?key={{.Key}}#{{.Field}}`.
+	// If set, target version and status validation failures link to the failing field.
+	FieldEditURLTemplate *string `json:"field_edit_url_template,omitempty"`
+	// CommentOncePerState determines whether the plugin only comments on a pull request when
+	// the state of a referenced bug changed since its last comment, even for refreshes. The
+	// states are tracked in a hidden marker in the comments of the plugin.
+	CommentOncePerState *bool `json:"comment_once_per_state,omitempty"`
+	// ReportSeverityChanges determines whether the plugin notes in its comment when the severity
+	// of a referenced bug changed since it was last validated. The severities are tracked in a
+	// hidden marker in the comments of the plugin.
+	ReportSeverityChanges *bool `json:"report_severity_changes,omitempty"`
+	// SlackWebhookURL is the URL of a Slack incoming webhook that is notified, in addition to
+	// the comment on the pull request, whenever a pull request references an invalid bug.
+	SlackWebhookURL *string `json:"slack_webhook_url,omitempty"`
 	// StateAfterMerge is the state to which the bug will be moved after all pull requests
 	// in the external bug tracker have been merged.
 	StateAfterMerge *JiraBugState `json:"state_after_merge,omitempty"`
+	// BlockedStates determine states in which a bug is waiting on more information. Bugs in
+	// one of these states will not be moved to StateAfterMerge, even if all linked pull
+	// requests have merged.
+	BlockedStates *[]JiraBugState `json:"blocked_states,omitempty"`
 	// PreMergeStateAfterMerge is the state to which the bug will be moved after all pull requests
 	// in the external bug tracker have been merged if the PR has the `qe-approved` label and both
 	// the FixVersion and AffectsVersion fields of the bug are set to `premerge`.
 	PreMergeStateAfterMerge *JiraBugState `json:"premerge_state_after_merge,omitempty"`
+	// StateAfterRevert is the state to which the bug will be moved after a pull request that
+	// reverts its fix, titled like `Revert "OCPBUGS-123: ..."`, merges, e.g. to reopen it. If
+	// set, revert pull requests also do not move the bug to the StateAfterValidation.
+	StateAfterRevert *JiraBugState `json:"state_after_revert,omitempty"`
 	// StateAfterClose is the state to which the bug will be moved if all pull requests
 	// in the external bug tracker have been closed.
 	StateAfterClose *JiraBugState `json:"state_after_close,omitempty"`
@@ -114,11 +347,56 @@ type JiraBranchOptions struct {
 	// in the external bug tracker have been close if the PR has the `qe-approved` label and both
 	// the FixVersion and AffectsVersion fields of the bug are set to `premerge`.
 	PreMergeStateAfterClose *JiraBugState `json:"premerge_state_after_close,omitempty"`
+	// StateAfterCloseUnmerged is the state to which the bug will be moved if a pull request
+	// is abandoned, i.e. closed without merging while no other pull request linked to the bug
+	// is open or merged, e.g. to flag that the bug needs attention. Closes of pull requests
+	// that are superseded by another linked pull request leave the bug alone. Takes precedence
+	// over StateAfterClose and PreMergeStateAfterClose.
+	StateAfterCloseUnmerged *JiraBugState `json:"state_after_close_unmerged,omitempty"`
 
 	// AllowedSecurityLevels is a list of the name of jira issue security levels that the jira plugin can
 	// link to in PRs. If an issue has a security level that is not in this list, the jira
 	// plugin will not link the issue to the PR.
 	AllowedSecurityLevels []string `json:"allowed_security_levels,omitempty"`
+	// DeniedSecurityLevels is a list of the name of jira issue security levels that the jira plugin
+	// must not link to in PRs, even if they are allowed by AllowedSecurityLevels. Denied levels always
+	// take precedence over allowed ones.
+	DeniedSecurityLevels []string `json:"denied_security_levels,omitempty"`
+	// SecurityTeam is the GitHub handle of a user or team, e.g. `org/security-team`, that is
+	// cc'd when a pull request references a bug in a security level that is not allowed for
+	// the repo, so that they can triage access to the bug.
+	SecurityTeam *string `json:"security_team,omitempty"`
+	// BlockClosureIfBlocksOpen determines whether the move of a bug to the StateAfterMerge
+	// is deferred while the bug blocks other bugs that are still open.
+	BlockClosureIfBlocksOpen *bool `json:"block_closure_if_blocks_open,omitempty"`
+	// ValidateFixVersionOnMerge determines whether the move of a bug to the StateAfterMerge
+	// is skipped when the fix versions of the bug do not include the target version of the
+	// branch, to catch fix versions that were set incorrectly.
+	ValidateFixVersionOnMerge *bool `json:"validate_fix_version_on_merge,omitempty"`
+	// MergeBoardColumns are the columns of the board with the RequiredBoardID, e.g. `In Progress`
+	// or `In Review`, one of which the bug needs to be in for it to be moved to the StateAfterMerge,
+	// to catch bugs of kanban teams that were never picked up from the backlog.
+	MergeBoardColumns *[]string `json:"merge_board_columns,omitempty"`
+	// CommentOnJiraAtMerge determines whether a comment recording the merge of a pull request
+	// is added to the bugs it references.
+	CommentOnJiraAtMerge *bool `json:"comment_on_jira_at_merge,omitempty"`
+	// MergeCommentVisibility restricts the visibility of the comment added to bugs on merge,
+	// e.g. to a group or role. Defaults to the visibility of the other comments of the plugin.
+	MergeCommentVisibility *jira.CommentVisibility `json:"merge_comment_visibility,omitempty"`
+	// SuspiciousStates determine states that a bug is not expected to be in while a pull request
+	// referencing it is still open, e.g. VERIFIED. Bugs in these states are still validated as
+	// usual, but a warning is added to the comment.
+	SuspiciousStates *[]JiraBugState `json:"suspicious_states,omitempty"`
+	// WarnOnIncompleteSubtasks determines whether a warning is added to the comment for valid
+	// bugs that have subtasks which are not done yet, as the fix may only be partial.
+	WarnOnIncompleteSubtasks *bool `json:"warn_on_incomplete_subtasks,omitempty"`
+	// RecommendCherrypick determines whether guidance recommending the cherrypick flow is added
+	// to the comment when a pull request that is not a cherrypick is opened referencing a bug.
+	// It is meant for release branches, where such pull requests may skip the backport process.
+	RecommendCherrypick *bool `json:"recommend_cherrypick,omitempty"`
+	// CherrypickRecommendation overrides the guidance added when RecommendCherrypick is set,
+	// e.g. to link to the backport process of the repo.
+	CherrypickRecommendation *string `json:"cherrypick_recommendation,omitempty"`
 }
 
 type JiraBugStateSet map[JiraBugState]interface{}
@@ -168,6 +446,8 @@ func (o JiraBranchOptions) matches(other JiraBranchOptions) bool {
 		(o.IsOpen != nil && other.IsOpen != nil && *o.IsOpen == *other.IsOpen)
 	targetReleaseMatch := o.TargetVersion == nil && other.TargetVersion == nil ||
 		(o.TargetVersion != nil && other.TargetVersion != nil && *o.TargetVersion == *other.TargetVersion)
+	targetReleasesMatch := o.TargetVersions == nil && other.TargetVersions == nil ||
+		(o.TargetVersions != nil && other.TargetVersions != nil && sets.NewString(*o.TargetVersions...).Equal(sets.NewString(*other.TargetVersions...)))
 	skipTargetVersionCheckMatch := o.SkipTargetVersionCheck == nil && other.SkipTargetVersionCheck == nil ||
 		(o.SkipTargetVersionCheck != nil && other.SkipTargetVersionCheck != nil && *o.SkipTargetVersionCheck == *other.SkipTargetVersionCheck)
 	bugStatesMatch := o.ValidStates == nil && other.ValidStates == nil ||
@@ -182,7 +462,73 @@ func (o JiraBranchOptions) matches(other JiraBranchOptions) bool {
 		(o.StateAfterMerge != nil && other.StateAfterMerge != nil && *o.StateAfterMerge == *other.StateAfterMerge)
 	preMergestatesAfterMergeMatch := o.PreMergeStateAfterMerge == nil && other.PreMergeStateAfterMerge == nil ||
 		(o.PreMergeStateAfterMerge != nil && other.PreMergeStateAfterMerge != nil && *o.PreMergeStateAfterMerge == *other.PreMergeStateAfterMerge)
-	return validateByDefaultMatch && isOpenMatch && targetReleaseMatch && skipTargetVersionCheckMatch && bugStatesMatch && dependentBugStatesMatch && statesAfterValidationMatch && addExternalLinkMatch && statesAfterMergeMatch && preMergestatesAfterMergeMatch
+	blockedStatesMatch := o.BlockedStates == nil && other.BlockedStates == nil ||
+		(o.BlockedStates != nil && other.BlockedStates != nil && jiraStatesMatch(*o.BlockedStates, *other.BlockedStates))
+	requireDependentPRsMergedMatch := o.RequireDependentPRsMerged == nil && other.RequireDependentPRsMerged == nil ||
+		(o.RequireDependentPRsMerged != nil && other.RequireDependentPRsMerged != nil && *o.RequireDependentPRsMerged == *other.RequireDependentPRsMerged)
+	requireDependentSameComponentMatch := o.RequireDependentSameComponent == nil && other.RequireDependentSameComponent == nil ||
+		(o.RequireDependentSameComponent != nil && other.RequireDependentSameComponent != nil && *o.RequireDependentSameComponent == *other.RequireDependentSameComponent)
+	requireDependentsResolvedMatch := o.RequireDependentsResolved == nil && other.RequireDependentsResolved == nil ||
+		(o.RequireDependentsResolved != nil && other.RequireDependentsResolved != nil && *o.RequireDependentsResolved == *other.RequireDependentsResolved)
+	resolvedStatesMatch := o.ResolvedStates == nil && other.ResolvedStates == nil ||
+		(o.ResolvedStates != nil && other.ResolvedStates != nil && jiraStatesMatch(*o.ResolvedStates, *other.ResolvedStates))
+	requiredBoardIDMatch := o.RequiredBoardID == nil && other.RequiredBoardID == nil ||
+		(o.RequiredBoardID != nil && other.RequiredBoardID != nil && *o.RequiredBoardID == *other.RequiredBoardID)
+	validationJQLMatch := o.ValidationJQL == nil && other.ValidationJQL == nil ||
+		(o.ValidationJQL != nil && other.ValidationJQL != nil && *o.ValidationJQL == *other.ValidationJQL)
+	requireOriginalBugMatch := o.RequireOriginalBug == nil && other.RequireOriginalBug == nil ||
+		(o.RequireOriginalBug != nil && other.RequireOriginalBug != nil && *o.RequireOriginalBug == *other.RequireOriginalBug)
+	requireEnvironmentFieldMatch := o.RequireEnvironmentField == nil && other.RequireEnvironmentField == nil ||
+		(o.RequireEnvironmentField != nil && other.RequireEnvironmentField != nil && *o.RequireEnvironmentField == *other.RequireEnvironmentField)
+	requireQaContactMatch := o.RequireQaContact == nil && other.RequireQaContact == nil ||
+		(o.RequireQaContact != nil && other.RequireQaContact != nil && *o.RequireQaContact == *other.RequireQaContact)
+	expectedProjectMatch := o.ExpectedProject == nil && other.ExpectedProject == nil ||
+		(o.ExpectedProject != nil && other.ExpectedProject != nil && *o.ExpectedProject == *other.ExpectedProject)
+	requiredLabelsMatch := sets.NewString(o.RequiredLabels...).Equal(sets.NewString(other.RequiredLabels...))
+	forbiddenLabelsMatch := sets.NewString(o.ForbiddenLabels...).Equal(sets.NewString(other.ForbiddenLabels...))
+	testReposMatch := o.TestRepos == nil && other.TestRepos == nil ||
+		(o.TestRepos != nil && other.TestRepos != nil && sets.NewString(*o.TestRepos...).Equal(sets.NewString(*other.TestRepos...)))
+	requireTestPRMatch := o.RequireTestPR == nil && other.RequireTestPR == nil ||
+		(o.RequireTestPR != nil && other.RequireTestPR != nil && *o.RequireTestPR == *other.RequireTestPR)
+	requireKnownTargetVersionMatch := o.RequireKnownTargetVersion == nil && other.RequireKnownTargetVersion == nil ||
+		(o.RequireKnownTargetVersion != nil && other.RequireKnownTargetVersion != nil && *o.RequireKnownTargetVersion == *other.RequireKnownTargetVersion)
+	knownTargetVersionsMatch := o.KnownTargetVersions == nil && other.KnownTargetVersions == nil ||
+		(o.KnownTargetVersions != nil && other.KnownTargetVersions != nil && sets.NewString(*o.KnownTargetVersions...).Equal(sets.NewString(*other.KnownTargetVersions...)))
+	validateBranchTargetConsistencyMatch := o.ValidateBranchTargetConsistency == nil && other.ValidateBranchTargetConsistency == nil ||
+		(o.ValidateBranchTargetConsistency != nil && other.ValidateBranchTargetConsistency != nil && *o.ValidateBranchTargetConsistency == *other.ValidateBranchTargetConsistency)
+	branchTargetVersionsMatch := len(o.BranchTargetVersions) == 0 && len(other.BranchTargetVersions) == 0 || reflect.DeepEqual(o.BranchTargetVersions, other.BranchTargetVersions)
+	issueTypeBranchesMatch := len(o.IssueTypeBranches) == 0 && len(other.IssueTypeBranches) == 0 || reflect.DeepEqual(o.IssueTypeBranches, other.IssueTypeBranches)
+	allowedReportersMatch := sets.NewString(o.AllowedReporters...).Equal(sets.NewString(other.AllowedReporters...))
+	rejectResolutionsMatch := sets.NewString(o.RejectResolutions...).Equal(sets.NewString(other.RejectResolutions...))
+	requireVerificationFieldMatch := o.RequireVerificationField == nil && other.RequireVerificationField == nil ||
+		(o.RequireVerificationField != nil && other.RequireVerificationField != nil && *o.RequireVerificationField == *other.RequireVerificationField)
+	requireReleaseNoteTypeMatch := o.RequireReleaseNoteType == nil && other.RequireReleaseNoteType == nil ||
+		(o.RequireReleaseNoteType != nil && other.RequireReleaseNoteType != nil && *o.RequireReleaseNoteType == *other.RequireReleaseNoteType)
+	requireStoryPointsMatch := o.RequireStoryPoints == nil && other.RequireStoryPoints == nil ||
+		(o.RequireStoryPoints != nil && other.RequireStoryPoints != nil && *o.RequireStoryPoints == *other.RequireStoryPoints)
+	rejectFlaggedBlockedMatch := o.RejectFlaggedBlocked == nil && other.RejectFlaggedBlocked == nil ||
+		(o.RejectFlaggedBlocked != nil && other.RejectFlaggedBlocked != nil && *o.RejectFlaggedBlocked == *other.RejectFlaggedBlocked)
+	return validateByDefaultMatch && isOpenMatch && targetReleaseMatch && targetReleasesMatch && skipTargetVersionCheckMatch && bugStatesMatch && dependentBugStatesMatch && statesAfterValidationMatch && addExternalLinkMatch && statesAfterMergeMatch && preMergestatesAfterMergeMatch && blockedStatesMatch && requireDependentPRsMergedMatch && requireDependentSameComponentMatch && requireDependentsResolvedMatch && resolvedStatesMatch && requiredBoardIDMatch && validationJQLMatch && requireOriginalBugMatch && requireEnvironmentFieldMatch && requireQaContactMatch && expectedProjectMatch && requiredLabelsMatch && forbiddenLabelsMatch && testReposMatch && requireTestPRMatch &&
+		requireKnownTargetVersionMatch && knownTargetVersionsMatch && validateBranchTargetConsistencyMatch && branchTargetVersionsMatch && issueTypeBranchesMatch && allowedReportersMatch && rejectResolutionsMatch &&
+		requireVerificationFieldMatch && requireReleaseNoteTypeMatch && requireStoryPointsMatch && rejectFlaggedBlockedMatch
+}
+
+// customFields returns the IDs of the custom fields to read from bugs, with defaults set for
+// the fields that are not configured
+func (o JiraBranchOptions) customFields() helpers.FieldMap {
+	if o.CustomFields == nil {
+		return helpers.FieldMap{}.WithDefaults()
+	}
+	return o.CustomFields.WithDefaults()
+}
+
+// severityLabel returns the given severity label, e.g. labels.SeverityCritical, with the
+// configured SeverityLabelPrefix
+func (o JiraBranchOptions) severityLabel(label string) string {
+	if label == "" || o.SeverityLabelPrefix == nil || *o.SeverityLabelPrefix == "" {
+		return label
+	}
+	return *o.SeverityLabelPrefix + strings.TrimPrefix(label, labels.SeverityPrefix)
 }
 
 const JiraOptionsWildcard = `*`
@@ -214,9 +560,96 @@ func ResolveJiraOptions(parent, child JiraBranchOptions) JiraBranchOptions {
 		if parent.TargetVersion != nil {
 			output.TargetVersion = parent.TargetVersion
 		}
+		if parent.TargetVersions != nil {
+			output.TargetVersions = parent.TargetVersions
+		}
 		if parent.SkipTargetVersionCheck != nil {
 			output.SkipTargetVersionCheck = parent.SkipTargetVersionCheck
 		}
+		if parent.RequireKnownTargetVersion != nil {
+			output.RequireKnownTargetVersion = parent.RequireKnownTargetVersion
+		}
+		if parent.KnownTargetVersions != nil {
+			output.KnownTargetVersions = parent.KnownTargetVersions
+		}
+		if parent.ValidateBranchTargetConsistency != nil {
+			output.ValidateBranchTargetConsistency = parent.ValidateBranchTargetConsistency
+		}
+		if parent.BranchTargetVersions != nil {
+			output.BranchTargetVersions = mergeStringMaps(output.BranchTargetVersions, parent.BranchTargetVersions)
+		}
+		if parent.AccountIDLogins != nil {
+			output.AccountIDLogins = mergeStringMaps(output.AccountIDLogins, parent.AccountIDLogins)
+		}
+		if parent.MergeRepos != nil {
+			output.MergeRepos = mergeMergeRepos(output.MergeRepos, parent.MergeRepos)
+		}
+		if parent.IssueTypeBranches != nil {
+			output.IssueTypeBranches = mergeIssueTypeBranches(output.IssueTypeBranches, parent.IssueTypeBranches)
+		}
+		if parent.AllowedReporters != nil {
+			output.AllowedReporters = sets.NewString(output.AllowedReporters...).Insert(parent.AllowedReporters...).List()
+		}
+		if parent.RejectResolutions != nil {
+			output.RejectResolutions = sets.NewString(output.RejectResolutions...).Insert(parent.RejectResolutions...).List()
+		}
+		if parent.RejectIfAlreadyFixedInTarget != nil {
+			output.RejectIfAlreadyFixedInTarget = parent.RejectIfAlreadyFixedInTarget
+		}
+		if parent.RetitleCommand != nil {
+			output.RetitleCommand = parent.RetitleCommand
+		}
+		if parent.RequiredBoardID != nil {
+			output.RequiredBoardID = parent.RequiredBoardID
+		}
+		if parent.RequireVerificationField != nil {
+			output.RequireVerificationField = parent.RequireVerificationField
+		}
+		if parent.VerificationField != nil {
+			output.VerificationField = parent.VerificationField
+		}
+		if parent.RequireReleaseNoteType != nil {
+			output.RequireReleaseNoteType = parent.RequireReleaseNoteType
+		}
+		if parent.ReleaseNoteTypeField != nil {
+			output.ReleaseNoteTypeField = parent.ReleaseNoteTypeField
+		}
+		if parent.RequireStoryPoints != nil {
+			output.RequireStoryPoints = parent.RequireStoryPoints
+		}
+		if parent.StoryPointsField != nil {
+			output.StoryPointsField = parent.StoryPointsField
+		}
+		if parent.RejectFlaggedBlocked != nil {
+			output.RejectFlaggedBlocked = parent.RejectFlaggedBlocked
+		}
+		if parent.FlaggedField != nil {
+			output.FlaggedField = parent.FlaggedField
+		}
+		if parent.ContributorsField != nil {
+			output.ContributorsField = parent.ContributorsField
+		}
+		if parent.RequireAuthorInContributors != nil {
+			output.RequireAuthorInContributors = parent.RequireAuthorInContributors
+		}
+		if parent.TestRepos != nil {
+			output.TestRepos = parent.TestRepos
+		}
+		if parent.RequireTestPR != nil {
+			output.RequireTestPR = parent.RequireTestPR
+		}
+		if parent.ValidationJQL != nil {
+			output.ValidationJQL = parent.ValidationJQL
+		}
+		if parent.CustomFields != nil {
+			output.CustomFields = parent.CustomFields
+		}
+		if parent.SeverityLabelPrefix != nil {
+			output.SeverityLabelPrefix = parent.SeverityLabelPrefix
+		}
+		if parent.ValidationJQLMessage != nil {
+			output.ValidationJQLMessage = parent.ValidationJQLMessage
+		}
 		if parent.ValidStates != nil {
 			output.ValidStates = parent.ValidStates
 		}
@@ -226,6 +659,39 @@ func ResolveJiraOptions(parent, child JiraBranchOptions) JiraBranchOptions {
 		if parent.DependentBugTargetVersions != nil {
 			output.DependentBugTargetVersions = parent.DependentBugTargetVersions
 		}
+		if parent.RequireDependentPRsMerged != nil {
+			output.RequireDependentPRsMerged = parent.RequireDependentPRsMerged
+		}
+		if parent.RequireDependentSameComponent != nil {
+			output.RequireDependentSameComponent = parent.RequireDependentSameComponent
+		}
+		if parent.RequireDependentsResolved != nil {
+			output.RequireDependentsResolved = parent.RequireDependentsResolved
+		}
+		if parent.ResolvedStates != nil {
+			output.ResolvedStates = parent.ResolvedStates
+		}
+		if parent.ShowHasDependents != nil {
+			output.ShowHasDependents = parent.ShowHasDependents
+		}
+		if parent.RequireOriginalBug != nil {
+			output.RequireOriginalBug = parent.RequireOriginalBug
+		}
+		if parent.RequireEnvironmentField != nil {
+			output.RequireEnvironmentField = parent.RequireEnvironmentField
+		}
+		if parent.RequireQaContact != nil {
+			output.RequireQaContact = parent.RequireQaContact
+		}
+		if parent.ExpectedProject != nil {
+			output.ExpectedProject = parent.ExpectedProject
+		}
+		if parent.RequiredLabels != nil {
+			output.RequiredLabels = sets.NewString(output.RequiredLabels...).Insert(parent.RequiredLabels...).List()
+		}
+		if parent.ForbiddenLabels != nil {
+			output.ForbiddenLabels = sets.NewString(output.ForbiddenLabels...).Insert(parent.ForbiddenLabels...).List()
+		}
 		if parent.StateAfterValidation != nil {
 			output.StateAfterValidation = parent.StateAfterValidation
 		}
@@ -235,21 +701,117 @@ func ResolveJiraOptions(parent, child JiraBranchOptions) JiraBranchOptions {
 		if parent.AddExternalLink != nil {
 			output.AddExternalLink = parent.AddExternalLink
 		}
+		if parent.LinkedPullRequestsWarningThreshold != nil {
+			output.LinkedPullRequestsWarningThreshold = parent.LinkedPullRequestsWarningThreshold
+		}
+		if parent.CommentOnLinkRemoval != nil {
+			output.CommentOnLinkRemoval = parent.CommentOnLinkRemoval
+		}
+		if parent.PublishCheckRun != nil {
+			output.PublishCheckRun = parent.PublishCheckRun
+		}
+		if parent.SkipValidityLabels != nil {
+			output.SkipValidityLabels = parent.SkipValidityLabels
+		}
+		if parent.HideEmptyValidations != nil {
+			output.HideEmptyValidations = parent.HideEmptyValidations
+		}
+		if parent.MaxListedValidations != nil {
+			output.MaxListedValidations = parent.MaxListedValidations
+		}
+		if parent.CherrypickCommandOverridesTitle != nil {
+			output.CherrypickCommandOverridesTitle = parent.CherrypickCommandOverridesTitle
+		}
+		if parent.CreateBlocksLinkOnClone != nil {
+			output.CreateBlocksLinkOnClone = parent.CreateBlocksLinkOnClone
+		}
+		if parent.MinParentDescriptionLength != nil {
+			output.MinParentDescriptionLength = parent.MinParentDescriptionLength
+		}
+		if parent.CloneTargetProject != nil {
+			output.CloneTargetProject = parent.CloneTargetProject
+		}
+		if parent.AllowCherrypickClone != nil {
+			output.AllowCherrypickClone = parent.AllowCherrypickClone
+		}
+		if parent.RequireBackportReady != nil {
+			output.RequireBackportReady = parent.RequireBackportReady
+		}
+		if parent.BackportReadyField != nil {
+			output.BackportReadyField = parent.BackportReadyField
+		}
+		if parent.FieldEditURLTemplate != nil {
+			output.FieldEditURLTemplate = parent.FieldEditURLTemplate
+		}
+		if parent.ValidationTransitionComment != nil {
+			output.ValidationTransitionComment = parent.ValidationTransitionComment
+		}
+		if parent.CommentOncePerState != nil {
+			output.CommentOncePerState = parent.CommentOncePerState
+		}
+		if parent.ReportSeverityChanges != nil {
+			output.ReportSeverityChanges = parent.ReportSeverityChanges
+		}
+		if parent.SlackWebhookURL != nil {
+			output.SlackWebhookURL = parent.SlackWebhookURL
+		}
 		if parent.StateAfterMerge != nil {
 			output.StateAfterMerge = parent.StateAfterMerge
 		}
+		if parent.BlockedStates != nil {
+			output.BlockedStates = parent.BlockedStates
+		}
 		if parent.PreMergeStateAfterMerge != nil {
 			output.PreMergeStateAfterMerge = parent.PreMergeStateAfterMerge
 		}
+		if parent.StateAfterRevert != nil {
+			output.StateAfterRevert = parent.StateAfterRevert
+		}
 		if parent.StateAfterClose != nil {
 			output.StateAfterClose = parent.StateAfterClose
 		}
 		if parent.PreMergeStateAfterClose != nil {
 			output.PreMergeStateAfterClose = parent.PreMergeStateAfterClose
 		}
+		if parent.StateAfterCloseUnmerged != nil {
+			output.StateAfterCloseUnmerged = parent.StateAfterCloseUnmerged
+		}
 		if parent.AllowedSecurityLevels != nil {
 			output.AllowedSecurityLevels = sets.NewString(output.AllowedSecurityLevels...).Insert(parent.AllowedSecurityLevels...).List()
 		}
+		if parent.DeniedSecurityLevels != nil {
+			output.DeniedSecurityLevels = sets.NewString(output.DeniedSecurityLevels...).Insert(parent.DeniedSecurityLevels...).List()
+		}
+		if parent.SecurityTeam != nil {
+			output.SecurityTeam = parent.SecurityTeam
+		}
+		if parent.BlockClosureIfBlocksOpen != nil {
+			output.BlockClosureIfBlocksOpen = parent.BlockClosureIfBlocksOpen
+		}
+		if parent.ValidateFixVersionOnMerge != nil {
+			output.ValidateFixVersionOnMerge = parent.ValidateFixVersionOnMerge
+		}
+		if parent.MergeBoardColumns != nil {
+			output.MergeBoardColumns = parent.MergeBoardColumns
+		}
+		if parent.CommentOnJiraAtMerge != nil {
+			output.CommentOnJiraAtMerge = parent.CommentOnJiraAtMerge
+		}
+		if parent.MergeCommentVisibility != nil {
+			output.MergeCommentVisibility = parent.MergeCommentVisibility
+		}
+		if parent.SuspiciousStates != nil {
+			output.SuspiciousStates = parent.SuspiciousStates
+		}
+		if parent.WarnOnIncompleteSubtasks != nil {
+			output.WarnOnIncompleteSubtasks = parent.WarnOnIncompleteSubtasks
+		}
+		if parent.RecommendCherrypick != nil {
+			output.RecommendCherrypick = parent.RecommendCherrypick
+		}
+		if parent.CherrypickRecommendation != nil {
+			output.CherrypickRecommendation = parent.CherrypickRecommendation
+		}
 	}
 
 	// override with the child
@@ -265,10 +827,97 @@ func ResolveJiraOptions(parent, child JiraBranchOptions) JiraBranchOptions {
 	if child.TargetVersion != nil {
 		output.TargetVersion = child.TargetVersion
 	}
+	if child.TargetVersions != nil {
+		output.TargetVersions = child.TargetVersions
+	}
 	if child.SkipTargetVersionCheck != nil {
 		output.SkipTargetVersionCheck = child.SkipTargetVersionCheck
 	}
+	if child.RequireKnownTargetVersion != nil {
+		output.RequireKnownTargetVersion = child.RequireKnownTargetVersion
+	}
+	if child.KnownTargetVersions != nil {
+		output.KnownTargetVersions = child.KnownTargetVersions
+	}
+	if child.ValidateBranchTargetConsistency != nil {
+		output.ValidateBranchTargetConsistency = child.ValidateBranchTargetConsistency
+	}
+	if child.BranchTargetVersions != nil {
+		output.BranchTargetVersions = mergeStringMaps(output.BranchTargetVersions, child.BranchTargetVersions)
+	}
+	if child.AccountIDLogins != nil {
+		output.AccountIDLogins = mergeStringMaps(output.AccountIDLogins, child.AccountIDLogins)
+	}
+	if child.MergeRepos != nil {
+		output.MergeRepos = mergeMergeRepos(output.MergeRepos, child.MergeRepos)
+	}
+	if child.IssueTypeBranches != nil {
+		output.IssueTypeBranches = mergeIssueTypeBranches(output.IssueTypeBranches, child.IssueTypeBranches)
+	}
 
+	if child.AllowedReporters != nil {
+		output.AllowedReporters = sets.NewString(output.AllowedReporters...).Insert(child.AllowedReporters...).List()
+	}
+	if child.RejectResolutions != nil {
+		output.RejectResolutions = sets.NewString(output.RejectResolutions...).Insert(child.RejectResolutions...).List()
+	}
+	if child.RejectIfAlreadyFixedInTarget != nil {
+		output.RejectIfAlreadyFixedInTarget = child.RejectIfAlreadyFixedInTarget
+	}
+	if child.RetitleCommand != nil {
+		output.RetitleCommand = child.RetitleCommand
+	}
+	if child.RequiredBoardID != nil {
+		output.RequiredBoardID = child.RequiredBoardID
+	}
+	if child.RequireVerificationField != nil {
+		output.RequireVerificationField = child.RequireVerificationField
+	}
+	if child.VerificationField != nil {
+		output.VerificationField = child.VerificationField
+	}
+	if child.RequireReleaseNoteType != nil {
+		output.RequireReleaseNoteType = child.RequireReleaseNoteType
+	}
+	if child.ReleaseNoteTypeField != nil {
+		output.ReleaseNoteTypeField = child.ReleaseNoteTypeField
+	}
+	if child.RequireStoryPoints != nil {
+		output.RequireStoryPoints = child.RequireStoryPoints
+	}
+	if child.StoryPointsField != nil {
+		output.StoryPointsField = child.StoryPointsField
+	}
+	if child.RejectFlaggedBlocked != nil {
+		output.RejectFlaggedBlocked = child.RejectFlaggedBlocked
+	}
+	if child.FlaggedField != nil {
+		output.FlaggedField = child.FlaggedField
+	}
+	if child.ContributorsField != nil {
+		output.ContributorsField = child.ContributorsField
+	}
+	if child.RequireAuthorInContributors != nil {
+		output.RequireAuthorInContributors = child.RequireAuthorInContributors
+	}
+	if child.TestRepos != nil {
+		output.TestRepos = child.TestRepos
+	}
+	if child.RequireTestPR != nil {
+		output.RequireTestPR = child.RequireTestPR
+	}
+	if child.ValidationJQL != nil {
+		output.ValidationJQL = child.ValidationJQL
+	}
+	if child.CustomFields != nil {
+		output.CustomFields = child.CustomFields
+	}
+	if child.SeverityLabelPrefix != nil {
+		output.SeverityLabelPrefix = child.SeverityLabelPrefix
+	}
+	if child.ValidationJQLMessage != nil {
+		output.ValidationJQLMessage = child.ValidationJQLMessage
+	}
 	if child.ValidStates != nil {
 		output.ValidStates = child.ValidStates
 	}
@@ -279,6 +928,39 @@ func ResolveJiraOptions(parent, child JiraBranchOptions) JiraBranchOptions {
 	if child.DependentBugTargetVersions != nil {
 		output.DependentBugTargetVersions = child.DependentBugTargetVersions
 	}
+	if child.RequireDependentPRsMerged != nil {
+		output.RequireDependentPRsMerged = child.RequireDependentPRsMerged
+	}
+	if child.RequireDependentSameComponent != nil {
+		output.RequireDependentSameComponent = child.RequireDependentSameComponent
+	}
+	if child.RequireDependentsResolved != nil {
+		output.RequireDependentsResolved = child.RequireDependentsResolved
+	}
+	if child.ResolvedStates != nil {
+		output.ResolvedStates = child.ResolvedStates
+	}
+	if child.ShowHasDependents != nil {
+		output.ShowHasDependents = child.ShowHasDependents
+	}
+	if child.RequireOriginalBug != nil {
+		output.RequireOriginalBug = child.RequireOriginalBug
+	}
+	if child.RequireEnvironmentField != nil {
+		output.RequireEnvironmentField = child.RequireEnvironmentField
+	}
+	if child.RequireQaContact != nil {
+		output.RequireQaContact = child.RequireQaContact
+	}
+	if child.ExpectedProject != nil {
+		output.ExpectedProject = child.ExpectedProject
+	}
+	if child.RequiredLabels != nil {
+		output.RequiredLabels = sets.NewString(output.RequiredLabels...).Insert(child.RequiredLabels...).List()
+	}
+	if child.ForbiddenLabels != nil {
+		output.ForbiddenLabels = sets.NewString(output.ForbiddenLabels...).Insert(child.ForbiddenLabels...).List()
+	}
 	if child.StateAfterValidation != nil {
 		output.StateAfterValidation = child.StateAfterValidation
 	}
@@ -288,25 +970,160 @@ func ResolveJiraOptions(parent, child JiraBranchOptions) JiraBranchOptions {
 	if child.AddExternalLink != nil {
 		output.AddExternalLink = child.AddExternalLink
 	}
+	if child.LinkedPullRequestsWarningThreshold != nil {
+		output.LinkedPullRequestsWarningThreshold = child.LinkedPullRequestsWarningThreshold
+	}
+	if child.CommentOnLinkRemoval != nil {
+		output.CommentOnLinkRemoval = child.CommentOnLinkRemoval
+	}
+	if child.PublishCheckRun != nil {
+		output.PublishCheckRun = child.PublishCheckRun
+	}
+	if child.SkipValidityLabels != nil {
+		output.SkipValidityLabels = child.SkipValidityLabels
+	}
+	if child.HideEmptyValidations != nil {
+		output.HideEmptyValidations = child.HideEmptyValidations
+	}
+	if child.MaxListedValidations != nil {
+		output.MaxListedValidations = child.MaxListedValidations
+	}
+	if child.CherrypickCommandOverridesTitle != nil {
+		output.CherrypickCommandOverridesTitle = child.CherrypickCommandOverridesTitle
+	}
+	if child.CreateBlocksLinkOnClone != nil {
+		output.CreateBlocksLinkOnClone = child.CreateBlocksLinkOnClone
+	}
+	if child.MinParentDescriptionLength != nil {
+		output.MinParentDescriptionLength = child.MinParentDescriptionLength
+	}
+	if child.CloneTargetProject != nil {
+		output.CloneTargetProject = child.CloneTargetProject
+	}
+	if child.AllowCherrypickClone != nil {
+		output.AllowCherrypickClone = child.AllowCherrypickClone
+	}
+	if child.RequireBackportReady != nil {
+		output.RequireBackportReady = child.RequireBackportReady
+	}
+	if child.BackportReadyField != nil {
+		output.BackportReadyField = child.BackportReadyField
+	}
+	if child.FieldEditURLTemplate != nil {
+		output.FieldEditURLTemplate = child.FieldEditURLTemplate
+	}
+	if child.ValidationTransitionComment != nil {
+		output.ValidationTransitionComment = child.ValidationTransitionComment
+	}
+	if child.CommentOncePerState != nil {
+		output.CommentOncePerState = child.CommentOncePerState
+	}
+	if child.ReportSeverityChanges != nil {
+		output.ReportSeverityChanges = child.ReportSeverityChanges
+	}
+	if child.SlackWebhookURL != nil {
+		output.SlackWebhookURL = child.SlackWebhookURL
+	}
 	if child.StateAfterMerge != nil {
 		output.StateAfterMerge = child.StateAfterMerge
 	}
+	if child.BlockedStates != nil {
+		output.BlockedStates = child.BlockedStates
+	}
 	if child.PreMergeStateAfterMerge != nil {
 		output.PreMergeStateAfterMerge = child.PreMergeStateAfterMerge
 	}
+	if child.StateAfterRevert != nil {
+		output.StateAfterRevert = child.StateAfterRevert
+	}
 	if child.StateAfterClose != nil {
 		output.StateAfterClose = child.StateAfterClose
 	}
 	if child.PreMergeStateAfterClose != nil {
 		output.PreMergeStateAfterClose = child.PreMergeStateAfterClose
 	}
+	if child.StateAfterCloseUnmerged != nil {
+		output.StateAfterCloseUnmerged = child.StateAfterCloseUnmerged
+	}
 	if child.AllowedSecurityLevels != nil {
 		output.AllowedSecurityLevels = sets.NewString(output.AllowedSecurityLevels...).Insert(child.AllowedSecurityLevels...).List()
 	}
+	if child.DeniedSecurityLevels != nil {
+		output.DeniedSecurityLevels = sets.NewString(output.DeniedSecurityLevels...).Insert(child.DeniedSecurityLevels...).List()
+	}
+	if child.SecurityTeam != nil {
+		output.SecurityTeam = child.SecurityTeam
+	}
+	if child.BlockClosureIfBlocksOpen != nil {
+		output.BlockClosureIfBlocksOpen = child.BlockClosureIfBlocksOpen
+	}
+	if child.ValidateFixVersionOnMerge != nil {
+		output.ValidateFixVersionOnMerge = child.ValidateFixVersionOnMerge
+	}
+	if child.MergeBoardColumns != nil {
+		output.MergeBoardColumns = child.MergeBoardColumns
+	}
+	if child.CommentOnJiraAtMerge != nil {
+		output.CommentOnJiraAtMerge = child.CommentOnJiraAtMerge
+	}
+	if child.MergeCommentVisibility != nil {
+		output.MergeCommentVisibility = child.MergeCommentVisibility
+	}
+	if child.SuspiciousStates != nil {
+		output.SuspiciousStates = child.SuspiciousStates
+	}
+	if child.WarnOnIncompleteSubtasks != nil {
+		output.WarnOnIncompleteSubtasks = child.WarnOnIncompleteSubtasks
+	}
+	if child.RecommendCherrypick != nil {
+		output.RecommendCherrypick = child.RecommendCherrypick
+	}
+	if child.CherrypickRecommendation != nil {
+		output.CherrypickRecommendation = child.CherrypickRecommendation
+	}
 
 	return output
 }
 
+// mergeStringMaps returns a copy of the base mapping with the entries of the override mapping
+// added, preferring the override's value for keys present in both.
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return merged
+}
+
+// mergeMergeRepos returns a copy of the base mapping with the repos of the override mapping
+// added to the repos of the same project.
+func mergeMergeRepos(base, override map[string][]string) map[string][]string {
+	merged := make(map[string][]string, len(base)+len(override))
+	for project, repos := range base {
+		merged[project] = sets.NewString(repos...).List()
+	}
+	for project, repos := range override {
+		merged[project] = sets.NewString(merged[project]...).Insert(repos...).List()
+	}
+	return merged
+}
+
+// mergeIssueTypeBranches returns a copy of the base mapping with the entries of the override
+// mapping added, replacing the branch patterns of issue types present in both.
+func mergeIssueTypeBranches(base, override map[string][]string) map[string][]string {
+	merged := make(map[string][]string, len(base)+len(override))
+	for issueType, patterns := range base {
+		merged[issueType] = patterns
+	}
+	for issueType, patterns := range override {
+		merged[issueType] = patterns
+	}
+	return merged
+}
+
 // OptionsForBranch determines the criteria for a valid Jira bug on a branch of a repo
 // by defaulting in a cascading way, in the following order (later entries override earlier
 // ones), always searching for the wildcard as well as the branch name: global, then org,
@@ -328,6 +1145,43 @@ func (b *Config) OptionsForBranch(org, repo, branch string) JiraBranchOptions {
 	return options
 }
 
+// JiraURLForOrg returns the Jira URL that should be used when rendering links to
+// issues for the org, or an empty string if the org does not override it.
+func (b *Config) JiraURLForOrg(org string) string {
+	orgOptions, exists := b.Orgs[org]
+	if !exists {
+		return ""
+	}
+	return strings.TrimSuffix(orgOptions.JiraURL, "/")
+}
+
+// PrivilegedCommandAllowlist returns the GitHub logins and team slugs allowed to run
+// privileged commands in a repo, including those configured for the `*` wildcard repo.
+// Both are empty if no allowlist is configured for the repo.
+func (b *Config) PrivilegedCommandAllowlist(org, repo string) (users, teams sets.String) {
+	users, teams = sets.NewString(), sets.NewString()
+	for _, name := range []string{JiraOptionsWildcard, repo} {
+		if repoOptions, ok := b.Orgs[org].Repos[name]; ok {
+			users.Insert(repoOptions.PrivilegedCommandUsers...)
+			teams.Insert(repoOptions.PrivilegedCommandTeams...)
+		}
+	}
+	return users, teams
+}
+
+// IsExemptBot determines whether pull requests authored by the login are exempt from the Jira
+// requirements of a repo, including the bots configured for the `*` wildcard repo.
+func (b *Config) IsExemptBot(org, repo, login string) bool {
+	for _, name := range []string{JiraOptionsWildcard, repo} {
+		for _, bot := range b.Orgs[org].Repos[name].ExemptBotLogins {
+			if github.NormLogin(bot) == github.NormLogin(login) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // OptionsForRepo determines the criteria for a valid Jira bug on branches of a repo
 // by defaulting in a cascading way, in the following order (later entries override earlier
 // ones), always searching for the wildcard as well as the branch name: global, then org,
@@ -252,6 +252,36 @@ func TestResolveJiraOptions(t *testing.T) {
 			child:    JiraBranchOptions{TargetVersion: &one},
 			expected: JiraBranchOptions{DependentBugTargetVersions: &[]string{one}, TargetVersion: &one, ExcludeDefaults: &yes},
 		},
+		{
+			name:     "child overrides parent on blocked states",
+			parent:   JiraBranchOptions{StateAfterMerge: &postState, BlockedStates: &[]JiraBugState{modifiedState}},
+			child:    JiraBranchOptions{BlockedStates: &[]JiraBugState{verifiedState}},
+			expected: JiraBranchOptions{StateAfterMerge: &postState, BlockedStates: &[]JiraBugState{verifiedState}},
+		},
+		{
+			name:     "parent and child branch target versions are merged, preferring the child",
+			parent:   JiraBranchOptions{ValidateBranchTargetConsistency: &yes, BranchTargetVersions: map[string]string{"master": "4.13", "release-4.12": "4.12"}},
+			child:    JiraBranchOptions{BranchTargetVersions: map[string]string{"master": "4.14", "release-4.13": "4.13"}},
+			expected: JiraBranchOptions{ValidateBranchTargetConsistency: &yes, BranchTargetVersions: map[string]string{"master": "4.14", "release-4.13": "4.13", "release-4.12": "4.12"}},
+		},
+		{
+			name:     "parent and child merge repos are merged per project",
+			parent:   JiraBranchOptions{MergeRepos: map[string][]string{"OCPBUGS": {"org/a"}, "OTHER": {"org/c"}}},
+			child:    JiraBranchOptions{MergeRepos: map[string][]string{"OCPBUGS": {"org/b"}}},
+			expected: JiraBranchOptions{MergeRepos: map[string][]string{"OCPBUGS": {"org/a", "org/b"}, "OTHER": {"org/c"}}},
+		},
+		{
+			name:     "parent and child allowed reporters are merged",
+			parent:   JiraBranchOptions{AllowedReporters: []string{"bob"}},
+			child:    JiraBranchOptions{AllowedReporters: []string{"alice"}},
+			expected: JiraBranchOptions{AllowedReporters: []string{"alice", "bob"}},
+		},
+		{
+			name:     "parent and child denied security levels are merged",
+			parent:   JiraBranchOptions{AllowedSecurityLevels: []string{"public"}, DeniedSecurityLevels: []string{"embargoed"}},
+			child:    JiraBranchOptions{DeniedSecurityLevels: []string{"internal"}},
+			expected: JiraBranchOptions{AllowedSecurityLevels: []string{"public"}, DeniedSecurityLevels: []string{"embargoed", "internal"}},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/andygrunwald/go-jira"
+
+	"k8s.io/test-infra/prow/github"
+	jiraclient "k8s.io/test-infra/prow/jira"
+)
+
+// healthChecker reports whether the plugin is able to verify webhook payloads and
+// authenticate against Jira and GitHub.
+type healthChecker struct {
+	// hmacSecret returns the secret used to verify the signature of webhook payloads
+	hmacSecret func() []byte
+	// jiraAuth and githubAuth make a lightweight authenticated call to the respective service
+	jiraAuth   func() error
+	githubAuth func() error
+}
+
+// healthStatus is the result of a health check, served as JSON from the health endpoint
+type healthStatus struct {
+	HMACSecretLoaded    bool   `json:"hmac_secret_loaded"`
+	JiraAuthenticated   bool   `json:"jira_authenticated"`
+	JiraError           string `json:"jira_error,omitempty"`
+	GitHubAuthenticated bool   `json:"github_authenticated"`
+	GitHubError         string `json:"github_error,omitempty"`
+}
+
+func (s healthStatus) healthy() bool {
+	return s.HMACSecretLoaded && s.JiraAuthenticated && s.GitHubAuthenticated
+}
+
+func newHealthChecker(hmacSecret func() []byte, jc jiraclient.Client, ghc github.Client) *healthChecker {
+	return &healthChecker{
+		hmacSecret: hmacSecret,
+		jiraAuth: func() error {
+			_, resp, err := jc.JiraClient().User.GetSelf()
+			if err != nil {
+				return jira.NewJiraError(resp, err)
+			}
+			return nil
+		},
+		githubAuth: func() error {
+			_, err := ghc.BotUser()
+			return err
+		},
+	}
+}
+
+func (h *healthChecker) status() healthStatus {
+	status := healthStatus{HMACSecretLoaded: len(h.hmacSecret()) > 0}
+	if err := h.jiraAuth(); err != nil {
+		status.JiraError = err.Error()
+	} else {
+		status.JiraAuthenticated = true
+	}
+	if err := h.githubAuth(); err != nil {
+		status.GitHubError = err.Error()
+	} else {
+		status.GitHubAuthenticated = true
+	}
+	return status
+}
+
+// jiraReady is a readiness check that fails while the plugin cannot authenticate against Jira
+func (h *healthChecker) jiraReady() bool {
+	return h.jiraAuth() == nil
+}
+
+func (h *healthChecker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := h.status()
+	w.Header().Set("Content-Type", "application/json")
+	if !status.healthy() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
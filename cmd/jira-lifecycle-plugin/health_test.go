@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHealthChecker(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		hmacSecret     []byte
+		jiraErr        error
+		githubErr      error
+		expectedCode   int
+		expectedStatus healthStatus
+		expectedReady  bool
+	}{{
+		name:           "healthy",
+		hmacSecret:     []byte("secret"),
+		expectedCode:   http.StatusOK,
+		expectedStatus: healthStatus{HMACSecretLoaded: true, JiraAuthenticated: true, GitHubAuthenticated: true},
+		expectedReady:  true,
+	}, {
+		name:           "missing HMAC secret is unhealthy",
+		expectedCode:   http.StatusServiceUnavailable,
+		expectedStatus: healthStatus{JiraAuthenticated: true, GitHubAuthenticated: true},
+		expectedReady:  true,
+	}, {
+		name:           "failing Jira authentication is unhealthy and not ready",
+		hmacSecret:     []byte("secret"),
+		jiraErr:        errors.New("401 Unauthorized"),
+		expectedCode:   http.StatusServiceUnavailable,
+		expectedStatus: healthStatus{HMACSecretLoaded: true, JiraError: "401 Unauthorized", GitHubAuthenticated: true},
+	}, {
+		name:           "failing GitHub authentication is unhealthy",
+		hmacSecret:     []byte("secret"),
+		githubErr:      errors.New("bad credentials"),
+		expectedCode:   http.StatusServiceUnavailable,
+		expectedStatus: healthStatus{HMACSecretLoaded: true, JiraAuthenticated: true, GitHubError: "bad credentials"},
+		expectedReady:  true,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := &healthChecker{
+				hmacSecret: func() []byte { return tc.hmacSecret },
+				jiraAuth:   func() error { return tc.jiraErr },
+				githubAuth: func() error { return tc.githubErr },
+			}
+			recorder := httptest.NewRecorder()
+			checker.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if recorder.Code != tc.expectedCode {
+				t.Errorf("expected status code %d, got %d", tc.expectedCode, recorder.Code)
+			}
+			var status healthStatus
+			if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+				t.Fatalf("failed to unmarshal health status: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedStatus, status); diff != "" {
+				t.Errorf("got unexpected health status: %s", diff)
+			}
+			if ready := checker.jiraReady(); ready != tc.expectedReady {
+				t.Errorf("expected readiness %t, got %t", tc.expectedReady, ready)
+			}
+		})
+	}
+}
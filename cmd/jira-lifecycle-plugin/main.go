@@ -9,6 +9,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	prowconfig "k8s.io/test-infra/prow/config"
 	"k8s.io/test-infra/prow/config/secret"
@@ -28,6 +30,7 @@ type options struct {
 
 	configPath        string
 	webhookSecretFile string
+	resyncTokenFile   string
 
 	config *Config
 
@@ -36,7 +39,10 @@ type options struct {
 	github                   prowflagutil.GitHubOptions
 	jira                     prowflagutil.JiraOptions
 
-	validateConfig string
+	validateConfig   string
+	validationMarker bool
+	validationReview string
+	logNoAction      bool
 }
 
 func gatherOptions() options {
@@ -48,6 +54,10 @@ func gatherOptions() options {
 	fs.StringVar(&o.configPath, "config-path", "", "Path to jira lifecycle configuration.")
 	fs.StringVar(&o.validateConfig, "validate-config", "", "Validate config at specified directory and exit without running operator")
 	fs.StringVar(&o.webhookSecretFile, "hmac-secret-file", "", "Path to the file containing the GitHub HMAC secret.")
+	fs.StringVar(&o.resyncTokenFile, "resync-token-file", "", "Path to the file containing the token required to use the /resync endpoint. The endpoint is disabled if unset.")
+	fs.BoolVar(&o.validationMarker, "validation-marker", false, "Append a hidden, machine-readable marker with the validation results of each referenced bug to comments.")
+	fs.StringVar(&o.validationReview, "validation-review", "", "Post the validation results of the referenced bugs as a pull request review instead of a comment. Invalid bugs request changes, while valid bugs get a commenting review with 'comment' or an approving review with 'approve'. Results are commented if unset.")
+	fs.BoolVar(&o.logNoAction, "log-no-action", false, "Log the reason whenever no action is taken for an event, e.g. because it is unrelated to Jira bugs.")
 
 	o.github.AddFlags(fs)
 	o.githubEventServerOptions.Bind(fs)
@@ -73,6 +83,10 @@ func (o *options) Validate() error {
 		return err
 	}
 
+	if o.validationReview != "" && o.validationReview != validationReviewComment && o.validationReview != validationReviewApprove {
+		return fmt.Errorf("--validation-review must be one of %q or %q, not %q", validationReviewComment, validationReviewApprove, o.validationReview)
+	}
+
 	bytes, err := gzip.ReadFileMaybeGZIP(o.configPath)
 	if err != nil {
 		return fmt.Errorf("couldn't read configuration file: %v", o.configPath)
@@ -82,6 +96,9 @@ func (o *options) Validate() error {
 	if err := yaml.Unmarshal(bytes, &config); err != nil {
 		return fmt.Errorf("couldn't unmarshal configuration: %w", err)
 	}
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
 	o.config = &config
 
 	if err := o.githubEventServerOptions.DefaultAndValidate(); err != nil {
@@ -106,6 +123,9 @@ func (o *options) getConfigWatchAndUpdate() (func(ctx context.Context), error) {
 		if err := yaml.Unmarshal(bytes, &c); err != nil {
 			return fmt.Errorf("couldn't unmarshal configuration: %w", err)
 		}
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
 
 		o.mut.Lock()
 		defer o.mut.Unlock()
@@ -164,6 +184,9 @@ func main() {
 		tokens = append(tokens, o.github.AppPrivateKeyPath)
 	}
 	tokens = append(tokens, o.webhookSecretFile)
+	if o.resyncTokenFile != "" {
+		tokens = append(tokens, o.resyncTokenFile)
+	}
 
 	if err := secret.Add(tokens...); err != nil {
 		logrus.WithError(err).Fatal("Error starting secrets agent.")
@@ -185,18 +208,35 @@ func main() {
 			defer o.mut.Unlock()
 			return o.config
 		},
-		ghc:             githubClient.WithFields(logger.Data).ForPlugin(PluginName),
-		jc:              jiraClient.WithFields(logger.Data).ForPlugin(PluginName),
-		prowConfigAgent: configAgent,
+		ghc:              reviewDismissingClient{githubClient.WithFields(logger.Data).ForPlugin(PluginName)},
+		jc:               jiraClient.WithFields(logger.Data).ForPlugin(PluginName),
+		prowConfigAgent:  configAgent,
+		validationMarker: o.validationMarker,
+		validationReview: o.validationReview,
+		logNoAction:      o.logNoAction,
+		metrics:          newAPIMetrics(prometheus.DefaultRegisterer),
+	}
+	if o.resyncTokenFile != "" {
+		serv.resyncToken = secret.GetTokenGenerator(o.resyncTokenFile)
+	}
+
+	checker := newHealthChecker(secret.GetTokenGenerator(o.webhookSecretFile), jiraClient, githubClient)
+	if status := checker.status(); !status.HMACSecretLoaded {
+		logger.Fatal("The webhook HMAC secret is empty, webhook payloads cannot be verified.")
+	} else if !status.healthy() {
+		logger.WithField("status", status).Error("Startup health check failed.")
 	}
 
 	eventServer := githubeventserver.New(o.githubEventServerOptions, secret.GetTokenGenerator(o.webhookSecretFile), logger)
 	eventServer.RegisterHandleIssueCommentEvent(serv.handleIssueComment)
 	eventServer.RegisterHandlePullRequestEvent(serv.handlePullRequest)
 	eventServer.RegisterHelpProvider(serv.helpProvider, logger)
+	eventServer.RegisterCustomFuncHandle("/resync", serv.handleResync)
+	eventServer.RegisterCustomFuncHandle("/healthz", checker.ServeHTTP)
+	eventServer.RegisterCustomFuncHandle("/metrics", promhttp.Handler().ServeHTTP)
 
 	health := pjutil.NewHealth()
-	health.ServeReady()
+	health.ServeReady(checker.jiraReady)
 
 	interrupts.ListenAndServe(eventServer, time.Second*30)
 	interrupts.WaitForGracefulShutdown()
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/test-infra/prow/github"
+	jiraclient "k8s.io/test-infra/prow/jira"
+)
+
+const (
+	jiraMetricsClient   = "jira"
+	githubMetricsClient = "github"
+)
+
+// apiMetrics records the latency of the calls made to the Jira and GitHub APIs while
+// handling events
+type apiMetrics struct {
+	callDuration *prometheus.HistogramVec
+}
+
+func newAPIMetrics(registerer prometheus.Registerer) *apiMetrics {
+	m := &apiMetrics{
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "jira_lifecycle_api_call_duration_seconds",
+			Help:    "How long the calls made to the Jira and GitHub APIs while handling events took, by client and operation.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"client", "operation"}),
+	}
+	registerer.MustRegister(m.callDuration)
+	return m
+}
+
+// observe records the duration of a call that started at the given time. It is meant to be
+// deferred, e.g. `defer m.observe(jiraMetricsClient, "get_issue", time.Now())`.
+func (m *apiMetrics) observe(client, operation string, start time.Time) {
+	m.callDuration.WithLabelValues(client, operation).Observe(time.Since(start).Seconds())
+}
+
+// instrumentedJiraClient records the latency of the calls made to the wrapped client
+type instrumentedJiraClient struct {
+	jiraclient.Client
+	metrics *apiMetrics
+}
+
+func (c *instrumentedJiraClient) GetIssue(id string) (*jira.Issue, error) {
+	defer c.metrics.observe(jiraMetricsClient, "get_issue", time.Now())
+	return c.Client.GetIssue(id)
+}
+
+func (c *instrumentedJiraClient) SearchWithContext(ctx context.Context, jql string, options *jira.SearchOptions) ([]jira.Issue, *jira.Response, error) {
+	defer c.metrics.observe(jiraMetricsClient, "search", time.Now())
+	return c.Client.SearchWithContext(ctx, jql, options)
+}
+
+func (c *instrumentedJiraClient) UpdateIssue(issue *jira.Issue) (*jira.Issue, error) {
+	defer c.metrics.observe(jiraMetricsClient, "update_issue", time.Now())
+	return c.Client.UpdateIssue(issue)
+}
+
+func (c *instrumentedJiraClient) CreateIssue(issue *jira.Issue) (*jira.Issue, error) {
+	defer c.metrics.observe(jiraMetricsClient, "create_issue", time.Now())
+	return c.Client.CreateIssue(issue)
+}
+
+func (c *instrumentedJiraClient) CreateIssueLink(link *jira.IssueLink) error {
+	defer c.metrics.observe(jiraMetricsClient, "create_issue_link", time.Now())
+	return c.Client.CreateIssueLink(link)
+}
+
+func (c *instrumentedJiraClient) CloneIssue(issue *jira.Issue) (*jira.Issue, error) {
+	defer c.metrics.observe(jiraMetricsClient, "clone_issue", time.Now())
+	return c.Client.CloneIssue(issue)
+}
+
+func (c *instrumentedJiraClient) GetTransitions(issueID string) ([]jira.Transition, error) {
+	defer c.metrics.observe(jiraMetricsClient, "get_transitions", time.Now())
+	return c.Client.GetTransitions(issueID)
+}
+
+func (c *instrumentedJiraClient) DoTransition(issueID, transitionID string) error {
+	defer c.metrics.observe(jiraMetricsClient, "do_transition", time.Now())
+	return c.Client.DoTransition(issueID, transitionID)
+}
+
+func (c *instrumentedJiraClient) UpdateStatus(issueID, statusName string) error {
+	defer c.metrics.observe(jiraMetricsClient, "update_status", time.Now())
+	return c.Client.UpdateStatus(issueID, statusName)
+}
+
+func (c *instrumentedJiraClient) FindUser(queryParam string) ([]*jira.User, error) {
+	defer c.metrics.observe(jiraMetricsClient, "find_user", time.Now())
+	return c.Client.FindUser(queryParam)
+}
+
+func (c *instrumentedJiraClient) GetRemoteLinks(id string) ([]jira.RemoteLink, error) {
+	defer c.metrics.observe(jiraMetricsClient, "get_links", time.Now())
+	return c.Client.GetRemoteLinks(id)
+}
+
+func (c *instrumentedJiraClient) AddRemoteLink(id string, link *jira.RemoteLink) (*jira.RemoteLink, error) {
+	defer c.metrics.observe(jiraMetricsClient, "create_link", time.Now())
+	return c.Client.AddRemoteLink(id, link)
+}
+
+func (c *instrumentedJiraClient) UpdateRemoteLink(id string, link *jira.RemoteLink) error {
+	defer c.metrics.observe(jiraMetricsClient, "update_link", time.Now())
+	return c.Client.UpdateRemoteLink(id, link)
+}
+
+func (c *instrumentedJiraClient) DeleteLink(id string) error {
+	defer c.metrics.observe(jiraMetricsClient, "delete_issue_link", time.Now())
+	return c.Client.DeleteLink(id)
+}
+
+func (c *instrumentedJiraClient) DeleteRemoteLink(issueID string, linkID int) error {
+	defer c.metrics.observe(jiraMetricsClient, "delete_link", time.Now())
+	return c.Client.DeleteRemoteLink(issueID, linkID)
+}
+
+func (c *instrumentedJiraClient) DeleteRemoteLinkViaURL(issueID, url string) (bool, error) {
+	defer c.metrics.observe(jiraMetricsClient, "delete_link_via_url", time.Now())
+	return c.Client.DeleteRemoteLinkViaURL(issueID, url)
+}
+
+func (c *instrumentedJiraClient) AddComment(issueID string, comment *jira.Comment) (*jira.Comment, error) {
+	defer c.metrics.observe(jiraMetricsClient, "add_comment", time.Now())
+	return c.Client.AddComment(issueID, comment)
+}
+
+func (c *instrumentedJiraClient) ListProjects() (*jira.ProjectList, error) {
+	defer c.metrics.observe(jiraMetricsClient, "list_projects", time.Now())
+	return c.Client.ListProjects()
+}
+
+// instrumentedGitHubClient records the latency of the calls made to the wrapped client
+type instrumentedGitHubClient struct {
+	githubClient
+	metrics *apiMetrics
+}
+
+func (c *instrumentedGitHubClient) EditComment(org, repo string, id int, comment string) error {
+	defer c.metrics.observe(githubMetricsClient, "edit_comment", time.Now())
+	return c.githubClient.EditComment(org, repo, id, comment)
+}
+
+func (c *instrumentedGitHubClient) GetIssue(org, repo string, number int) (*github.Issue, error) {
+	defer c.metrics.observe(githubMetricsClient, "get_issue", time.Now())
+	return c.githubClient.GetIssue(org, repo, number)
+}
+
+func (c *instrumentedGitHubClient) EditIssue(org, repo string, number int, issue *github.Issue) (*github.Issue, error) {
+	defer c.metrics.observe(githubMetricsClient, "edit_issue", time.Now())
+	return c.githubClient.EditIssue(org, repo, number, issue)
+}
+
+func (c *instrumentedGitHubClient) ListIssueComments(org, repo string, number int) ([]github.IssueComment, error) {
+	defer c.metrics.observe(githubMetricsClient, "list_issue_comments", time.Now())
+	return c.githubClient.ListIssueComments(org, repo, number)
+}
+
+func (c *instrumentedGitHubClient) GetPullRequest(org, repo string, number int) (*github.PullRequest, error) {
+	defer c.metrics.observe(githubMetricsClient, "get_pull_request", time.Now())
+	return c.githubClient.GetPullRequest(org, repo, number)
+}
+
+func (c *instrumentedGitHubClient) CreateComment(owner, repo string, number int, comment string) error {
+	defer c.metrics.observe(githubMetricsClient, "create_comment", time.Now())
+	return c.githubClient.CreateComment(owner, repo, number, comment)
+}
+
+func (c *instrumentedGitHubClient) GetIssueLabels(org, repo string, number int) ([]github.Label, error) {
+	defer c.metrics.observe(githubMetricsClient, "get_issue_labels", time.Now())
+	return c.githubClient.GetIssueLabels(org, repo, number)
+}
+
+func (c *instrumentedGitHubClient) AddLabel(owner, repo string, number int, label string) error {
+	defer c.metrics.observe(githubMetricsClient, "add_label", time.Now())
+	return c.githubClient.AddLabel(owner, repo, number, label)
+}
+
+func (c *instrumentedGitHubClient) RemoveLabel(owner, repo string, number int, label string) error {
+	defer c.metrics.observe(githubMetricsClient, "remove_label", time.Now())
+	return c.githubClient.RemoveLabel(owner, repo, number, label)
+}
+
+func (c *instrumentedGitHubClient) WasLabelAddedByHuman(org, repo string, num int, label string) (bool, error) {
+	defer c.metrics.observe(githubMetricsClient, "was_label_added_by_human", time.Now())
+	return c.githubClient.WasLabelAddedByHuman(org, repo, num, label)
+}
+
+func (c *instrumentedGitHubClient) QueryWithGitHubAppsSupport(ctx context.Context, q interface{}, vars map[string]interface{}, org string) error {
+	defer c.metrics.observe(githubMetricsClient, "query", time.Now())
+	return c.githubClient.QueryWithGitHubAppsSupport(ctx, q, vars, org)
+}
+
+func (c *instrumentedGitHubClient) IsMember(org, user string) (bool, error) {
+	defer c.metrics.observe(githubMetricsClient, "is_member", time.Now())
+	return c.githubClient.IsMember(org, user)
+}
+
+func (c *instrumentedGitHubClient) TeamBySlugHasMember(org string, teamSlug string, memberLogin string) (bool, error) {
+	defer c.metrics.observe(githubMetricsClient, "team_by_slug_has_member", time.Now())
+	return c.githubClient.TeamBySlugHasMember(org, teamSlug, memberLogin)
+}
+
+func (c *instrumentedGitHubClient) CreateCheckRun(org, repo string, checkRun github.CheckRun) error {
+	defer c.metrics.observe(githubMetricsClient, "create_check_run", time.Now())
+	return c.githubClient.CreateCheckRun(org, repo, checkRun)
+}
+
+func (c *instrumentedGitHubClient) CreateReview(org, repo string, number int, r github.DraftReview) error {
+	defer c.metrics.observe(githubMetricsClient, "create_review", time.Now())
+	return c.githubClient.CreateReview(org, repo, number, r)
+}
+
+func (c *instrumentedGitHubClient) ListReviews(org, repo string, number int) ([]github.Review, error) {
+	defer c.metrics.observe(githubMetricsClient, "list_reviews", time.Now())
+	return c.githubClient.ListReviews(org, repo, number)
+}
+
+func (c *instrumentedGitHubClient) DismissReview(org, repo string, number int, review github.Review, message string) error {
+	defer c.metrics.observe(githubMetricsClient, "dismiss_review", time.Now())
+	return c.githubClient.DismissReview(org, repo, number, review, message)
+}
+
+// instrument wraps the clients used to handle an event so that the latency of their calls is
+// recorded. The clients are returned as they are if no metrics are configured.
+func (m *apiMetrics) instrument(jc jiraclient.Client, ghc githubClient) (jiraclient.Client, githubClient) {
+	if m == nil {
+		return jc, ghc
+	}
+	return &instrumentedJiraClient{Client: jc, metrics: m}, &instrumentedGitHubClient{githubClient: ghc, metrics: m}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/github/fakegithub"
+	"k8s.io/test-infra/prow/jira/fakejira"
+)
+
+func TestAPIMetrics(t *testing.T) {
+	t.Parallel()
+	registry := prometheus.NewRegistry()
+	metrics := newAPIMetrics(registry)
+
+	gc := fakegithub.NewFakeClient()
+	gc.IssueLabelsExisting = []string{}
+	gc.IssueComments = map[int][]github.IssueComment{}
+	fakeJira := &fakejira.FakeClient{Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+		Transitions: []jira.Transition{{ID: "1", Name: "POST", To: jira.Status{Name: "POST"}}},
+	}
+	jc, ghc := metrics.instrument(fakeJira, fakeGHClient{gc})
+	e := event{
+		org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", opened: true, body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+	}
+	options := JiraBranchOptions{StateAfterValidation: &JiraBugState{Status: "POST"}}
+	if err := handle(jc, ghc, &fakeAgileClient{}, options, logrus.WithField("test", t.Name()), e, sets.NewString("org/repo"), sets.NewString()); err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	observed := map[string]uint64{}
+	for _, family := range families {
+		if family.GetName() != "jira_lifecycle_api_call_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			observed[labels["client"]+"/"+labels["operation"]] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+	expected := map[string]uint64{
+		"jira/get_issue":          2,
+		"jira/get_transitions":    1,
+		"jira/update_status":      1,
+		"github/get_issue_labels": 2,
+		"github/add_label":        2,
+		"github/create_comment":   1,
+	}
+	if diff := cmp.Diff(expected, observed); diff != "" {
+		t.Errorf("observed API calls differ from expected: %s", diff)
+	}
+}
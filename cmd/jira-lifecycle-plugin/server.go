@@ -1,14 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"text/template"
 
 	"github.com/andygrunwald/go-jira"
 	githubql "github.com/shurcooL/githubv4"
@@ -36,14 +41,28 @@ const (
 	moderateSeverity      = "Moderate"
 	lowSeverity           = "Low"
 	informationalSeverity = "Informational"
+	defaultRetitleCommand = "/retitle"
 )
 
 var (
-	titleMatchJiraIssue    = regexp.MustCompile(`(?i)([[:alpha:]]+-\d+,)*(NO-JIRA|NO-ISSUE|[[:alpha:]]+-\d+)+:`)
-	refreshCommandMatch    = regexp.MustCompile(`(?mi)^/jira refresh\s*$`)
-	qaReviewCommandMatch   = regexp.MustCompile(`(?mi)^/jira cc-qa\s*$`)
-	cherrypickCommandMatch = regexp.MustCompile(`(?mi)^/jira cherrypick (OCPBUGS-(\d+),)*(OCPBUGS-(\d+))+\s*$`)
-	cherrypickPRMatch      = regexp.MustCompile(`This is an automated cherry-pick of #([0-9]+)`)
+	titleMatchJiraIssue     = regexp.MustCompile(`(?i)([[:alpha:]]+-\d+,)*(NO-JIRA|NO-ISSUE|[[:alpha:]]+-\d+)+:`)
+	refreshCommandMatch     = regexp.MustCompile(`(?mi)^/jira refresh\s*$`)
+	qaReviewCommandMatch    = regexp.MustCompile(`(?mi)^/jira cc-qa\s*$`)
+	retryCommandMatch       = regexp.MustCompile(`(?mi)^/jira retry\s*$`)
+	listPRsCommandMatch     = regexp.MustCompile(`(?mi)^/jira prs\s*$`)
+	depsCommandMatch        = regexp.MustCompile(`(?mi)^/jira deps\s*$`)
+	verifyCommandMatch      = regexp.MustCompile(`(?mi)^/jira verify\s*$`)
+	addLinkCommandMatch     = regexp.MustCompile(`(?mi)^/jira add-link\s*$`)
+	relabelCommandMatch     = regexp.MustCompile(`(?mi)^/jira relabel\s*$`)
+	fixLabelsCommandMatch   = regexp.MustCompile(`(?mi)^/jira fix-labels\s*$`)
+	fixVersionCommandMatch  = regexp.MustCompile(`(?mi)^/jira fix-version-from-branch\s*$`)
+	severityMapCommandMatch = regexp.MustCompile(`(?mi)^/jira severity-map\s*$`)
+	historyCommandMatch     = regexp.MustCompile(`(?mi)^/jira history\s*$`)
+	simulateMergeMatch      = regexp.MustCompile(`(?mi)^/jira simulate-merge\s*$`)
+	recloneCommandMatch     = regexp.MustCompile(`(?mi)^/jira reclone ([[:alpha:]]+-\d+)\s*$`)
+	cherrypickCommandMatch  = regexp.MustCompile(`(?mi)^/jira cherrypick (OCPBUGS-(\d+),)*(OCPBUGS-(\d+))+( --branches ([^\s,]+,)*[^\s,]+)?\s*$`)
+	cherrypickPRMatch       = regexp.MustCompile(`This is an automated cherry-pick of #([0-9]+)`)
+	revertTitleMatch        = regexp.MustCompile(`(?i)^\s*Revert:?\s+"`)
 )
 
 type referencedBug struct {
@@ -56,6 +75,11 @@ type dependent struct {
 	targetVersion    *string
 	multipleVersions bool
 	bugState         JiraBugState
+	// unmergedPRs lists the pull requests linked to the dependent that have not merged. It is
+	// only populated if RequireDependentPRsMerged is set.
+	unmergedPRs []string
+	// components lists the names of the components the dependent is filed against
+	components []string
 }
 
 type server struct {
@@ -64,6 +88,41 @@ type server struct {
 	prowConfigAgent *prowconfig.Agent
 	ghc             githubClient
 	jc              jiraclient.Client
+
+	// resyncToken returns the token that must be provided to use the resync endpoint.
+	// The endpoint is disabled if this is not set.
+	resyncToken func() []byte
+
+	// validationMarker determines whether comments about referenced bugs include a
+	// hidden, machine-readable marker with the validation results
+	validationMarker bool
+
+	// validationReview determines whether the validation results of referenced bugs are posted
+	// as a pull request review instead of a comment, and which review valid bugs get: one of
+	// validationReviewComment or validationReviewApprove. Results are commented if unset.
+	validationReview string
+
+	// logNoAction determines whether the reason is logged whenever no action is taken for
+	// an event, which otherwise happens silently
+	logNoAction bool
+
+	// metrics records the latency of the Jira and GitHub API calls made while handling
+	// events. Nothing is recorded if this is not set.
+	metrics *apiMetrics
+}
+
+const (
+	// validationReviewComment posts the results of valid bugs as a commenting review
+	validationReviewComment = "comment"
+	// validationReviewApprove posts the results of valid bugs as an approving review
+	validationReviewApprove = "approve"
+)
+
+// noActionReasonField is the log field holding the reason why no action was taken for an event
+const noActionReasonField = "no_action_reason"
+
+func logNoAction(log *logrus.Entry, reason string) {
+	log.WithField(noActionReasonField, reason).Info("Taking no action for the event.")
 }
 
 func (s *server) helpProvider(enabledRepos []config.OrgRepo) (*pluginhelp.PluginHelp, error) {
@@ -97,9 +156,13 @@ func (s *server) helpProvider(enabledRepos []config.OrgRepo) (*pluginhelp.Plugin
 					conditions = append(conditions, "be closed")
 				}
 			}
-			if opts[branch].TargetVersion != nil {
+			if versions := acceptableTargetVersions(opts[branch]); len(versions) > 0 {
 				if opts[branch].SkipTargetVersionCheck == nil || (opts[branch].SkipTargetVersionCheck != nil && !*opts[branch].SkipTargetVersionCheck) {
-					conditions = append(conditions, fmt.Sprintf("target the %q version", *opts[branch].TargetVersion))
+					if len(versions) == 1 {
+						conditions = append(conditions, fmt.Sprintf("target the %q version", versions[0]))
+					} else {
+						conditions = append(conditions, fmt.Sprintf("target one of the following versions: %s", strings.Join(versions, ", ")))
+					}
 				}
 			}
 			if opts[branch].ValidStates != nil && len(*opts[branch].ValidStates) > 0 {
@@ -116,6 +179,84 @@ func (s *server) helpProvider(enabledRepos []config.OrgRepo) (*pluginhelp.Plugin
 			if opts[branch].DependentBugTargetVersions != nil {
 				conditions = append(conditions, fmt.Sprintf("have all dependent bugs in one of the following target versions: %s", strings.Join(*opts[branch].DependentBugTargetVersions, ", ")))
 			}
+			if opts[branch].RequireDependentPRsMerged != nil && *opts[branch].RequireDependentPRsMerged {
+				conditions = append(conditions, "have all pull requests linked to dependent bugs merged")
+			}
+			if opts[branch].RequireDependentSameComponent != nil && *opts[branch].RequireDependentSameComponent {
+				conditions = append(conditions, "have all dependent bugs filed against the same components")
+			}
+			if opts[branch].RequireDependentsResolved != nil && *opts[branch].RequireDependentsResolved {
+				conditions = append(conditions, "have all dependent bugs resolved")
+			}
+			if opts[branch].RequiredBoardID != nil {
+				conditions = append(conditions, fmt.Sprintf("be on board %d", *opts[branch].RequiredBoardID))
+			}
+			if opts[branch].RequireOriginalBug != nil && *opts[branch].RequireOriginalBug {
+				conditions = append(conditions, "not be a clone of another bug")
+			}
+			if opts[branch].RequireEnvironmentField != nil && *opts[branch].RequireEnvironmentField {
+				conditions = append(conditions, "have the environment field set")
+			}
+			if opts[branch].RequireQaContact != nil && *opts[branch].RequireQaContact {
+				conditions = append(conditions, "have a QA contact")
+			}
+			if opts[branch].ExpectedProject != nil {
+				conditions = append(conditions, fmt.Sprintf("be in the %s project", *opts[branch].ExpectedProject))
+			}
+			if len(opts[branch].RequiredLabels) > 0 {
+				conditions = append(conditions, fmt.Sprintf("have the labels %s", strings.Join(opts[branch].RequiredLabels, ", ")))
+			}
+			if len(opts[branch].ForbiddenLabels) > 0 {
+				conditions = append(conditions, fmt.Sprintf("not have any of the labels %s", strings.Join(opts[branch].ForbiddenLabels, ", ")))
+			}
+			if opts[branch].ValidationJQL != nil && *opts[branch].ValidationJQL != "" {
+				conditions = append(conditions, fmt.Sprintf("match the filter `%s`", *opts[branch].ValidationJQL))
+			}
+			if opts[branch].RequireTestPR != nil && *opts[branch].RequireTestPR && opts[branch].TestRepos != nil {
+				conditions = append(conditions, fmt.Sprintf("link a pull request in a repo matching one of %s", strings.Join(*opts[branch].TestRepos, ", ")))
+			}
+			if opts[branch].RequireKnownTargetVersion != nil && *opts[branch].RequireKnownTargetVersion {
+				if opts[branch].KnownTargetVersions != nil {
+					conditions = append(conditions, fmt.Sprintf("target one of the following known versions: %s", strings.Join(*opts[branch].KnownTargetVersions, ", ")))
+				} else {
+					conditions = append(conditions, "target a known version of their project")
+				}
+			}
+			if opts[branch].ValidateBranchTargetConsistency != nil && *opts[branch].ValidateBranchTargetConsistency {
+				if expected, ok := opts[branch].BranchTargetVersions[branch]; ok {
+					conditions = append(conditions, fmt.Sprintf("target the %q version expected for the branch", expected))
+				} else if branch == JiraOptionsWildcard && len(opts[branch].BranchTargetVersions) > 0 {
+					conditions = append(conditions, "target the version expected for the branch of the pull request")
+				}
+			}
+			if len(opts[branch].IssueTypeBranches) > 0 {
+				var issueTypes []string
+				for issueType := range opts[branch].IssueTypeBranches {
+					issueTypes = append(issueTypes, issueType)
+				}
+				sort.Strings(issueTypes)
+				for _, issueType := range issueTypes {
+					conditions = append(conditions, fmt.Sprintf("only be of type %s on branches matching %s", issueType, strings.Join(opts[branch].IssueTypeBranches[issueType], ", ")))
+				}
+			}
+			if len(opts[branch].AllowedReporters) > 0 {
+				conditions = append(conditions, fmt.Sprintf("be reported by one of %s", strings.Join(opts[branch].AllowedReporters, ", ")))
+			}
+			if len(opts[branch].RejectResolutions) > 0 {
+				conditions = append(conditions, fmt.Sprintf("not be resolved as any of %s", strings.Join(opts[branch].RejectResolutions, ", ")))
+			}
+			if opts[branch].RequireVerificationField != nil && *opts[branch].RequireVerificationField {
+				conditions = append(conditions, "have the verification field set")
+			}
+			if opts[branch].RequireReleaseNoteType != nil && *opts[branch].RequireReleaseNoteType {
+				conditions = append(conditions, "have the release note type set")
+			}
+			if opts[branch].RequireStoryPoints != nil && *opts[branch].RequireStoryPoints {
+				conditions = append(conditions, "have story points")
+			}
+			if opts[branch].RejectFlaggedBlocked != nil && *opts[branch].RejectFlaggedBlocked {
+				conditions = append(conditions, "not be flagged as blocked")
+			}
 			switch len(conditions) {
 			case 0:
 				message += "exist"
@@ -194,6 +335,7 @@ func (s *server) helpProvider(enabledRepos []config.OrgRepo) (*pluginhelp.Plugin
 					Resolution: "FIXED",
 				},
 				AllowedSecurityLevels: []string{"group1", "groups2"},
+				DeniedSecurityLevels:  []string{"group3"},
 			},
 		},
 		Orgs: map[string]JiraOrgOptions{
@@ -232,6 +374,7 @@ func (s *server) helpProvider(enabledRepos []config.OrgRepo) (*pluginhelp.Plugin
 							Resolution: "FIXED",
 						},
 						AllowedSecurityLevels: []string{"group1", "groups2"},
+						DeniedSecurityLevels:  []string{"group3"},
 					},
 				},
 				Repos: map[string]JiraRepoOptions{
@@ -270,6 +413,7 @@ func (s *server) helpProvider(enabledRepos []config.OrgRepo) (*pluginhelp.Plugin
 									Resolution: "FIXED",
 								},
 								AllowedSecurityLevels: []string{"group1", "groups2"},
+								DeniedSecurityLevels:  []string{"group3"},
 							},
 						},
 					},
@@ -300,11 +444,95 @@ func (s *server) helpProvider(enabledRepos []config.OrgRepo) (*pluginhelp.Plugin
 		Examples:    []string{"/jira cc-qa"},
 	})
 	pluginHelp.AddCommand(pluginhelp.Command{
-		Usage:       "/jira cherrypick jiraBugKey",
-		Description: "Cherrypick a jira bug and link it to the current PR",
+		Usage:       "/jira cherrypick jiraBugKey [--branches branch,...]",
+		Description: "Cherrypick a jira bug and link it to the current PR. With --branches, the bug is cloned for the target version of each of the given branches instead",
+		Featured:    false,
+		WhoCanUse:   "Anyone",
+		Examples:    []string{"/jira cherrypick OCPBUGS-1234", "/jira cherrypick OCPBUGS-1234 --branches release-4.14,release-4.13"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira retry",
+		Description: "Retry the Jira state transition for the bug referenced in the PR title without re-running the full validation",
+		Featured:    false,
+		WhoCanUse:   "Anyone",
+		Examples:    []string{"/jira retry"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira prs",
+		Description: "List all PRs linked to the Jira bug referenced in the PR title, along with their merge status",
+		Featured:    false,
+		WhoCanUse:   "Anyone",
+		Examples:    []string{"/jira prs"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira deps",
+		Description: "List the bugs that the Jira bug referenced in the PR title depends on, with their states and target versions as seen by the dependent bug validations",
+		Featured:    false,
+		WhoCanUse:   "Anyone",
+		Examples:    []string{"/jira deps"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira verify",
+		Description: "Move the Jira bug referenced in the title of a merged PR to the VERIFIED state, attributing the verification to the commenter",
+		Featured:    false,
+		WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+		Examples:    []string{"/jira verify"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira add-link",
+		Description: "Link the PR on the Jira bug referenced in the PR title using the external bug tracker, even if the repo is not configured to add these links",
+		Featured:    false,
+		WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+		Examples:    []string{"/jira add-link"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira relabel",
+		Description: "Reconcile the Jira labels of the PR (valid/invalid bug, valid reference and severity) with the current configuration without changing the state of the bug",
+		Featured:    false,
+		WhoCanUse:   "Anyone",
+		Examples:    []string{"/jira relabel"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira fix-labels",
+		Description: "Like /jira relabel, but also remove the Jira labels of the PR that were added manually or are not managed with the current configuration, e.g. contradictory valid and invalid bug labels",
+		Featured:    false,
+		WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+		Examples:    []string{"/jira fix-labels"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira severity-map",
+		Description: "List the severities of Jira bugs and the labels they are mapped to on the PR",
+		Featured:    false,
+		WhoCanUse:   "Anyone",
+		Examples:    []string{"/jira severity-map"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira history",
+		Description: "Summarize the recent actions of the plugin on the PR, e.g. bug validations and state changes, as a timeline built from its prior comments",
 		Featured:    false,
 		WhoCanUse:   "Anyone",
-		Examples:    []string{"/jira cherrypick OCPBUGS-1234"},
+		Examples:    []string{"/jira history"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira simulate-merge",
+		Description: "Handle the PR as if it had merged to verify the configuration of the branch, e.g. on a staging instance: the bug referenced in the PR title is moved to the state after merge if all of its linked PRs have merged, treating this PR as merged. The response is labeled as a simulation",
+		Featured:    false,
+		WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+		Examples:    []string{"/jira simulate-merge"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira reclone jiraBugKey",
+		Description: "Replace the clone referenced in the PR title, which was cloned from the wrong bug, with a clone of the given bug and retitle the PR. The existing clone is unlinked from its parent, but not deleted",
+		Featured:    false,
+		WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+		Examples:    []string{"/jira reclone OCPBUGS-1234"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira fix-version-from-branch",
+		Description: "Set the target version of the Jira bug referenced in the PR title, e.g. a clone created with the wrong target version, to the target version configured for the branch of the PR and re-validate the bug",
+		Featured:    false,
+		WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+		Examples:    []string{"/jira fix-version-from-branch"},
 	})
 	return pluginHelp, nil
 }
@@ -322,38 +550,230 @@ type githubClient interface {
 	WasLabelAddedByHuman(org, repo string, num int, label string) (bool, error)
 	QueryWithGitHubAppsSupport(ctx context.Context, q interface{}, vars map[string]interface{}, org string) error
 	BotUserChecker() (func(candidate string) bool, error)
+	IsMember(org, user string) (bool, error)
+	TeamBySlugHasMember(org string, teamSlug string, memberLogin string) (bool, error)
+	CreateCheckRun(org, repo string, checkRun github.CheckRun) error
+	CreateReview(org, repo string, number int, r github.DraftReview) error
+	ListReviews(org, repo string, number int) ([]github.Review, error)
+	DismissReview(org, repo string, number int, review github.Review, message string) error
+}
+
+// untrackedKeys returns the keys of the referenced issues that are not in one of the tracked
+// projects. All projects are tracked if no tracked projects are configured.
+func untrackedKeys(bugs []referencedBug, trackedProjects sets.String) []string {
+	if len(trackedProjects) == 0 {
+		return nil
+	}
+	var untracked []string
+	for _, bug := range bugs {
+		if !trackedProjects.Has(projectFromKey(bug.Key)) {
+			untracked = append(untracked, bug.Key)
+		}
+	}
+	return untracked
+}
+
+// projectFromKey returns the project of a Jira issue key, e.g. OCPBUGS for OCPBUGS-123
+func projectFromKey(key string) string {
+	return strings.ToUpper(strings.SplitN(key, "-", 2)[0])
+}
+
+// validBugCheckRunName is the name of the check-run published when PublishCheckRun is set.
+const validBugCheckRunName = "jira/valid-bug"
+
+// publishCheckRun reports the outcome of the bug validation as a completed check-run on the
+// head commit of the pull request.
+func publishCheckRun(ghc githubClient, e event, valid bool, summary string) error {
+	pr, err := ghc.GetPullRequest(e.org, e.repo, e.number)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+	conclusion, title := "success", "The referenced Jira bug is valid"
+	if !valid {
+		conclusion, title = "failure", "The referenced Jira bug is invalid"
+	}
+	return ghc.CreateCheckRun(e.org, e.repo, github.CheckRun{
+		Name:       validBugCheckRunName,
+		HeadSHA:    pr.Head.SHA,
+		Status:     "completed",
+		Conclusion: conclusion,
+		Output: github.CheckRunOutput{
+			Title:   title,
+			Summary: summary,
+		},
+	})
+}
+
+// republishCheckRun publishes the outcome of the last bug validation, as recorded by the validity
+// labels, on the current head commit of the pull request. It returns false if the outcome is only
+// known by validating the bugs again.
+func republishCheckRun(e event, ghc githubClient, options JiraBranchOptions) (bool, error) {
+	if options.SkipValidityLabels != nil && *options.SkipValidityLabels {
+		return false, nil
+	}
+	currentLabels, err := ghc.GetIssueLabels(e.org, e.repo, e.number)
+	if err != nil {
+		return false, fmt.Errorf("failed to get labels: %w", err)
+	}
+	var valid, invalid bool
+	for _, label := range currentLabels {
+		valid = valid || label.Name == labels.JiraValidBug
+		invalid = invalid || label.Name == labels.JiraInvalidBug
+	}
+	if !valid && !invalid {
+		// the bugs were never validated, so there is no outcome to carry over
+		return true, nil
+	}
+	summary := "The outcome of the bug validation of an earlier commit was carried over to this commit. Comment <code>/jira refresh</code> to validate the referenced bugs again."
+	if err := publishCheckRun(ghc, e, valid && !invalid, summary); err != nil {
+		return true, fmt.Errorf("failed to publish check-run: %w", err)
+	}
+	return true, nil
+}
+
+// jiraClientForOrg returns the Jira client to use for events in the org. If the org overrides
+// the Jira URL, the returned client renders links using that URL instead of the server's.
+func (s *server) jiraClientForOrg(cfg *Config, org string) jiraclient.Client {
+	if url := cfg.JiraURLForOrg(org); url != "" {
+		return &orgJiraClient{Client: s.jc, url: url}
+	}
+	return s.jc
+}
+
+// cherrypickTargetVersions returns the configured target version of each of the branches,
+// leaving out branches without one
+func cherrypickTargetVersions(cfg *Config, org, repo string, branches []string) map[string]string {
+	versions := map[string]string{}
+	for _, branch := range branches {
+		if options := cfg.OptionsForBranch(org, repo, branch); options.TargetVersion != nil {
+			versions[branch] = *options.TargetVersion
+		}
+	}
+	return versions
+}
+
+// orgJiraClient overrides the Jira URL of the wrapped client. It is only used to
+// render links, all requests are still sent to the wrapped client's server.
+type orgJiraClient struct {
+	jiraclient.Client
+	url string
+}
+
+func (c *orgJiraClient) JiraURL() string {
+	return c.url
 }
 
 func (s *server) handleIssueComment(l *logrus.Entry, e github.IssueCommentEvent) {
 	cfg := s.config()
-	event, err := digestComment(s.ghc, l, e)
+	event, err := digestComment(s.ghc, cfg, l, e)
 	if err != nil {
 		l.Errorf("failed to digest comment: %v", err)
 	}
+	if event == nil && err == nil && s.logNoAction {
+		logNoAction(l, "unrelated event")
+	}
 	if event != nil {
 		options := cfg.OptionsForBranch(event.org, event.repo, event.baseRef)
-		if err := handle(s.jc, s.ghc, options, l, *event, s.prowConfigAgent.Config().AllRepos); err != nil {
+		s.configureEvent(event)
+		event.cherrypickTargetVersions = cherrypickTargetVersions(cfg, event.org, event.repo, event.cherrypickBranches)
+		jc, ghc := s.metrics.instrument(s.jiraClientForOrg(cfg, event.org), s.ghc)
+		if err := handle(jc, ghc, &jiraAgileClient{jc: jc}, options, l, *event, s.prowConfigAgent.Config().AllRepos, sets.NewString(cfg.TrackedProjects...)); err != nil {
 			l.Errorf("failed to handle comment: %v", err)
 		}
 	}
 }
 
-func handle(jc jiraclient.Client, ghc githubClient, options JiraBranchOptions, log *logrus.Entry, e event, allRepos sets.String) error {
+func handle(jc jiraclient.Client, ghc githubClient, ac agileClient, options JiraBranchOptions, log *logrus.Entry, e event, allRepos, trackedProjects sets.String) error {
 	comment := e.comment(ghc)
+	if e.labelChangedBy != "" {
+		isBot, err := ghc.BotUserChecker()
+		if err != nil {
+			return fmt.Errorf("failed to create bot user checker: %w", err)
+		}
+		if isBot(e.labelChangedBy) {
+			// the labels were changed while handling an earlier event
+			e.noAction(log, "labels changed by the bot")
+			return nil
+		}
+	}
+	// the exemption only skips the validation, so the bugs of merged and closed pull requests
+	// are still moved
+	if e.exemptBot && !e.merged && !e.closed {
+		if e.opened {
+			return comment(fmt.Sprintf("This pull request was opened by %s, whose pull requests are exempt from the Jira requirements of this repository, so the referenced bugs have not been validated and no Jira labels have been applied. Comment <code>/jira refresh</code> to validate them anyway.", e.login))
+		}
+		e.noAction(log, "pull request authored by an exempt bot")
+		return nil
+	}
+	if untracked := untrackedKeys(e.bugs, trackedProjects); !e.missing && len(untracked) > 0 && len(untracked) < len(e.bugs) {
+		// issues that are not managed by this plugin are skipped, while the tracked ones are
+		// handled as usual, including on merge and close
+		log.WithField("keys", untracked).Debug("Skipping referenced issues in untracked projects.")
+		skipped := sets.NewString(untracked...)
+		var tracked []referencedBug
+		for _, bug := range e.bugs {
+			if !skipped.Has(bug.Key) {
+				tracked = append(tracked, bug)
+			}
+		}
+		e.bugs = tracked
+	} else if !e.missing && len(untracked) > 0 {
+		// do not apply any labels for issues that are not managed by this plugin
+		if e.opened || e.refresh {
+			var responses []string
+			for _, key := range untracked {
+				responses = append(responses, fmt.Sprintf("This pull request references %s, which is in the %s project. Issues in the %s project are not managed by this plugin, so no labels have been applied.", key, projectFromKey(key), projectFromKey(key)))
+			}
+			return comment(strings.Join(responses, "\n\n"))
+		}
+		e.noAction(log, "bug in an untracked project")
+		return nil
+	}
+	// title edits and pushes only maintain the remote links of the referenced bugs; they must not
+	// comment on problems with the bugs, which would be repeated on every push
+	if e.titleChanged {
+		return handleTitleChange(e, jc, log)
+	}
+	if e.synchronized {
+		if err := handleSynchronize(e, jc, options, log); err != nil || options.PublishCheckRun == nil || !*options.PublishCheckRun {
+			return err
+		}
+		// the check-run only covers the commit it was published for, so the new head needs one too
+		if handled, err := republishCheckRun(e, ghc, options); handled || err != nil {
+			return err
+		}
+		// without the validity labels the outcome is only known by validating the bugs again
+	}
 	if !e.missing {
 		for _, refBug := range e.bugs {
 			if refBug.IsBug && refBug.Key != "" {
-				issue, err := getJira(jc, refBug.Key, log, comment)
-				if err != nil || issue == nil {
-					return err
+				issue, notFound, msg := lookupJira(jc, refBug.Key, log)
+				if notFound {
+					if deleted, err := handleDeletedJira(e, ghc, jc, refBug.Key, log); deleted || err != nil {
+						return err
+					}
 				}
-				bugAllowed, err := isBugAllowed(issue, options.AllowedSecurityLevels)
+				if issue == nil {
+					return comment(msg)
+				}
+				bugAllowed, err := isBugAllowed(issue, options.AllowedSecurityLevels, options.DeniedSecurityLevels)
 				if err != nil {
 					return err
 				}
 				if !bugAllowed {
 					// ignore bugs that are in non-allowed security levels for this repo
 					if e.opened || e.refresh {
+						denied, err := isBugDenied(issue, options.DeniedSecurityLevels)
+						if err != nil {
+							return err
+						}
+						if denied {
+							response := fmt.Sprintf(issueLink+" is in a security level that is denied for this repo.\nDenied security levels for this repo are:", refBug.Key, jc.JiraURL(), refBug.Key)
+							for _, group := range options.DeniedSecurityLevels {
+								response += "\n- " + group
+							}
+							return comment(response + securityTeamCC(options))
+						}
 						response := fmt.Sprintf(issueLink+" is in a security level that is not in the allowed security levels for this repo.", refBug.Key, jc.JiraURL(), refBug.Key)
 						if len(options.AllowedSecurityLevels) > 0 {
 							response += "\nAllowed security levels for this repo are:"
@@ -363,29 +783,94 @@ func handle(jc jiraclient.Client, ghc githubClient, options JiraBranchOptions, l
 						} else {
 							response += " There are no allowed security levels configured for this repo."
 						}
-						return comment(response)
+						return comment(response + securityTeamCC(options))
 					}
+					e.noAction(log, "bug in a security level that is not allowed")
 					return nil
 				}
 			}
 		}
 	}
+	// retries only re-attempt the state transition
+	if e.retry {
+		return handleRetry(e, ghc, jc, ac, options, log, allRepos)
+	}
+	if e.listPRs {
+		return handleListPRs(e, ghc, jc, log, allRepos)
+	}
+	if e.deps {
+		return handleDeps(e, ghc, jc, options, log, allRepos)
+	}
+	if e.verify {
+		return handleVerify(e, ghc, jc, log)
+	}
+	if e.simulateMerge {
+		return handleSimulateMerge(e, ghc, jc, ac, options, log, allRepos)
+	}
+	if e.addLink {
+		return handleAddLink(e, ghc, jc, log)
+	}
+	if e.recloneParent != "" {
+		return handleReclone(e, ghc, jc, options, log)
+	}
+	if e.fixVersion {
+		if handled, err := handleFixVersion(e, ghc, jc, options, log); handled || err != nil {
+			return err
+		}
+		// the bugs are re-validated with their new target version like for a refresh
+		e.refresh = true
+	}
+	if e.fixLabels {
+		// fixing the labels is a relabel that does not retain the labels added manually
+		e.relabel = true
+	}
+	if e.relabel {
+		// relabeling re-runs the validation like a refresh to reconcile the labels, but it
+		// must not change the bug, even if the pull request is already merged or closed
+		e.refresh = true
+		options.StateAfterValidation = nil
+		options.PreMergeStateAfterValidation = nil
+		options.AddExternalLink = nil
+		options.SlackWebhookURL = nil
+	}
+	if e.convertedToDraft {
+		return handleConvertedToDraft(e, ghc, options)
+	}
+	if e.draft {
+		// bugs of drafts are validated and labeled, but not moved before the pull request is
+		// ready for review, which re-evaluates them
+		options.StateAfterValidation = nil
+		options.PreMergeStateAfterValidation = nil
+	}
 	// cherrypicks follow a different pattern than normal validation
 	if e.cherrypick {
 		return handleCherrypick(e, ghc, jc, options, log)
 	}
+	if e.revert && options.StateAfterRevert != nil {
+		// merged reverts reopen the bugs whose fix they revert
+		if e.merged && !e.relabel {
+			return handleRevertMerge(e, ghc, jc, options, log)
+		}
+		// reverts do not advance the state of the bugs whose fix they revert
+		options.StateAfterValidation = nil
+		options.PreMergeStateAfterValidation = nil
+	}
 	// merges follow a different pattern from the normal validation
-	if e.merged {
-		return handleMerge(e, ghc, jc, options, log, allRepos)
+	if e.merged && !e.relabel {
+		return handleMerge(e, ghc, jc, ac, options, log, allRepos)
 	}
 	// close events follow a different pattern from the normal validation
-	if e.closed && !e.merged {
-		return handleClose(e, ghc, jc, options, log)
+	if e.closed && !e.merged && !e.relabel {
+		return handleClose(e, ghc, jc, options, log, allRepos)
 	}
 
 	var needsJiraValidRefLabel, needsJiraValidBugLabel, needsJiraInvalidBugLabel bool
 	var response, severityLabel string
 	var invalidIssues []string
+	// bugStates records the state of each validated bug for CommentOncePerState
+	var bugStates []string
+	// bugSeverities records the severity of each validated bug for ReportSeverityChanges
+	var bugSeverities []string
 	if !e.noJira {
 		for _, refBug := range e.bugs {
 			// separate responses for different bugs
@@ -445,7 +930,7 @@ func handle(jc jiraclient.Client, ghc githubClient, options JiraBranchOptions, l
 					}
 					// We still want to notify if the pull request branch and bug target version mismatch
 					if checkTargetVersion(options) {
-						if err := validateTargetVersion(issue, *options.TargetVersion); err != nil {
+						if err := validateTargetVersions(issue, acceptableTargetVersions(options), options.customFields()); err != nil {
 							response += fmt.Sprintf("\n\nWarning: The referenced jira issue has an invalid target version for the target branch this PR targets: %v.", err)
 						}
 					}
@@ -454,10 +939,11 @@ func handle(jc jiraclient.Client, ghc githubClient, options JiraBranchOptions, l
 			if refBug.IsBug && issue != nil {
 				log = log.WithField("refKey", refBug.Key)
 
-				severity, err := getSimplifiedSeverity(issue)
+				severity, err := getSimplifiedSeverity(issue, options.customFields())
 				if err != nil {
 					return err
 				}
+				bugSeverities = append(bugSeverities, fmt.Sprintf("%s=%s", refBug.Key, severity))
 
 				newSeverityLabel := getSeverityLabel(severity)
 				if newSeverityLabel == labels.SeverityCritical {
@@ -473,50 +959,88 @@ func handle(jc jiraclient.Client, ghc githubClient, options JiraBranchOptions, l
 				}
 
 				var dependents []dependent
-				if options.DependentBugStates != nil || options.DependentBugTargetVersions != nil {
-					for _, link := range issue.Fields.IssueLinks {
-						// identify if bug depends on this link; multiple different types of links may be blocker types; more can be added as they are identified
-						dependsOn := false
-						dependsOn = dependsOn || (link.InwardIssue != nil && link.Type.Name == "Blocks" && link.Type.Inward == "is blocked by")
-						dependsOn = dependsOn || (link.OutwardIssue != nil && link.Type.Name == "Depend" && link.Type.Outward == "depends on")
-						if !dependsOn {
-							continue
-						}
-						// link may be either an outward or inward issue; depends on the link type
-						linkIssue := link.InwardIssue
-						if linkIssue == nil {
-							linkIssue = link.OutwardIssue
-						}
-						// the issue in the link is very trimmed down; get full link for dependentIssue list
-						dependentIssue, err := jc.GetIssue(linkIssue.Key)
-						if err != nil {
-							return comment(formatError(fmt.Sprintf("searching for dependent bug %s", linkIssue.Key), jc.JiraURL(), refBug.Key, err))
-						}
-						targetVersion, err := helpers.GetIssueTargetVersion(dependentIssue)
-						if err != nil {
-							return comment(formatError(fmt.Sprintf("failed to get target version for %s", dependentIssue.Key), jc.JiraURL(), refBug.Key, err))
-						}
-						var targetVersionString *string
-						if len(targetVersion) != 0 {
-							targetVersionString = &targetVersion[0].Name
-						}
-						dependentState := JiraBugState{}
-						if dependentIssue.Fields.Status != nil {
-							dependentState.Status = dependentIssue.Fields.Status.Name
-						}
-						if dependentIssue.Fields.Resolution != nil {
-							dependentState.Resolution = dependentIssue.Fields.Resolution.Name
-						}
-						newDependent := dependent{
-							key:           dependentIssue.Key,
-							targetVersion: targetVersionString,
-							bugState:      dependentState,
-						}
-						dependents = append(dependents, newDependent)
+				if dependentValidationsConfigured(options) {
+					var action string
+					dependents, action, err = dependentsOf(e, ghc, jc, issue, options, allRepos)
+					if err != nil {
+						return comment(formatError(action, jc.JiraURL(), refBug.Key, err))
+					}
+				}
+
+				var onBoard *bool
+				if options.RequiredBoardID != nil {
+					isOnBoard, err := ac.IsIssueOnBoard(*options.RequiredBoardID, issue.Key)
+					if err != nil {
+						log.WithError(err).Warn("Unexpected error checking the board membership of the Jira issue.")
+						return comment(formatError(fmt.Sprintf("checking whether the bug is on board %d", *options.RequiredBoardID), jc.JiraURL(), refBug.Key, err))
 					}
+					onBoard = &isOnBoard
 				}
 
-				valid, validationsRun, why := validateBug(issue, dependents, options, jc.JiraURL())
+				validationOptions := options
+				if options.RequireKnownTargetVersion != nil && *options.RequireKnownTargetVersion && options.KnownTargetVersions == nil {
+					versions, err := ac.ProjectVersions(projectFromKey(issue.Key))
+					if err != nil {
+						log.WithError(err).Warn("Unexpected error listing the versions of the Jira project.")
+						return comment(formatError(fmt.Sprintf("listing the versions of the %s project", projectFromKey(issue.Key)), jc.JiraURL(), refBug.Key, err))
+					}
+					// the known versions are fetched per bug, as the referenced bugs may be in different projects
+					validationOptions.KnownTargetVersions = &versions
+				}
+				valid, validationsRun, why := validateBug(issue, dependents, onBoard, validationOptions, e.baseRef, jc.JiraURL())
+				if options.ValidationJQL != nil && *options.ValidationJQL != "" {
+					matches, err := ac.IssueMatchesJQL(issue.Key, *options.ValidationJQL)
+					if err != nil {
+						log.WithError(err).Warn("Unexpected error searching for the Jira issue with the validation filter.")
+						return comment(formatError("checking whether the bug matches the validation filter", jc.JiraURL(), refBug.Key, err))
+					}
+					if matches {
+						validationsRun = append(validationsRun, "bug matches the validation filter")
+					} else {
+						valid = false
+						message := "expected the bug to match the validation filter, but it does not"
+						if options.ValidationJQLMessage != nil && *options.ValidationJQLMessage != "" {
+							message = *options.ValidationJQLMessage
+						}
+						why = append(why, message)
+					}
+				}
+				bugState := issueState(issue)
+				var contributorsWarning string
+				if options.ContributorsField != nil && *options.ContributorsField != "" {
+					author, included, err := contributorsIncludeAuthor(ghc, e, issue, *options.ContributorsField)
+					if err != nil {
+						log.WithError(err).Warn("Unexpected error checking the contributors of the bug.")
+						return comment(formatError("checking whether the contributors of the bug include the author of the pull request", jc.JiraURL(), refBug.Key, err))
+					}
+					switch {
+					case included:
+						validationsRun = append(validationsRun, fmt.Sprintf("bug's contributors include the author of this pull request (@%s)", author))
+					case options.RequireAuthorInContributors != nil && *options.RequireAuthorInContributors:
+						valid = false
+						why = append(why, fmt.Sprintf("expected the bug's contributors to include the author of this pull request (@%s), but none of them has a public email matching that GitHub user", author))
+					default:
+						contributorsWarning = fmt.Sprintf("\n\nWARNING: None of the contributors of %s has a public email matching the author of this pull request (@%s). Please add the author to the contributors of the bug.", refBug.Key, author)
+					}
+				}
+				var testPRWarning string
+				if options.TestRepos != nil && len(*options.TestRepos) > 0 {
+					testPR, err := linkedTestPR(jc, issue, *options.TestRepos)
+					if err != nil {
+						log.WithError(err).Warn("Unexpected error checking the pull requests linked to the bug.")
+						return comment(formatError("checking whether the bug links a pull request in a test repo", jc.JiraURL(), refBug.Key, err))
+					}
+					repos := strings.Join(*options.TestRepos, ", ")
+					switch {
+					case testPR != "":
+						validationsRun = append(validationsRun, fmt.Sprintf("bug links the pull request %s in a test repo", testPR))
+					case options.RequireTestPR != nil && *options.RequireTestPR:
+						valid = false
+						why = append(why, fmt.Sprintf("expected the bug to link a pull request in a repo matching one of %s, but it does not", repos))
+					default:
+						testPRWarning = fmt.Sprintf("\n\nWARNING: %s does not link a pull request in a repo matching one of %s. Please link the pull request adding tests for the fix to the bug.", refBug.Key, repos)
+					}
+				}
 				if !needsJiraInvalidBugLabel {
 					needsJiraValidBugLabel, needsJiraInvalidBugLabel = valid, !valid
 				}
@@ -526,33 +1050,68 @@ func handle(jc jiraclient.Client, ghc githubClient, options JiraBranchOptions, l
 					// if configured, move the bug to the new state
 					if options.StateAfterValidation != nil {
 						if options.StateAfterValidation.Status != "" && (issue.Fields.Status == nil || !strings.EqualFold(options.StateAfterValidation.Status, issue.Fields.Status.Name)) {
-							if err := jc.UpdateStatus(issue.ID, options.StateAfterValidation.Status); err != nil {
-								log.WithError(err).Warn("Unexpected error updating jira issue.")
-								return comment(formatError(fmt.Sprintf("updating to the %s state", options.StateAfterValidation.Status), jc.JiraURL(), refBug.Key, err))
+							reachable, available, err := isStatusReachable(jc, issue.ID, options.StateAfterValidation.Status)
+							if err != nil {
+								log.WithError(err).Warn("Unexpected error getting transitions for jira issue.")
+								return comment(formatError("getting the available transitions", jc.JiraURL(), refBug.Key, err))
 							}
-							if options.StateAfterValidation.Resolution != "" && (issue.Fields.Resolution == nil || !strings.EqualFold(options.StateAfterValidation.Resolution, issue.Fields.Resolution.Name)) {
-								updateIssue := jira.Issue{Key: issue.Key, Fields: &jira.IssueFields{Resolution: &jira.Resolution{Name: options.StateAfterValidation.Resolution}}}
-								if _, err := jc.UpdateIssue(&updateIssue); err != nil {
+							if reachable {
+								if err := jc.UpdateStatus(issue.ID, options.StateAfterValidation.Status); err != nil {
 									log.WithError(err).Warn("Unexpected error updating jira issue.")
-									return comment(formatError(fmt.Sprintf("updating to the %s resolution", options.StateAfterValidation.Resolution), jc.JiraURL(), refBug.Key, err))
+									return comment(formatError(fmt.Sprintf("updating to the %s state", options.StateAfterValidation.Status), jc.JiraURL(), refBug.Key, err))
+								}
+								if options.StateAfterValidation.Resolution != "" && (issue.Fields.Resolution == nil || !strings.EqualFold(options.StateAfterValidation.Resolution, issue.Fields.Resolution.Name)) {
+									updateIssue := jira.Issue{Key: issue.Key, Fields: &jira.IssueFields{Resolution: &jira.Resolution{Name: options.StateAfterValidation.Resolution}}}
+									if _, err := jc.UpdateIssue(&updateIssue); err != nil {
+										log.WithError(err).Warn("Unexpected error updating jira issue.")
+										return comment(formatError(fmt.Sprintf("updating to the %s resolution", options.StateAfterValidation.Resolution), jc.JiraURL(), refBug.Key, err))
+									}
+								}
+								if options.ValidationTransitionComment != nil && *options.ValidationTransitionComment != "" {
+									body, err := validationTransitionComment(*options.ValidationTransitionComment, issue.Key, e, options.StateAfterValidation.String())
+									if err != nil {
+										log.WithError(err).Warn("Unexpected error rendering the validation transition comment.")
+										return comment(formatError("rendering the comment about the transition", jc.JiraURL(), refBug.Key, err))
+									}
+									if _, err := jc.AddComment(issue.ID, &jira.Comment{Body: body, Visibility: PrivateVisibility}); err != nil {
+										log.WithError(err).Warn("Unexpected error adding comment to jira issue.")
+										return comment(formatError("adding a comment about the transition", jc.JiraURL(), refBug.Key, err))
+									}
 								}
+								response += fmt.Sprintf(" The bug has been moved to the %s state.", options.StateAfterValidation)
+								bugState = options.StateAfterValidation.String()
+							} else {
+								response += fmt.Sprintf(" The bug could not be moved to the %s state because no transition to %s exists. Available transitions: %s.", options.StateAfterValidation, options.StateAfterValidation.Status, strings.Join(available, ", "))
 							}
-							response += fmt.Sprintf(" The bug has been moved to the %s state.", options.StateAfterValidation)
 						}
 					}
 
-					response += "\n\n<details>"
-					if len(validationsRun) == 0 {
-						response += "<summary>No validations were run on this bug</summary>"
-					} else {
-						response += fmt.Sprintf("<summary>%d validation(s) were run on this bug</summary>\n", len(validationsRun))
-					}
-					for _, validation := range validationsRun {
-						response += fmt.Sprint("\n* ", validation)
+					hideValidations := len(validationsRun) == 0 && options.HideEmptyValidations != nil && *options.HideEmptyValidations
+					if !hideValidations {
+						response += "\n\n<details>"
+						switch len(validationsRun) {
+						case 0:
+							if validationsConfigured(options) {
+								response += "<summary>No validations applied to this bug</summary>\n\nThe requirements configured for this branch did not apply to this bug, so none of them were checked."
+							} else {
+								response += "<summary>No validations were run on this bug</summary>"
+							}
+						case 1:
+							response += "<summary>1 validation was run on this bug</summary>\n"
+						default:
+							response += fmt.Sprintf("<summary>%d validations were run on this bug</summary>\n", len(validationsRun))
+						}
+						listed, omitted := truncateList(validationsRun, maxListedValidations(options))
+						for _, validation := range listed {
+							response += fmt.Sprint("\n* ", validation)
+						}
+						if omitted > 0 {
+							response += fmt.Sprintf("\n* ...and %d more", omitted)
+						}
+						response += "</details>"
 					}
-					response += "</details>"
 
-					qaContactDetail, err := helpers.GetIssueQaContact(issue)
+					qaContactDetail, err := helpers.GetIssueQaContact(issue, options.customFields())
 					if err != nil {
 						return comment(formatError("processing qa contact information for the bug", jc.JiraURL(), refBug.Key, err))
 					}
@@ -561,7 +1120,9 @@ func handle(jc jiraclient.Client, ghc githubClient, options JiraBranchOptions, l
 							response += fmt.Sprintf(issueLink+" does not have a QA contact, skipping assignment", refBug.Key, jc.JiraURL(), refBug.Key)
 						}
 					} else if qaContactDetail.EmailAddress == "" {
-						if e.cc {
+						if login := accountIDLogin(options, qaContactDetail); login != "" {
+							response += fmt.Sprint("\n\n", qaContactReviewRequest(login))
+						} else if e.cc {
 							response += fmt.Sprintf("QA contact for "+issueLink+" does not have a listed email, skipping assignment", refBug.Key, jc.JiraURL(), refBug.Key)
 						}
 					} else {
@@ -575,21 +1136,57 @@ func handle(jc jiraclient.Client, ghc githubClient, options JiraBranchOptions, l
 							log.WithError(err).Error("Failed to run graphql github query")
 							return comment(formatError(fmt.Sprintf("querying GitHub for users with public email (%s)", email), jc.JiraURL(), refBug.Key, err))
 						}
-						response += fmt.Sprint("\n\n", processQuery(query, email, log))
+						if login := accountIDLogin(options, qaContactDetail); len(query.Search.Edges) == 0 && login != "" {
+							// fall back to the configured login before giving up on the review request
+							response += fmt.Sprint("\n\n", qaContactReviewRequest(login))
+						} else {
+							response += fmt.Sprint("\n\n", processQuery(query, email, log))
+						}
 					}
 				} else {
 					log.Debug("Invalid bug found.")
 					var formattedReasons string
-					for _, reason := range why {
+					listed, omitted := truncateList(why, maxListedValidations(options))
+					for _, reason := range listed {
 						formattedReasons += fmt.Sprintf(" - %s\n", reason)
 					}
+					if omitted > 0 {
+						formattedReasons += fmt.Sprintf(" - ...and %d more\n", omitted)
+					}
 					response += fmt.Sprintf(`This pull request references `+issueLink+`, which is invalid:
 %s
 Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.`, refBug.Key, jc.JiraURL(), refBug.Key, formattedReasons)
+					if options.SlackWebhookURL != nil && *options.SlackWebhookURL != "" {
+						invalidBugNotifier.notify(log, *options.SlackWebhookURL, invalidBugSlackMessage(e, refBug.Key, jc.JiraURL(), why))
+					}
+				}
+				response += multipleTargetVersionsWarning(issue, options.customFields())
+				bugStates = append(bugStates, fmt.Sprintf("%s=%s", refBug.Key, bugState))
+				response += contributorsWarning
+				response += testPRWarning
+				response += suspiciousStateWarning(issue, e, options)
+				if valid {
+					response += incompleteSubtasksWarning(issue, options, jc.JiraURL())
+				}
+				if e.validationMarker {
+					response += formatValidationMarker(refBug.Key, valid, why)
 				}
 
 				if options.AddExternalLink != nil && *options.AddExternalLink {
+					// events for the same pull request may be handled concurrently; make sure that
+					// checking for and creating the remote link happens atomically
+					unlock := remoteLinkLocks.lock(issue.Key)
+					if options.LinkedPullRequestsWarningThreshold != nil {
+						warning, err := linkedPullRequestsWarning(jc, issue.ID, e, *options.LinkedPullRequestsWarningThreshold)
+						if err != nil {
+							unlock()
+							log.WithError(err).Warn("Unexpected error getting remote links for Jira issue.")
+							return comment(formatError("getting remote links", jc.JiraURL(), refBug.Key, err))
+						}
+						response += warning
+					}
 					changed, err := upsertGitHubLinkToIssue(log, issue.ID, jc, e)
+					unlock()
 					if err != nil {
 						log.WithError(err).Warn("Unexpected error adding external tracker bug to Jira bug.")
 						return comment(formatError("adding this pull request to the external tracker bugs", jc.JiraURL(), refBug.Key, err))
@@ -600,6 +1197,7 @@ Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jir
 				}
 			}
 		}
+		response += cherrypickRecommendation(e, options)
 	} else {
 		needsJiraValidRefLabel = true
 		response = "This pull request explicitly references no jira issue."
@@ -614,6 +1212,10 @@ Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jir
 	}
 	var hasJiraValidBugLabel, hasJiraValidRefLabel, hasJiraInvalidBugLabel bool
 	var severityLabelToRemove string
+	configuredSeverityLabels := sets.NewString()
+	for _, mapping := range severityLabels {
+		configuredSeverityLabels.Insert(options.severityLabel(mapping.label))
+	}
 	for _, l := range currentLabels {
 		if l.Name == labels.JiraValidBug {
 			hasJiraValidBugLabel = true
@@ -625,11 +1227,7 @@ Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jir
 			hasJiraValidRefLabel = true
 		}
 
-		if l.Name == labels.SeverityCritical ||
-			l.Name == labels.SeverityImportant ||
-			l.Name == labels.SeverityModerate ||
-			l.Name == labels.SeverityLow ||
-			l.Name == labels.SeverityInformational {
+		if configuredSeverityLabels.Has(l.Name) {
 			severityLabelToRemove = l.Name
 		}
 	}
@@ -642,9 +1240,12 @@ To reference a jira issue, add 'XYZ-NNN:' to the title of this pull request and
 		// if the user attempted to reference a jira key, but we couldn't find the key in jira, give feedback to the user.
 		response = fmt.Sprintf("The referenced Jira(s) %v could not be located, all automatically applied jira labels will be removed.", invalidIssues)
 		needsJiraValidRefLabel = false
+	} else if e.missing && severityLabelToRemove == "" {
+		e.noAction(log, "no bug referenced in the title")
 	}
 
 	var labelsChanged bool
+	severityLabel = options.severityLabel(severityLabel)
 	if severityLabelToRemove != "" && severityLabel != severityLabelToRemove {
 		if err := ghc.RemoveLabel(e.org, e.repo, e.number, severityLabelToRemove); err != nil {
 			log.WithError(err).Error("Failed to remove severity bug label.")
@@ -658,7 +1259,19 @@ To reference a jira issue, add 'XYZ-NNN:' to the title of this pull request and
 		labelsChanged = true
 	}
 
-	if hasJiraValidRefLabel && !needsJiraValidRefLabel {
+	if e.fixLabels {
+		// remove all stale severity labels rather than only one of them
+		for _, l := range currentLabels {
+			if configuredSeverityLabels.Has(l.Name) && l.Name != severityLabel && l.Name != severityLabelToRemove {
+				if err := ghc.RemoveLabel(e.org, e.repo, e.number, l.Name); err != nil {
+					log.WithError(err).Error("Failed to remove severity bug label.")
+				}
+				labelsChanged = true
+			}
+		}
+	}
+
+	if hasJiraValidRefLabel && !needsJiraValidRefLabel && !e.fixLabels {
 		humanLabelled, err := ghc.WasLabelAddedByHuman(e.org, e.repo, e.number, labels.JiraValidRef)
 		if err != nil {
 			// Return rather than potentially doing the wrong thing. The user can re-trigger us.
@@ -670,7 +1283,7 @@ To reference a jira issue, add 'XYZ-NNN:' to the title of this pull request and
 		}
 	}
 
-	if hasJiraValidBugLabel && !needsJiraValidBugLabel {
+	if hasJiraValidBugLabel && !needsJiraValidBugLabel && !e.fixLabels {
 		humanLabelled, err := ghc.WasLabelAddedByHuman(e.org, e.repo, e.number, labels.JiraValidBug)
 		if err != nil {
 			// Return rather than potentially doing the wrong thing. The user can re-trigger us.
@@ -702,37 +1315,73 @@ To reference a jira issue, add 'XYZ-NNN:' to the title of this pull request and
 		}
 	}
 
-	if needsJiraValidBugLabel {
-		if !hasJiraValidBugLabel {
-			if err := ghc.AddLabel(e.org, e.repo, e.number, labels.JiraValidBug); err != nil {
-				log.WithError(err).Error("Failed to add valid bug label.")
+	skipValidityLabels := options.SkipValidityLabels != nil && *options.SkipValidityLabels
+	if !skipValidityLabels {
+		if needsJiraValidBugLabel {
+			if !hasJiraValidBugLabel {
+				if err := ghc.AddLabel(e.org, e.repo, e.number, labels.JiraValidBug); err != nil {
+					log.WithError(err).Error("Failed to add valid bug label.")
+				}
+				labelsChanged = true
+			}
+		} else {
+			if hasJiraValidBugLabel {
+				if err := ghc.RemoveLabel(e.org, e.repo, e.number, labels.JiraValidBug); err != nil {
+					log.WithError(err).Error("Failed to remove valid bug label.")
+				}
+				labelsChanged = true
+			}
+		}
+
+		if needsJiraInvalidBugLabel && !hasJiraInvalidBugLabel {
+			if err := ghc.AddLabel(e.org, e.repo, e.number, labels.JiraInvalidBug); err != nil {
+				log.WithError(err).Error("Failed to add invalid bug label.")
+			}
+			labelsChanged = true
+		} else if !needsJiraInvalidBugLabel && hasJiraInvalidBugLabel {
+			if err := ghc.RemoveLabel(e.org, e.repo, e.number, labels.JiraInvalidBug); err != nil {
+				log.WithError(err).Error("Failed to remove invalid bug label.")
 			}
 			labelsChanged = true
 		}
-	} else {
+	} else if e.fixLabels {
+		// the validity labels are not managed for the branch, so any of them are stale
 		if hasJiraValidBugLabel {
 			if err := ghc.RemoveLabel(e.org, e.repo, e.number, labels.JiraValidBug); err != nil {
 				log.WithError(err).Error("Failed to remove valid bug label.")
 			}
 			labelsChanged = true
 		}
+		if hasJiraInvalidBugLabel {
+			if err := ghc.RemoveLabel(e.org, e.repo, e.number, labels.JiraInvalidBug); err != nil {
+				log.WithError(err).Error("Failed to remove invalid bug label.")
+			}
+			labelsChanged = true
+		}
 	}
 
-	if needsJiraInvalidBugLabel && !hasJiraInvalidBugLabel {
-		if err := ghc.AddLabel(e.org, e.repo, e.number, labels.JiraInvalidBug); err != nil {
-			log.WithError(err).Error("Failed to add invalid bug label.")
-		}
-		labelsChanged = true
-	} else if !needsJiraInvalidBugLabel && hasJiraInvalidBugLabel {
-		if err := ghc.RemoveLabel(e.org, e.repo, e.number, labels.JiraInvalidBug); err != nil {
-			log.WithError(err).Error("Failed to remove invalid bug label.")
+	if options.PublishCheckRun != nil && *options.PublishCheckRun && (needsJiraValidBugLabel || needsJiraInvalidBugLabel) {
+		if err := publishCheckRun(ghc, e, needsJiraValidBugLabel, response); err != nil {
+			log.WithError(err).Error("Failed to publish check-run.")
 		}
-		labelsChanged = true
+	}
+
+	if options.ReportSeverityChanges != nil && *options.ReportSeverityChanges && len(bugSeverities) > 0 {
+		response += severityChanges(e, ghc, bugSeverities, jc.JiraURL(), log)
+		response += formatSeverityMarker(bugSeverities)
+	}
+
+	var stateMarker string
+	commentOncePerState := options.CommentOncePerState != nil && *options.CommentOncePerState && len(bugStates) > 0
+	if commentOncePerState {
+		stateMarker = formatStateMarker(bugStates)
+		response += stateMarker
 	}
 
 	var duplicateComment bool
-	// we always want to comment if the labels changed or a refresh was manually triggered
-	if !labelsChanged && !e.refresh {
+	// we always want to comment if the labels changed or a refresh was manually triggered,
+	// unless refreshes should only comment when the state of the bugs changed
+	if !labelsChanged && (!e.refresh || commentOncePerState) {
 		comments, err := ghc.ListIssueComments(e.org, e.repo, e.number)
 		if err != nil {
 			log.WithError(err).Error("Failed to list issue comments.")
@@ -752,39 +1401,145 @@ To reference a jira issue, add 'XYZ-NNN:' to the title of this pull request and
 						break
 					}
 				}
+				lastBotBody := ""
 				if lastBotComment != nil {
+					lastBotBody = lastBotComment.Body
+				}
+				if e.validationReview != "" {
+					// the results may have been posted as a review rather than a comment
+					if body, err := lastBotReviewBody(e, ghc, isBot); err != nil {
+						log.WithError(err).Error("Failed to list reviews.")
+					} else if body != "" {
+						lastBotBody += "\n" + body
+					}
+				}
+				if lastBotBody != "" {
 					// the comment function prepends the user and appends details (which may be different for different events),
 					// so we can't do an exact match. A `strings.Contains` should be good enough
-					if strings.Contains(lastBotComment.Body, response) {
+					if strings.Contains(lastBotBody, response) {
 						duplicateComment = true
 					}
-				}
+					if commentOncePerState && strings.Contains(lastBotBody, stateMarker) {
+						duplicateComment = true
+					}
+				}
 			}
 		}
 	}
 
 	if response != "" && !duplicateComment {
+		if e.validationReview != "" && (needsJiraValidBugLabel || needsJiraInvalidBugLabel) {
+			return reviewValidation(e, ghc, log, response, !needsJiraInvalidBugLabel)
+		}
 		return comment(response)
 	}
 	return nil
 }
 
+// reviewValidation posts the validation results as a pull request review, which requests
+// changes if any of the bugs is invalid so that it can block the pull request
+func reviewValidation(e event, ghc githubClient, log *logrus.Entry, response string, valid bool) error {
+	action := github.ReviewAction(github.RequestChanges)
+	if valid {
+		action = github.Comment
+		if e.validationReview == validationReviewApprove {
+			action = github.Approve
+		}
+		// earlier reviews requesting changes would keep blocking the pull request
+		if err := dismissChangeRequests(e, ghc); err != nil {
+			log.WithError(err).Warn("Failed to dismiss the earlier reviews requesting changes.")
+		}
+	}
+	return ghc.CreateReview(e.org, e.repo, e.number, github.DraftReview{
+		Body:   plugins.FormatResponseRaw(e.body, e.htmlUrl, e.login, response),
+		Action: action,
+	})
+}
+
+// lastBotReviewBody returns the body of the latest review of the pull request by the bot, or an
+// empty string if there is none
+func lastBotReviewBody(e event, ghc githubClient, isBot func(string) bool) (string, error) {
+	reviews, err := ghc.ListReviews(e.org, e.repo, e.number)
+	if err != nil {
+		return "", err
+	}
+	for i := len(reviews) - 1; i >= 0; i-- {
+		if isBot(reviews[i].User.Login) {
+			return reviews[i].Body, nil
+		}
+	}
+	return "", nil
+}
+
+// dismissChangeRequests dismisses the reviews of the bot that request changes on the pull request,
+// which were posted while one of the referenced bugs was invalid
+func dismissChangeRequests(e event, ghc githubClient) error {
+	isBot, err := ghc.BotUserChecker()
+	if err != nil {
+		return fmt.Errorf("failed to create bot user checker: %w", err)
+	}
+	reviews, err := ghc.ListReviews(e.org, e.repo, e.number)
+	if err != nil {
+		return fmt.Errorf("failed to list reviews: %w", err)
+	}
+	for _, review := range reviews {
+		if !isBot(review.User.Login) || review.State != github.ReviewStateChangesRequested {
+			continue
+		}
+		if err := ghc.DismissReview(e.org, e.repo, e.number, review, "The referenced Jira bugs are valid now."); err != nil {
+			return fmt.Errorf("failed to dismiss review %d: %w", review.ID, err)
+		}
+	}
+	return nil
+}
+
+// reviewDismissingClient adds the dismissal of pull request reviews, which the GitHub client does
+// not offer, to a GitHub client by running the GraphQL mutation for it
+type reviewDismissingClient struct {
+	github.Client
+}
+
+func (c reviewDismissingClient) DismissReview(org, repo string, number int, review github.Review, message string) error {
+	var m struct {
+		DismissPullRequestReview struct {
+			ClientMutationID githubql.String
+		} `graphql:"dismissPullRequestReview(input: $input)"`
+	}
+	input := githubql.DismissPullRequestReviewInput{PullRequestReviewID: githubql.ID(review.NodeID), Message: githubql.String(message)}
+	return c.MutateWithGitHubAppsSupport(context.Background(), &m, input, nil, org)
+}
+
+// defaultMaxListedValidations is the number of validations or reasons listed for a bug when
+// MaxListedValidations is not configured
+const defaultMaxListedValidations = 50
+
+// maxListedValidations returns the number of validations or reasons listed for a bug
+func maxListedValidations(options JiraBranchOptions) int {
+	if options.MaxListedValidations != nil {
+		return *options.MaxListedValidations
+	}
+	return defaultMaxListedValidations
+}
+
+// truncateList returns the first max items of the list and the number of items left out
+func truncateList(items []string, max int) ([]string, int) {
+	if max < 1 || len(items) <= max {
+		return items, 0
+	}
+	return items[:max], len(items) - max
+}
+
 // getSimplifiedSeverity retrieves the severity of the issue and trims the image tags that precede
 // the name of the severity, which are a nuisance for automation
-func getSimplifiedSeverity(issue *jira.Issue) (string, error) {
-	severity, err := helpers.GetIssueSeverity(issue)
+func getSimplifiedSeverity(issue *jira.Issue, fields helpers.FieldMap) (string, error) {
+	severity, err := helpers.GetIssueSeverity(issue, fields)
 	if err != nil {
 		return "", fmt.Errorf("Failed to get severity of issue %s", issue.Key)
 	}
 	if severity == nil {
 		return "unset", nil
 	}
-	// the values of the severity fields in redhat jira have an image before them
-	// (ex: <img alt=\"\" src=\"/images/icons/priorities/medium.svg\" width=\"16\" height=\"16\"> Medium)
-	// we need to trim that off before returning. There is always a space between the image and the text,
-	// so we can split on spaces and take the last element
-	splitSeverity := strings.Split(severity.Value, " ")
-	return splitSeverity[len(splitSeverity)-1], nil
+	return helpers.SimplifiedSeverity(severity.Value), nil
 }
 
 func isPreMergeVerified(issue *jira.Issue, prLabels []github.Label) bool {
@@ -912,6 +1667,152 @@ func prURLFromCommentURL(url string) string {
 	return newURL
 }
 
+// remoteLinkLocks serializes the handling of remote links per Jira issue within this process
+var remoteLinkLocks = &keyedMutex{locks: map[string]*refCountedMutex{}}
+
+// keyedMutex provides a mutex per key. Mutexes are removed once no one holds or waits for them.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*refCountedMutex
+}
+
+type refCountedMutex struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks the mutex for the key and returns the function to unlock it
+func (k *keyedMutex) lock(key string) func() {
+	k.mu.Lock()
+	m, ok := k.locks[key]
+	if !ok {
+		m = &refCountedMutex{}
+		k.locks[key] = m
+	}
+	m.refs++
+	k.mu.Unlock()
+
+	m.Lock()
+	return func() {
+		m.Unlock()
+		k.mu.Lock()
+		m.refs--
+		if m.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// linkedPullRequestsWarning returns a warning listing the pull requests the issue is already linked
+// to if there are more of them than the threshold and the pull request of the event is not one of them.
+func linkedPullRequestsWarning(jc jiraclient.Client, issueID string, e event, threshold int) (string, error) {
+	links, err := jc.GetRemoteLinks(issueID)
+	if err != nil {
+		return "", err
+	}
+	url := prURLFromCommentURL(e.htmlUrl)
+	var linked []string
+	for _, link := range links {
+		if link.Object == nil || !strings.HasPrefix(link.Object.URL, "https://github.com/") {
+			continue
+		}
+		if link.Object.URL == url {
+			// the pull request is already linked, so no link is being added
+			return "", nil
+		}
+		linked = append(linked, fmt.Sprintf("[%s](%s)", link.Object.Title, link.Object.URL))
+	}
+	if len(linked) <= threshold {
+		return "", nil
+	}
+	return fmt.Sprintf("\n\nWarning: The referenced bug is already linked to %d pull requests, which is more than the %d expected for this repository. Please make sure that this pull request references the correct bug. The bug is already linked to:\n- %s", len(linked), threshold, strings.Join(linked, "\n- ")), nil
+}
+
+// gitHubLinkTitle returns the title of the remote link to the pull request
+func gitHubLinkTitle(e event) string {
+	return fmt.Sprintf("%s/%s#%d: %s", e.org, e.repo, e.number, e.title)
+}
+
+// handleTitleChange updates the title of the existing remote links to the pull request, found by
+// its URL, after the title of the pull request changed without changing the referenced bugs.
+// Links are not created here, as that is up to the validation of the bugs.
+func handleTitleChange(e event, jc jiraclient.Client, log *logrus.Entry) error {
+	url := prURLFromCommentURL(e.htmlUrl)
+	title := gitHubLinkTitle(e)
+	for _, refBug := range e.bugs {
+		issue, err := jc.GetIssue(refBug.Key)
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", refBug.Key, err)
+		}
+		unlock := remoteLinkLocks.lock(issue.Key)
+		links, err := jc.GetRemoteLinks(issue.ID)
+		if err != nil {
+			unlock()
+			return fmt.Errorf("failed to get remote links of %s: %w", refBug.Key, err)
+		}
+		for _, link := range links {
+			if link.Object == nil || link.Object.URL != url || link.Object.Title == title {
+				continue
+			}
+			link, object := link, *link.Object
+			object.Title = title
+			link.Object = &object
+			if err := jc.UpdateRemoteLink(issue.ID, &link); err != nil {
+				unlock()
+				return fmt.Errorf("failed to update the remote link of %s: %w", refBug.Key, err)
+			}
+			log.WithField("issue", issue.Key).Info("Updated the title of the jira link")
+		}
+		unlock()
+	}
+	return nil
+}
+
+// handleSynchronize prunes the remote links to specific commits of the pull request, which may no
+// longer exist after a force-push, and ensures that the link to the pull request itself exists
+// instead if any were pruned or the branch is configured to add external links.
+func handleSynchronize(e event, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry) error {
+	commitsURL := prURLFromCommentURL(e.htmlUrl) + "/commits/"
+	for _, refBug := range e.bugs {
+		if !refBug.IsBug {
+			continue
+		}
+		issue, err := jc.GetIssue(refBug.Key)
+		if err != nil {
+			// missing bugs are reported when the pull request is validated, not on every push
+			log.WithError(err).WithField("issue", refBug.Key).Debug("Skipping the remote links of a bug that could not be looked up.")
+			continue
+		}
+		unlock := remoteLinkLocks.lock(issue.Key)
+		links, err := jc.GetRemoteLinks(issue.ID)
+		if err != nil {
+			unlock()
+			return fmt.Errorf("failed to get remote links of %s: %w", refBug.Key, err)
+		}
+		var pruned bool
+		for _, link := range links {
+			if link.Object == nil || !strings.HasPrefix(link.Object.URL, commitsURL) {
+				continue
+			}
+			if err := jc.DeleteRemoteLink(issue.ID, link.ID); err != nil {
+				unlock()
+				return fmt.Errorf("failed to remove the remote link to %s from %s: %w", link.Object.URL, refBug.Key, err)
+			}
+			log.WithField("issue", issue.Key).Infof("Removed the stale jira link to %s", link.Object.URL)
+			pruned = true
+		}
+		if pruned || (options.AddExternalLink != nil && *options.AddExternalLink) {
+			if _, err := upsertGitHubLinkToIssue(log, issue.ID, jc, e); err != nil {
+				unlock()
+				return fmt.Errorf("failed to link the pull request on %s: %w", refBug.Key, err)
+			}
+		}
+		unlock()
+	}
+	return nil
+}
+
 // upsertGitHubLinkToIssue adds a remote link to the github issue on the jira issue. It returns a bool indicating whether or not the
 // remote link changed or was created, and an error.
 func upsertGitHubLinkToIssue(log *logrus.Entry, issueID string, jc jiraclient.Client, e event) (bool, error) {
@@ -921,7 +1822,7 @@ func upsertGitHubLinkToIssue(log *logrus.Entry, issueID string, jc jiraclient.Cl
 	}
 
 	url := prURLFromCommentURL(e.htmlUrl)
-	title := fmt.Sprintf("%s/%s#%d: %s", e.org, e.repo, e.number, e.title)
+	title := gitHubLinkTitle(e)
 	var existingLink *jira.RemoteLink
 
 	// Check if the same link exists already. We consider two links to be the same if the have the same URL.
@@ -972,13 +1873,127 @@ func (s *server) handlePullRequest(l *logrus.Entry, pre github.PullRequestEvent)
 	if err != nil {
 		l.Errorf("failed to digest PR: %v", err)
 	}
+	if event == nil && err == nil && s.logNoAction {
+		logNoAction(l, "unrelated event")
+	}
 	if event != nil {
-		if err := handle(s.jc, s.ghc, options, l, *event, s.prowConfigAgent.Config().AllRepos); err != nil {
+		s.configurePullRequestEvent(cfg, event)
+		jc, ghc := s.metrics.instrument(s.jiraClientForOrg(cfg, event.org), s.ghc)
+		if err := handle(jc, ghc, &jiraAgileClient{jc: jc}, options, l, *event, s.prowConfigAgent.Config().AllRepos, sets.NewString(cfg.TrackedProjects...)); err != nil {
 			l.Errorf("failed to handle PR: %v", err)
 		}
 	}
 }
 
+// configureEvent applies the server settings to an event digested from a GitHub event
+func (s *server) configureEvent(e *event) {
+	e.validationMarker = s.validationMarker
+	e.validationReview = s.validationReview
+	e.logNoAction = s.logNoAction
+}
+
+// configurePullRequestEvent applies the server settings to an event digested from a pull
+// request, which is skipped if the pull request was authored by an exempt bot
+func (s *server) configurePullRequestEvent(cfg *Config, e *event) {
+	s.configureEvent(e)
+	// commands are explicit requests, so only pull request events of exempt bots are skipped
+	e.exemptBot = cfg.IsExemptBot(e.org, e.repo, e.login)
+}
+
+// handleResync reprocesses a single pull request identified by the `org`, `repo` and `number`
+// query parameters without requiring a GitHub event. The request must carry the configured
+// resync token as a bearer token. The comments posted while handling the pull request are
+// returned in the response body.
+func (s *server) handleResync(w http.ResponseWriter, r *http.Request) {
+	l := logrus.WithField("handler", "resync")
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	// an empty token would match requests without any token
+	if s.resyncToken == nil || len(bytes.TrimSpace(s.resyncToken())) == 0 {
+		http.Error(w, "resync endpoint is not configured", http.StatusForbidden)
+		return
+	}
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") || subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authorization, "Bearer ")), bytes.TrimSpace(s.resyncToken())) != 1 {
+		http.Error(w, "invalid resync token", http.StatusUnauthorized)
+		return
+	}
+	query := r.URL.Query()
+	org, repo := query.Get("org"), query.Get("repo")
+	if org == "" || repo == "" {
+		http.Error(w, "the org and repo parameters are required", http.StatusBadRequest)
+		return
+	}
+	number, err := strconv.Atoi(query.Get("number"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid pull request number %q: %v", query.Get("number"), err), http.StatusBadRequest)
+		return
+	}
+	l = l.WithFields(logrus.Fields{github.OrgLogField: org, github.RepoLogField: repo, github.PrLogField: number})
+	comments, err := s.resync(l, org, repo, number)
+	if err != nil {
+		l.WithError(err).Error("Failed to resync pull request.")
+		http.Error(w, fmt.Sprintf("failed to resync %s/%s#%d: %v", org, repo, number, err), http.StatusInternalServerError)
+		return
+	}
+	if len(comments) == 0 {
+		fmt.Fprintf(w, "No action was taken for %s/%s#%d.\n", org, repo, number)
+		return
+	}
+	fmt.Fprintln(w, strings.Join(comments, "\n\n"))
+}
+
+// resync fetches the pull request and handles it as if a GitHub event had been received for it,
+// returning the comments that were posted as a result.
+func (s *server) resync(l *logrus.Entry, org, repo string, number int) ([]string, error) {
+	pr, err := s.ghc.GetPullRequest(org, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	action := github.PullRequestActionEdited
+	if pr.State == github.PullRequestStateClosed {
+		action = github.PullRequestActionClosed
+	}
+	pre := github.PullRequestEvent{Action: action, Number: number, PullRequest: *pr}
+	cfg := s.config()
+	options := cfg.OptionsForBranch(org, repo, pr.Base.Ref)
+	event, err := digestPR(l, pre, options.ValidateByDefault)
+	if err != nil {
+		return nil, fmt.Errorf("failed to digest PR: %w", err)
+	}
+	if event == nil {
+		return nil, nil
+	}
+	// a resync is an explicit request from an operator, so it should always report its outcome
+	event.refresh = true
+	s.configurePullRequestEvent(cfg, event)
+	jc, ghc := s.metrics.instrument(s.jiraClientForOrg(cfg, org), s.ghc)
+	recorder := &commentRecordingClient{githubClient: ghc}
+	if err := handle(jc, recorder, &jiraAgileClient{jc: jc}, options, l, *event, s.prowConfigAgent.Config().AllRepos, sets.NewString(cfg.TrackedProjects...)); err != nil {
+		return recorder.comments, fmt.Errorf("failed to handle PR: %w", err)
+	}
+	return recorder.comments, nil
+}
+
+// commentRecordingClient records all comments and reviews created through it before passing
+// them on to the wrapped client.
+type commentRecordingClient struct {
+	githubClient
+	comments []string
+}
+
+func (c *commentRecordingClient) CreateComment(owner, repo string, number int, comment string) error {
+	c.comments = append(c.comments, comment)
+	return c.githubClient.CreateComment(owner, repo, number, comment)
+}
+
+func (c *commentRecordingClient) CreateReview(org, repo string, number int, r github.DraftReview) error {
+	c.comments = append(c.comments, r.Body)
+	return c.githubClient.CreateReview(org, repo, number, r)
+}
+
 func getCherryPickMatch(pre github.PullRequestEvent) (bool, int, error) {
 	cherrypickMatch := cherrypickPRMatch.FindStringSubmatch(pre.PullRequest.Body)
 	if cherrypickMatch != nil {
@@ -1000,11 +2015,15 @@ func digestPR(log *logrus.Entry, pre github.PullRequestEvent, validateByDefault
 		pre.Action != github.PullRequestActionEdited &&
 		pre.Action != github.PullRequestActionClosed &&
 		pre.Action != github.PullRequestActionLabeled &&
-		pre.Action != github.PullRequestActionUnlabeled {
+		pre.Action != github.PullRequestActionUnlabeled &&
+		pre.Action != github.PullRequestActionReadyForReview &&
+		pre.Action != github.PullRequestActionConvertedToDraft &&
+		pre.Action != github.PullRequestActionSynchronize {
 		return nil, nil
 	}
 
-	if (pre.Action == github.PullRequestActionLabeled || pre.Action == github.PullRequestActionUnlabeled) && pre.Label.Name != labels.QEApproved {
+	labelEvent := pre.Action == github.PullRequestActionLabeled || pre.Action == github.PullRequestActionUnlabeled
+	if labelEvent && pre.Label.Name != labels.QEApproved && !validityLabels.Has(pre.Label.Name) {
 		return nil, nil
 	}
 
@@ -1017,10 +2036,14 @@ func digestPR(log *logrus.Entry, pre github.PullRequestEvent, validateByDefault
 		body    = pre.PullRequest.Body
 	)
 
-	e := &event{org: org, repo: repo, baseRef: baseRef, number: number, merged: pre.PullRequest.Merged, closed: pre.Action == github.PullRequestActionClosed, opened: pre.Action == github.PullRequestActionOpened, state: pre.PullRequest.State, body: body, title: title, htmlUrl: pre.PullRequest.HTMLURL, login: pre.PullRequest.User.Login}
+	e := &event{org: org, repo: repo, baseRef: baseRef, number: number, merged: pre.PullRequest.Merged, closed: pre.Action == github.PullRequestActionClosed, opened: pre.Action == github.PullRequestActionOpened, state: pre.PullRequest.State, body: body, title: title, htmlUrl: pre.PullRequest.HTMLURL, login: pre.PullRequest.User.Login, draft: pre.PullRequest.Draft}
 	// Make sure the PR title is referencing a bug
 	var err error
 	e.bugs, e.missing, e.noJira = jiraKeyFromTitle(title)
+	e.revert = isRevertTitle(title)
+	if labelEvent && validityLabels.Has(pre.Label.Name) {
+		e.labelChangedBy = pre.Sender.Login
+	}
 
 	// Check if PR is a cherrypick
 	cherrypick, cherrypickFromPRNum, err := getCherryPickMatch(pre)
@@ -1042,6 +2065,35 @@ func digestPR(log *logrus.Entry, pre github.PullRequestEvent, validateByDefault
 		return e, nil
 	}
 
+	if pre.Action == github.PullRequestActionConvertedToDraft {
+		// the referenced bugs are not re-validated, but the author is told that they will
+		// not be moved until the pull request is ready for review again
+		if e.missing || e.noJira {
+			return nil, nil
+		}
+		e.convertedToDraft = true
+		return e, nil
+	}
+
+	if pre.Action == github.PullRequestActionReadyForReview {
+		// a draft that is marked as ready for review is re-evaluated, so that any state
+		// transition that did not happen yet is performed now
+		if e.missing && (validateByDefault == nil || !*validateByDefault) {
+			return nil, nil
+		}
+		return e, nil
+	}
+
+	if pre.Action == github.PullRequestActionSynchronize {
+		// pushes do not change the referenced bugs, but may leave remote links to commits
+		// that no longer exist after a force-push
+		if e.missing || e.noJira {
+			return nil, nil
+		}
+		e.synchronized = true
+		return e, nil
+	}
+
 	// when exiting early from errors trying to find out if the PR previously referenced a bug,
 	// we want to handle the event only if a bug is currently referenced or we are validating by
 	// default
@@ -1076,6 +2128,11 @@ func digestPR(log *logrus.Entry, pre github.PullRequestEvent, validateByDefault
 			}
 		}
 		if !changed {
+			if pre.Action == github.PullRequestActionEdited && changes.Title.From != title {
+				// the title of the remote links to the pull request needs to follow the new title
+				e.titleChanged = true
+				return e, nil
+			}
 			logrus.Debugf("Referenced Jira issue(s) (%+v) has not changed, not handling event.", e.bugs)
 			return nil, nil
 		}
@@ -1087,16 +2144,40 @@ func digestPR(log *logrus.Entry, pre github.PullRequestEvent, validateByDefault
 }
 
 // digestComment determines if any action is necessary and creates the objects for handle() if it is
-func digestComment(gc githubClient, log *logrus.Entry, ice github.IssueCommentEvent) (*event, error) {
+func digestComment(gc githubClient, cfg *Config, log *logrus.Entry, ice github.IssueCommentEvent) (*event, error) {
 	// Only consider new comments.
 	if ice.Action != github.IssueCommentActionCreated {
 		return nil, nil
 	}
 	// Make sure they are requesting a valid command
-	var refresh, cc, cherrypick bool
+	var refresh, cc, cherrypick, retry, listPRs, deps, verify, addLink, relabel, fixLabels, fixVersion, severityMap, history, reclone, simulateMerge bool
 	switch {
 	case refreshCommandMatch.MatchString(ice.Comment.Body):
 		refresh = true
+	case retryCommandMatch.MatchString(ice.Comment.Body):
+		retry = true
+	case listPRsCommandMatch.MatchString(ice.Comment.Body):
+		listPRs = true
+	case depsCommandMatch.MatchString(ice.Comment.Body):
+		deps = true
+	case verifyCommandMatch.MatchString(ice.Comment.Body):
+		verify = true
+	case addLinkCommandMatch.MatchString(ice.Comment.Body):
+		addLink = true
+	case relabelCommandMatch.MatchString(ice.Comment.Body):
+		relabel = true
+	case fixLabelsCommandMatch.MatchString(ice.Comment.Body):
+		fixLabels = true
+	case fixVersionCommandMatch.MatchString(ice.Comment.Body):
+		fixVersion = true
+	case severityMapCommandMatch.MatchString(ice.Comment.Body):
+		severityMap = true
+	case historyCommandMatch.MatchString(ice.Comment.Body):
+		history = true
+	case simulateMergeMatch.MatchString(ice.Comment.Body):
+		simulateMerge = true
+	case recloneCommandMatch.MatchString(ice.Comment.Body):
+		reclone = true
 	case qaReviewCommandMatch.MatchString(ice.Comment.Body):
 		cc = true
 	case cherrypickCommandMatch.MatchString(ice.Comment.Body):
@@ -1110,21 +2191,55 @@ func digestComment(gc githubClient, log *logrus.Entry, ice github.IssueCommentEv
 		number = ice.Issue.Number
 	)
 
+	// the severity mapping does not depend on the referenced bugs, so it is answered right away
+	if severityMap {
+		return nil, gc.CreateComment(org, repo, number, plugins.FormatResponseRaw(ice.Comment.Body, ice.Comment.HTMLURL, ice.Comment.User.Login, severityMapResponse(cfg.OptionsForBranch(org, repo, ""))))
+	}
+
 	// We don't support linking issues to OCPBUGS
 	if !ice.Issue.IsPullRequest() {
 		log.Debug("Jira bug command requested on an issue, ignoring")
 		return nil, gc.CreateComment(org, repo, number, plugins.FormatResponseRaw(ice.Comment.Body, ice.Comment.HTMLURL, ice.Comment.User.Login, `Jira bug referencing is only supported for Pull Requests, not issues.`))
 	}
 
+	// the history is read from the prior comments of the bot, so it does not depend on the referenced bugs either
+	if history {
+		response, err := historyResponse(gc, org, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		return nil, gc.CreateComment(org, repo, number, plugins.FormatResponseRaw(ice.Comment.Body, ice.Comment.HTMLURL, ice.Comment.User.Login, response))
+	}
+
+	// privileged commands may be limited to an allowlist of users for the repo
+	if verify || addLink || reclone || fixLabels || fixVersion || simulateMerge {
+		allowed, err := privilegedCommandAllowed(gc, cfg, org, repo, ice.Comment.User.Login)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, gc.CreateComment(org, repo, number, plugins.FormatResponseRaw(ice.Comment.Body, ice.Comment.HTMLURL, ice.Comment.User.Login, fmt.Sprintf("You are not allowed to run privileged <code>/jira</code> commands in %s/%s. Ask one of the users allowed to run them to do it for you, or ask a maintainer to add you to the allowlist in the plugin configuration.", org, repo)))
+		}
+	}
+
 	// Make sure the PR title is referencing a bug
 	pr, err := gc.GetPullRequest(org, repo, number)
 	if err != nil {
 		return nil, err
 	}
 
-	e := &event{org: org, repo: repo, baseRef: pr.Base.Ref, number: number, merged: pr.Merged, state: pr.State, body: ice.Comment.Body, title: ice.Issue.Title, htmlUrl: ice.Comment.HTMLURL, login: ice.Comment.User.Login, refresh: refresh, cc: cc}
+	e := &event{org: org, repo: repo, baseRef: pr.Base.Ref, number: number, merged: pr.Merged, state: pr.State, body: ice.Comment.Body, title: ice.Issue.Title, htmlUrl: ice.Comment.HTMLURL, login: ice.Comment.User.Login, refresh: refresh, cc: cc, retry: retry, listPRs: listPRs, deps: deps, verify: verify, addLink: addLink, relabel: relabel, fixLabels: fixLabels, fixVersion: fixVersion, simulateMerge: simulateMerge, draft: pr.Draft}
+	if retry {
+		// a retry on a closed pull request re-attempts the transition made on close
+		e.closed = pr.State == github.PullRequestStateClosed
+	}
 
 	e.bugs, e.missing, e.noJira = jiraKeyFromTitle(pr.Title)
+	e.revert = isRevertTitle(pr.Title)
+
+	if reclone {
+		e.recloneParent = strings.ToUpper(recloneCommandMatch.FindStringSubmatch(ice.Comment.Body)[1])
+	}
 
 	if cherrypick {
 		mat := cherrypickCommandMatch.FindStringSubmatch(ice.Comment.Body)
@@ -1133,7 +2248,13 @@ func digestComment(gc githubClient, log *logrus.Entry, ice github.IssueCommentEv
 			return nil, errors.New("failed to get cherrypick string match")
 		}
 		keys := strings.TrimPrefix(strings.TrimRight(mat[0], "\r\n "), "/jira cherrypick ")
+		keys, branches, _ := strings.Cut(keys, " --branches ")
+		if branches != "" {
+			e.cherrypickBranches = strings.Split(branches, ",")
+		}
 		splitKeys := strings.Split(keys, ",")
+		// remember the bugs from the title so that conflicts with the command can be detected
+		e.titleBugs = e.bugs
 		e.bugs = []referencedBug{} // reset bugs list to only include cherrypick comment specified bugs
 		for _, key := range splitKeys {
 			e.bugs = append(e.bugs, referencedBug{
@@ -1148,6 +2269,30 @@ func digestComment(gc githubClient, log *logrus.Entry, ice github.IssueCommentEv
 	return e, nil
 }
 
+// privilegedCommandAllowed determines whether the user may run privileged commands in the repo,
+// i.e. whether no allowlist is configured or the user is on it, directly or through a team.
+func privilegedCommandAllowed(gc githubClient, cfg *Config, org, repo, login string) (bool, error) {
+	users, teams := cfg.PrivilegedCommandAllowlist(org, repo)
+	if users.Len() == 0 && teams.Len() == 0 {
+		return true, nil
+	}
+	for _, user := range users.List() {
+		if github.NormLogin(user) == github.NormLogin(login) {
+			return true, nil
+		}
+	}
+	for _, team := range teams.List() {
+		isMember, err := gc.TeamBySlugHasMember(org, team, login)
+		if err != nil {
+			return false, fmt.Errorf("failed to check whether %s is a member of the %s team: %w", login, team, err)
+		}
+		if isMember {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 type event struct {
 	org, repo, baseRef              string
 	number                          int
@@ -1157,16 +2302,78 @@ type event struct {
 	state                           string
 	body, title, htmlUrl, login     string
 	refresh, cc, cherrypickCmd      bool
-	cherrypick                      bool
-	cherrypickFromPRNum             int
+	retry, listPRs, verify, relabel bool
+	// deps is set for the command listing the dependents of the referenced bugs
+	deps bool
+	// fixLabels is set for the command that relabels the pull request without retaining the
+	// labels that were added manually or are not managed with the current configuration
+	fixLabels bool
+	// fixVersion is set for the command setting the target version of the referenced bugs to
+	// the one configured for the branch before re-validating them
+	fixVersion bool
+	// titleChanged is set for edits of the title that do not change the referenced bugs, which
+	// only require the title of the remote links to the pull request to be updated
+	titleChanged bool
+	// synchronized is set for pushes to the pull request, after which remote links to specific
+	// commits of the pull request may be stale
+	synchronized bool
+	// addLink is set for the command linking the pull request on the referenced bugs regardless
+	// of whether the branch is configured to add external links
+	addLink bool
+	// simulateMerge is set for the command handling the pull request as if it had merged, which
+	// is used to verify the configuration of a branch
+	simulateMerge       bool
+	cherrypick          bool
+	cherrypickFromPRNum int
+	// titleBugs holds the bugs referenced in the title when bugs holds
+	// the bugs given to the cherrypick command instead
+	titleBugs []referencedBug
+	// revert is set for pull requests titled like `Revert "OCPBUGS-123: ..."`, which revert the
+	// fix for the referenced bugs
+	revert bool
+	// cherrypickBranches holds the branches given to the cherrypick command, if any, to clone
+	// the bugs for each of them instead of only for the branch of the pull request
+	cherrypickBranches []string
+	// cherrypickTargetVersions maps the cherrypickBranches to their configured target version;
+	// it is set by the server as resolving it requires the configuration of other branches
+	cherrypickTargetVersions map[string]string
+	// validationMarker is set from the server configuration, see server.validationMarker
+	validationMarker bool
+	// validationReview is set from the server configuration, see server.validationReview
+	validationReview string
+	// logNoAction is set from the server configuration, see server.logNoAction
+	logNoAction bool
+	// exemptBot is set by the server for pull request events of bots whose pull requests are
+	// exempt from the Jira requirements, see JiraRepoOptions.ExemptBotLogins
+	exemptBot bool
+	// draft is set for draft pull requests, whose bugs are not moved after validation until
+	// the pull request is ready for review; convertedToDraft is set when it just became one
+	draft, convertedToDraft bool
+	// recloneParent is the key of the bug given to the reclone command, which replaces the parent
+	// of the clone referenced in the title
+	recloneParent string
+	// labelChangedBy is set to the user who added or removed one of the validity labels when
+	// the event is a label event, so that changes made by the bot itself can be ignored
+	labelChangedBy string
 }
 
+// validityLabels are the labels whose manual changes are handled right away, so that the labels
+// of the pull request are made consistent with the referenced bugs again
+var validityLabels = sets.NewString(labels.JiraValidRef, labels.JiraValidBug, labels.JiraInvalidBug)
+
 func (e *event) comment(gc githubClient) func(body string) error {
 	return func(body string) error {
 		return gc.CreateComment(e.org, e.repo, e.number, plugins.FormatResponseRaw(e.body, e.htmlUrl, e.login, body))
 	}
 }
 
+// noAction logs the reason why no action is taken for the event, if enabled for the server
+func (e *event) noAction(log *logrus.Entry, reason string) {
+	if e.logNoAction {
+		logNoAction(log, reason)
+	}
+}
+
 type queryUser struct {
 	Login githubql.String
 }
@@ -1202,13 +2409,68 @@ type emailToLoginQuery struct {
 	Search querySearch `graphql:"search(type:USER query:$email first:5)"`
 }
 
+// linkedTestPR returns a link to the first pull request linked to the bug through its remote links
+// that is in a repo matching one of the given patterns, or an empty string if there is none.
+func linkedTestPR(jc jiraclient.Client, issue *jira.Issue, patterns []string) (string, error) {
+	links, err := jc.GetRemoteLinks(issue.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the remote links of %s: %w", issue.Key, err)
+	}
+	for _, link := range links {
+		if link.Object == nil {
+			continue
+		}
+		item, isPR, err := prPartsFromURL(link.Object.URL)
+		if !isPR || err != nil {
+			continue
+		}
+		if branchMatchesAny(item.Org+"/"+item.Repo, patterns) {
+			return fmt.Sprintf("[%s/%s#%d](https://github.com/%s/%s/pull/%d)", item.Org, item.Repo, item.Num, item.Org, item.Repo, item.Num), nil
+		}
+	}
+	return "", nil
+}
+
+// contributorsIncludeAuthor determines whether one of the contributors of the bug, as listed in
+// the given custom field, is the author of the pull request. This is the reverse of the QA contact
+// review request: contributors are resolved to GitHub users through their public email.
+func contributorsIncludeAuthor(ghc githubClient, e event, issue *jira.Issue, field string) (string, bool, error) {
+	pr, err := ghc.GetPullRequest(e.org, e.repo, e.number)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get the pull request: %w", err)
+	}
+	author := pr.User.Login
+	contributors, err := helpers.GetIssueContributors(issue, field)
+	if err != nil {
+		return author, false, err
+	}
+	for _, contributor := range contributors {
+		if contributor == nil || contributor.EmailAddress == "" {
+			continue
+		}
+		query := &emailToLoginQuery{}
+		queryVars := map[string]interface{}{
+			"email": githubql.String(contributor.EmailAddress),
+		}
+		if err := ghc.QueryWithGitHubAppsSupport(context.Background(), query, queryVars, e.org); err != nil {
+			return author, false, fmt.Errorf("failed to query GitHub for users with public email (%s): %w", contributor.EmailAddress, err)
+		}
+		for _, edge := range query.Search.Edges {
+			if github.NormLogin(string(edge.Node.User.Login)) == github.NormLogin(author) {
+				return author, true, nil
+			}
+		}
+	}
+	return author, false, nil
+}
+
 // processQueryResult generates a response based on a populated emailToLoginQuery
 func processQuery(query *emailToLoginQuery, email string, log *logrus.Entry) string {
 	switch len(query.Search.Edges) {
 	case 0:
 		return fmt.Sprintf("No GitHub users were found matching the public email listed for the QA contact in Jira (%s), skipping review request.", email)
 	case 1:
-		return fmt.Sprintf("Requesting review from QA contact:\n/cc @%s", query.Search.Edges[0].Node.User.Login)
+		return qaContactReviewRequest(string(query.Search.Edges[0].Node.User.Login))
 	default:
 		response := fmt.Sprintf("Multiple GitHub users were found matching the public email listed for the QA contact in Jira (%s), skipping review request. List of users with matching email:", email)
 		for _, edge := range query.Search.Edges {
@@ -1218,23 +2480,118 @@ func processQuery(query *emailToLoginQuery, email string, log *logrus.Entry) str
 	}
 }
 
+// qaContactReviewRequest requests a review from the QA contact with the given GitHub login
+func qaContactReviewRequest(login string) string {
+	return fmt.Sprintf("Requesting review from QA contact:\n/cc @%s", login)
+}
+
+// accountIDLogin returns the GitHub login configured for the account ID of the Jira user, or an
+// empty string if there is none.
+func accountIDLogin(options JiraBranchOptions, user *jira.User) string {
+	if user.AccountID == "" {
+		return ""
+	}
+	return options.AccountIDLogins[user.AccountID]
+}
+
+// severityLabels maps the severities of Jira bugs to the labels added to the pull requests
+// referencing them, from the most to the least severe
+var severityLabels = []struct{ severity, label string }{
+	{severity: criticalSeverity, label: labels.SeverityCritical},
+	{severity: importantSeverity, label: labels.SeverityImportant},
+	{severity: moderateSeverity, label: labels.SeverityModerate},
+	{severity: lowSeverity, label: labels.SeverityLow},
+	{severity: informationalSeverity, label: labels.SeverityInformational},
+}
+
 func getSeverityLabel(severity string) string {
-	switch severity {
-	case criticalSeverity:
-		return labels.SeverityCritical
-	case importantSeverity:
-		return labels.SeverityImportant
-	case moderateSeverity:
-		return labels.SeverityModerate
-	case lowSeverity:
-		return labels.SeverityLow
-	case informationalSeverity:
-		return labels.SeverityInformational
+	for _, mapping := range severityLabels {
+		if mapping.severity == severity {
+			return mapping.label
+		}
 	}
 	//If we don't understand the severity, don't set it but don't error.
 	return ""
 }
 
+// severityMapResponse describes the severityLabels for the /jira severity-map command, using
+// the severity label prefix configured for the repo
+func severityMapResponse(options JiraBranchOptions) string {
+	response := "The severities of Jira bugs are mapped to the following labels, from the most to the least severe:\n\n| Severity | Label |\n| --- | --- |"
+	for _, mapping := range severityLabels {
+		response += fmt.Sprintf("\n| %s | `%s` |", mapping.severity, options.severityLabel(mapping.label))
+	}
+	return response + "\n\nIf the pull request references several bugs, only the label of the most severe one is added. Bugs with any other severity do not get a severity label."
+}
+
+// maxHistoryEntries is the number of the most recent comments of the bot that are summarized
+// by the /jira history command
+const maxHistoryEntries = 20
+
+var (
+	validatedIssueMatch = regexp.MustCompile(`\[Jira Issue ([^\]]+)\]\([^)]*\), which is (valid|invalid)`)
+	movedIssueMatch     = regexp.MustCompile(`\[Jira Issue ([^\]]+)\]\([^)]*\)[^\n\[]*?\bhas been moved to the ([^\n]+?) state\.`)
+)
+
+// historyResponse summarizes the actions of the bot on the pull request for the /jira history
+// command as a timeline with an entry for each of its comments that validated or moved bugs
+func historyResponse(gc githubClient, org, repo string, number int) (string, error) {
+	comments, err := gc.ListIssueComments(org, repo, number)
+	if err != nil {
+		return "", fmt.Errorf("failed to list comments: %w", err)
+	}
+	isBot, err := gc.BotUserChecker()
+	if err != nil {
+		return "", fmt.Errorf("failed to create bot user checker: %w", err)
+	}
+	var entries []string
+	// comments are returned in order of ID, which is oldest first
+	for _, comment := range comments {
+		if !isBot(comment.User.Login) {
+			continue
+		}
+		if actions := historyActions(comment.Body); len(actions) > 0 {
+			entries = append(entries, fmt.Sprintf(" * [%s](%s): %s", comment.CreatedAt.UTC().Format("2006-01-02 15:04 MST"), comment.HTMLURL, strings.Join(actions, ", ")))
+		}
+	}
+	if len(entries) == 0 {
+		return "The plugin has not validated or moved any bugs on this pull request yet.", nil
+	}
+	response := "The plugin took the following actions on this pull request, from the oldest to the most recent:\n"
+	if len(entries) > maxHistoryEntries {
+		response += fmt.Sprintf(" * ...and %d earlier actions\n", len(entries)-maxHistoryEntries)
+		entries = entries[len(entries)-maxHistoryEntries:]
+	}
+	return response + strings.Join(entries, "\n"), nil
+}
+
+// historyActions lists the validations and transitions of bugs recorded in a comment of the bot.
+// Validation results are read from the validation markers if the comment has any, as they are
+// not affected by changes to the wording of the comments.
+func historyActions(body string) []string {
+	var actions []string
+	markers := parseValidationMarkers(body)
+	for _, marker := range markers {
+		actions = append(actions, fmt.Sprintf("%s was found %s", marker.Key, validity(marker.Valid)))
+	}
+	if len(markers) == 0 {
+		for _, match := range validatedIssueMatch.FindAllStringSubmatch(body, -1) {
+			actions = append(actions, fmt.Sprintf("%s was found %s", match[1], match[2]))
+		}
+	}
+	for _, match := range movedIssueMatch.FindAllStringSubmatch(body, -1) {
+		actions = append(actions, fmt.Sprintf("%s was moved to the %s state", match[1], match[2]))
+	}
+	return actions
+}
+
+func validity(valid bool) string {
+	if valid {
+		return "valid"
+	}
+	return "invalid"
+}
+
 func bugMatchesStates(bug *jira.Issue, states []JiraBugState) bool {
 	if bug == nil {
 		return false
@@ -1268,8 +2625,8 @@ func prettyStates(statuses []JiraBugState) []string {
 	return pretty
 }
 
-// validateBug determines if the bug matches the options and returns a description of why not
-func validateBug(bug *jira.Issue, dependents []dependent, options JiraBranchOptions, jiraEndpoint string) (bool, []string, []string) {
+// validateBug determines if the bug matches the options for the branch and returns a description of why not
+func validateBug(bug *jira.Issue, dependents []dependent, onBoard *bool, options JiraBranchOptions, branch, jiraEndpoint string) (bool, []string, []string) {
 	valid := true
 	var errors []string
 	var validations []string
@@ -1281,7 +2638,7 @@ func validateBug(bug *jira.Issue, dependents []dependent, options JiraBranchOpti
 			not = "not "
 			was = "is"
 		}
-		errors = append(errors, fmt.Sprintf("expected the bug to %sbe open, but it %s", not, was))
+		errors = append(errors, fmt.Sprintf("expected the bug to %sbe open, but it %s", not, was)+fieldEditLink(options, bug.Key, statusField))
 	} else if options.IsOpen != nil {
 		expected := "open"
 		if !*options.IsOpen {
@@ -1294,27 +2651,257 @@ func validateBug(bug *jira.Issue, dependents []dependent, options JiraBranchOpti
 		validations = append(validations, fmt.Sprintf("bug %s open, matching expected state (%s)", was, expected))
 	}
 
-	if options.TargetVersion != nil {
-		if err := validateTargetVersion(bug, *options.TargetVersion); err != nil {
-			errors = append(errors, err.Error())
+	if options.RequireOriginalBug != nil && *options.RequireOriginalBug {
+		if parent := clonedFrom(bug); parent != nil {
+			key := parent.Key
+			if key == "" {
+				key = parent.ID
+			}
+			errors = append(errors, fmt.Sprintf("expected the bug to be the original bug, but it is a clone of "+issueLink+"; reference the original bug instead", key, jiraEndpoint, key))
 			valid = false
 		} else {
-			validations = append(validations, fmt.Sprintf("bug target version (%s) matches configured target version for branch (%s)", *options.TargetVersion, *options.TargetVersion))
+			validations = append(validations, "bug is the original bug rather than a clone")
 		}
 	}
 
-	if options.ValidStates != nil {
-		var allowed []JiraBugState
-		allowed = append(allowed, *options.ValidStates...)
-		if options.StateAfterValidation != nil {
-			stateAlreadyExists := false
-			for _, state := range allowed {
-				if strings.EqualFold(state.Status, options.StateAfterValidation.Status) && strings.EqualFold(state.Resolution, options.StateAfterValidation.Resolution) {
-					stateAlreadyExists = true
-					break
-				}
-			}
-			if !stateAlreadyExists {
+	if options.RequireEnvironmentField != nil && *options.RequireEnvironmentField {
+		if environment := strings.TrimSpace(bug.Fields.Environment); environment == "" {
+			errors = append(errors, "expected the bug to have the environment field set to the affected platform, but it is empty"+fieldEditLink(options, bug.Key, environmentField))
+			valid = false
+		} else {
+			validations = append(validations, fmt.Sprintf("bug has the environment field set (%s)", environment))
+		}
+	}
+
+	if options.RequireQaContact != nil && *options.RequireQaContact {
+		if qaContact, err := helpers.GetIssueQaContact(bug, options.customFields()); err != nil {
+			errors = append(errors, fmt.Sprintf("failed to get the bug's QA contact: %v", err))
+			valid = false
+		} else if qaContact == nil {
+			errors = append(errors, "expected the bug to have a QA contact, but none is set")
+			valid = false
+		} else {
+			validations = append(validations, fmt.Sprintf("bug has a QA contact (%s)", userName(qaContact)))
+		}
+	}
+
+	if options.ExpectedProject != nil {
+		if project := projectFromKey(bug.Key); !strings.EqualFold(project, *options.ExpectedProject) {
+			errors = append(errors, fmt.Sprintf("expected the bug to be in the %s project, but it is in the %s project; reference a bug from the %s project or clone this bug into it", *options.ExpectedProject, project, *options.ExpectedProject))
+			valid = false
+		} else {
+			validations = append(validations, fmt.Sprintf("bug is in the expected project (%s)", *options.ExpectedProject))
+		}
+	}
+
+	if len(options.RequiredLabels) > 0 || len(options.ForbiddenLabels) > 0 {
+		bugLabels := sets.NewString(bug.Fields.Labels...)
+		if len(options.RequiredLabels) > 0 {
+			var missing []string
+			for _, label := range options.RequiredLabels {
+				if !bugLabels.Has(label) {
+					missing = append(missing, label)
+				}
+			}
+			if len(missing) > 0 {
+				errors = append(errors, fmt.Sprintf("expected the bug to have the labels %s, but it is missing %s", strings.Join(options.RequiredLabels, ", "), strings.Join(missing, ", "))+fieldEditLink(options, bug.Key, labelsField))
+				valid = false
+			} else {
+				validations = append(validations, fmt.Sprintf("bug has the required labels (%s)", strings.Join(options.RequiredLabels, ", ")))
+			}
+		}
+		if len(options.ForbiddenLabels) > 0 {
+			if present := bugLabels.Intersection(sets.NewString(options.ForbiddenLabels...)); present.Len() > 0 {
+				errors = append(errors, fmt.Sprintf("expected the bug not to have any of the labels %s, but it has %s", strings.Join(options.ForbiddenLabels, ", "), strings.Join(present.List(), ", "))+fieldEditLink(options, bug.Key, labelsField))
+				valid = false
+			} else {
+				validations = append(validations, fmt.Sprintf("bug has none of the forbidden labels (%s)", strings.Join(options.ForbiddenLabels, ", ")))
+			}
+		}
+	}
+
+	if versions := acceptableTargetVersions(options); len(versions) > 0 {
+		if err := validateTargetVersions(bug, versions, options.customFields()); err != nil {
+			errors = append(errors, err.Error()+fieldEditLink(options, bug.Key, options.customFields().TargetVersion))
+			valid = false
+		} else if len(versions) == 1 {
+			validations = append(validations, fmt.Sprintf("bug target version (%s) matches configured target version for branch (%s)", versions[0], versions[0]))
+		} else {
+			validations = append(validations, fmt.Sprintf("bug target version matches one of the configured target versions for branch (%s)", strings.Join(versions, ", ")))
+		}
+	}
+
+	if options.RequireKnownTargetVersion != nil && *options.RequireKnownTargetVersion && options.KnownTargetVersions != nil {
+		known := sets.NewString(*options.KnownTargetVersions...)
+		project := projectFromKey(bug.Key)
+		targetVersions, err := helpers.GetIssueTargetVersion(bug, options.customFields())
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("failed to get the bug's target version: %v", err))
+			valid = false
+		}
+		for _, version := range targetVersions {
+			if version == nil {
+				continue
+			}
+			if known.Has(version.Name) {
+				validations = append(validations, fmt.Sprintf("target version %s is a known version for project %s", version.Name, project))
+			} else {
+				errors = append(errors, fmt.Sprintf("target version %s is not a known version for project %s", version.Name, project)+fieldEditLink(options, bug.Key, options.customFields().TargetVersion))
+				valid = false
+			}
+		}
+	}
+
+	if options.ValidateBranchTargetConsistency != nil && *options.ValidateBranchTargetConsistency {
+		if expected, ok := options.BranchTargetVersions[branch]; ok {
+			if err := validateTargetVersion(bug, expected, options.customFields()); err != nil {
+				errors = append(errors, fmt.Sprintf("the bug's target version is not consistent with the %q branch, which expects bugs targeting %q: %v", branch, expected, err)+fieldEditLink(options, bug.Key, options.customFields().TargetVersion))
+				valid = false
+			} else {
+				validations = append(validations, fmt.Sprintf("bug target version is consistent with the %q branch, which expects bugs targeting %q", branch, expected))
+			}
+		}
+	}
+
+	if len(options.IssueTypeBranches) > 0 && bug.Fields != nil && bug.Fields.Type.Name != "" {
+		issueType := bug.Fields.Type.Name
+		if patterns, ok := issueTypeBranchPatterns(options, issueType); ok {
+			if branchMatchesAny(branch, patterns) {
+				validations = append(validations, fmt.Sprintf("issues of type %s are allowed on the %q branch", issueType, branch))
+			} else {
+				errors = append(errors, fmt.Sprintf("issues of type %s are not allowed on the %q branch, they are only allowed on branches matching %s", issueType, branch, strings.Join(patterns, ", ")))
+				valid = false
+			}
+		}
+	}
+
+	if len(options.AllowedReporters) > 0 {
+		allowed := sets.NewString(options.AllowedReporters...)
+		if bug.Fields == nil || bug.Fields.Reporter == nil {
+			errors = append(errors, fmt.Sprintf("expected the bug to be reported by one of the allowed reporters (%s), but it has no reporter", strings.Join(options.AllowedReporters, ", ")))
+			valid = false
+		} else if reporter := bug.Fields.Reporter; !allowed.Has(reporter.Name) && !allowed.Has(reporter.AccountID) {
+			name := reporter.Name
+			if name == "" {
+				name = reporter.AccountID
+			}
+			errors = append(errors, fmt.Sprintf("expected the bug to be reported by one of the allowed reporters (%s), but it was reported by %s", strings.Join(options.AllowedReporters, ", "), name))
+			valid = false
+		} else {
+			validations = append(validations, "bug was reported by one of the allowed reporters")
+		}
+	}
+
+	if options.RequiredBoardID != nil {
+		if onBoard == nil || !*onBoard {
+			errors = append(errors, fmt.Sprintf("expected the bug to be on board %d, but it is not", *options.RequiredBoardID))
+			valid = false
+		} else {
+			validations = append(validations, fmt.Sprintf("bug is on board %d", *options.RequiredBoardID))
+		}
+	}
+
+	if options.RequireVerificationField != nil && *options.RequireVerificationField {
+		if options.VerificationField == nil || *options.VerificationField == "" {
+			errors = append(errors, "the bug's verification field must be set, but no verification field is configured for this repository")
+			valid = false
+		} else if verification, err := helpers.GetIssueVerification(bug, *options.VerificationField); err != nil {
+			errors = append(errors, fmt.Sprintf("failed to get the bug's verification field: %v", err))
+			valid = false
+		} else if verification == "" {
+			errors = append(errors, "expected the bug to have its verification field set, but it does not")
+			valid = false
+		} else {
+			validations = append(validations, "bug has its verification field set")
+		}
+	}
+
+	if options.RequireReleaseNoteType != nil && *options.RequireReleaseNoteType {
+		if options.ReleaseNoteTypeField == nil || *options.ReleaseNoteTypeField == "" {
+			errors = append(errors, "the bug's release note type must be set, but no release note type field is configured for this repository")
+			valid = false
+		} else if releaseNoteType, err := helpers.GetIssueReleaseNoteType(bug, *options.ReleaseNoteTypeField); err != nil {
+			errors = append(errors, fmt.Sprintf("failed to get the bug's release note type: %v", err))
+			valid = false
+		} else if releaseNoteType == "" {
+			errors = append(errors, "expected the bug to have its release note type set, but it does not"+fieldEditLink(options, bug.Key, *options.ReleaseNoteTypeField))
+			valid = false
+		} else {
+			validations = append(validations, fmt.Sprintf("bug has its release note type set (%s)", releaseNoteType))
+		}
+	}
+
+	if options.RequireStoryPoints != nil && *options.RequireStoryPoints {
+		if options.StoryPointsField == nil || *options.StoryPointsField == "" {
+			errors = append(errors, "the bug's story points must be set, but no story points field is configured for this repository")
+			valid = false
+		} else if points, err := helpers.GetIssueStoryPoints(bug, *options.StoryPointsField); err != nil {
+			errors = append(errors, fmt.Sprintf("failed to get the bug's story points: %v", err))
+			valid = false
+		} else if points == nil {
+			errors = append(errors, "expected the bug to have its story points set, but it does not"+fieldEditLink(options, bug.Key, *options.StoryPointsField))
+			valid = false
+		} else {
+			validations = append(validations, fmt.Sprintf("bug has its story points set (%v)", *points))
+		}
+	}
+
+	if options.RejectFlaggedBlocked != nil && *options.RejectFlaggedBlocked {
+		if options.FlaggedField == nil || *options.FlaggedField == "" {
+			errors = append(errors, "the bug must not be flagged as blocked, but no flag field is configured for this repository")
+			valid = false
+		} else if flagged, err := helpers.GetIssueFlagged(bug, *options.FlaggedField); err != nil {
+			errors = append(errors, fmt.Sprintf("failed to get the bug's flag: %v", err))
+			valid = false
+		} else if flagged {
+			errors = append(errors, "the referenced bug is flagged as blocked")
+			valid = false
+		} else {
+			validations = append(validations, "bug is not flagged as blocked")
+		}
+	}
+
+	if len(options.RejectResolutions) > 0 {
+		rejected := false
+		if bug.Fields.Resolution != nil {
+			for _, resolution := range options.RejectResolutions {
+				if strings.EqualFold(resolution, bug.Fields.Resolution.Name) {
+					rejected = true
+					break
+				}
+			}
+		}
+		if rejected {
+			valid = false
+			errors = append(errors, fmt.Sprintf("expected the bug not to be resolved as one of the following: %s, but it is resolved as %s; reopen the bug or link this pull request to a different bug", strings.Join(options.RejectResolutions, ", "), bug.Fields.Resolution.Name))
+		} else {
+			validations = append(validations, fmt.Sprintf("bug is not resolved as one of the rejected resolutions (%s)", strings.Join(options.RejectResolutions, ", ")))
+		}
+	}
+
+	if options.RejectIfAlreadyFixedInTarget != nil && *options.RejectIfAlreadyFixedInTarget {
+		if versions := acceptableTargetVersions(options); len(versions) > 0 {
+			if fixVersion := fixedInVersion(bug, versions); fixVersion != "" {
+				valid = false
+				errors = append(errors, fmt.Sprintf("expected the bug not to already be fixed in the target version, but it is resolved as %s with fix version %s; a backport is not needed, so link this pull request to a different bug or reopen the bug if the fix is incomplete", bug.Fields.Resolution.Name, fixVersion))
+			} else {
+				validations = append(validations, fmt.Sprintf("bug is not already fixed in the target version (%s)", strings.Join(versions, ", ")))
+			}
+		}
+	}
+
+	if options.ValidStates != nil {
+		var allowed []JiraBugState
+		allowed = append(allowed, *options.ValidStates...)
+		if options.StateAfterValidation != nil {
+			stateAlreadyExists := false
+			for _, state := range allowed {
+				if strings.EqualFold(state.Status, options.StateAfterValidation.Status) && strings.EqualFold(state.Resolution, options.StateAfterValidation.Resolution) {
+					stateAlreadyExists = true
+					break
+				}
+			}
+			if !stateAlreadyExists {
 				allowed = append(allowed, *options.StateAfterValidation)
 			}
 		}
@@ -1327,7 +2914,7 @@ func validateBug(bug *jira.Issue, dependents []dependent, options JiraBranchOpti
 		}
 		if !bugMatchesStates(bug, allowed) {
 			valid = false
-			errors = append(errors, fmt.Sprintf("expected the bug to be in one of the following states: %s, but it is %s instead", strings.Join(prettyStates(allowed), ", "), PrettyStatus(status, resolution)))
+			errors = append(errors, fmt.Sprintf("expected the bug to be in one of the following states: %s, but it is %s instead", strings.Join(prettyStates(allowed), ", "), PrettyStatus(status, resolution))+fieldEditLink(options, bug.Key, statusField))
 		} else {
 			validations = append(validations, fmt.Sprintf("bug is in the state %s, which is one of the valid states (%s)", PrettyStatus(status, resolution), strings.Join(prettyStates(allowed), ", ")))
 		}
@@ -1369,6 +2956,47 @@ func validateBug(bug *jira.Issue, dependents []dependent, options JiraBranchOpti
 		}
 	}
 
+	if options.RequireDependentPRsMerged != nil && *options.RequireDependentPRsMerged {
+		for _, bug := range dependents {
+			if !strings.HasPrefix(bug.key, "OCPBUGS-") {
+				continue
+			}
+			if len(bug.unmergedPRs) > 0 {
+				valid = false
+				errors = append(errors, fmt.Sprintf("expected all pull requests linked to dependent "+issueLink+" to be merged, but the following are not: %s", bug.key, jiraEndpoint, bug.key, strings.Join(bug.unmergedPRs, ", ")))
+			} else {
+				validations = append(validations, fmt.Sprintf("all pull requests linked to dependent "+issueLink+" are merged", bug.key, jiraEndpoint, bug.key))
+			}
+		}
+	}
+
+	if options.RequireDependentSameComponent != nil && *options.RequireDependentSameComponent {
+		components := componentNames(bug)
+		for _, dependent := range dependents {
+			if !sets.NewString(dependent.components...).Equal(sets.NewString(components...)) {
+				valid = false
+				errors = append(errors, fmt.Sprintf("expected dependent "+issueLink+" to be filed against the same components as the bug (%s), but it is filed against %s instead", dependent.key, jiraEndpoint, dependent.key, prettyComponents(components), prettyComponents(dependent.components)))
+			} else {
+				validations = append(validations, fmt.Sprintf("dependent "+issueLink+" is filed against the same components as the bug (%s)", dependent.key, jiraEndpoint, dependent.key, prettyComponents(components)))
+			}
+		}
+	}
+
+	if options.RequireDependentsResolved != nil && *options.RequireDependentsResolved && len(dependents) > 0 {
+		var unresolved []string
+		for _, dependent := range dependents {
+			if !dependentResolved(dependent, options) {
+				unresolved = append(unresolved, fmt.Sprintf(issueLink+" (%s)", dependent.key, jiraEndpoint, dependent.key, PrettyStatus(dependent.bugState.Status, dependent.bugState.Resolution)))
+			}
+		}
+		if len(unresolved) > 0 {
+			valid = false
+			errors = append(errors, fmt.Sprintf("expected all dependents of the bug to be resolved, but the following are not: %s", strings.Join(unresolved, ", ")))
+		} else {
+			validations = append(validations, "all dependents of the bug are resolved")
+		}
+	}
+
 	if len(dependents) == 0 {
 		switch {
 		case options.DependentBugStates != nil && options.DependentBugTargetVersions != nil:
@@ -1384,7 +3012,7 @@ func validateBug(bug *jira.Issue, dependents []dependent, options JiraBranchOpti
 			errors = append(errors, fmt.Sprintf("expected "+issueLink+" to depend on a bug targeting a version in %s, but no dependents were found", bug.Key, jiraEndpoint, bug.Key, strings.Join(*options.DependentBugTargetVersions, ", ")))
 		default:
 		}
-	} else {
+	} else if options.ShowHasDependents == nil || *options.ShowHasDependents || dependentValidationsConfigured(options) {
 		validations = append(validations, "bug has dependents")
 	}
 
@@ -1399,81 +3027,636 @@ func validateBug(bug *jira.Issue, dependents []dependent, options JiraBranchOpti
 	return valid, validations, errors
 }
 
-func validateTargetVersion(issue *jira.Issue, requiredTargetVersion string) error {
-	issueType := ""
-	if issue.Fields != nil {
-		issueType = strings.ToLower(issue.Fields.Type.Name)
-	} else {
-		issueType = "bug"
+// dependentValidationsConfigured determines whether any of the validations of dependent bugs
+// are configured for the branch
+func dependentValidationsConfigured(options JiraBranchOptions) bool {
+	return options.DependentBugStates != nil || options.DependentBugTargetVersions != nil ||
+		(options.RequireDependentPRsMerged != nil && *options.RequireDependentPRsMerged) ||
+		(options.RequireDependentSameComponent != nil && *options.RequireDependentSameComponent) ||
+		(options.RequireDependentsResolved != nil && *options.RequireDependentsResolved)
+}
+
+// validationsConfigured determines whether any requirements for valid bugs are configured for
+// the branch, regardless of whether they apply to a given bug
+func validationsConfigured(options JiraBranchOptions) bool {
+	// IsOpen is a requirement whether it expects the bug to be open or closed
+	if options.IsOpen != nil {
+		return true
+	}
+	for _, flag := range []*bool{
+		options.RequireOriginalBug,
+		options.RequireEnvironmentField,
+		options.RequireQaContact,
+		options.RequireKnownTargetVersion,
+		options.ValidateBranchTargetConsistency,
+		options.RequireVerificationField,
+		options.RequireReleaseNoteType,
+		options.RequireStoryPoints,
+		options.RejectFlaggedBlocked,
+		options.RejectIfAlreadyFixedInTarget,
+	} {
+		if flag != nil && *flag {
+			return true
+		}
+	}
+	return options.ExpectedProject != nil || options.RequiredBoardID != nil || options.ValidStates != nil ||
+		len(options.RequiredLabels) > 0 || len(options.ForbiddenLabels) > 0 || len(options.AllowedReporters) > 0 ||
+		len(options.IssueTypeBranches) > 0 || len(options.RejectResolutions) > 0 ||
+		len(acceptableTargetVersions(options)) > 0 || dependentValidationsConfigured(options) ||
+		(options.ValidationJQL != nil && *options.ValidationJQL != "") ||
+		(options.ContributorsField != nil && *options.ContributorsField != "") ||
+		(options.TestRepos != nil && len(*options.TestRepos) > 0)
+}
+
+// userName returns the name under which the Jira user is displayed, falling back to the
+// other identifiers of the user if the display name is not set
+func userName(user *jira.User) string {
+	for _, name := range []string{user.DisplayName, user.Name, user.EmailAddress, user.AccountID} {
+		if name != "" {
+			return name
+		}
+	}
+	return "unknown user"
+}
+
+// dependentResolved determines whether the dependent is in one of the ResolvedStates or, if
+// none are configured, whether it has a resolution or is closed
+func dependentResolved(dependent dependent, options JiraBranchOptions) bool {
+	if options.ResolvedStates != nil {
+		return dependent.bugState.matches(*options.ResolvedStates)
+	}
+	return dependent.bugState.Resolution != "" || strings.EqualFold(dependent.bugState.Status, status.Closed)
+}
+
+// issueTypeBranchPatterns returns the branch patterns configured for the issue type, which is
+// matched case-insensitively. The returned bool is false if none are configured.
+func issueTypeBranchPatterns(options JiraBranchOptions, issueType string) ([]string, bool) {
+	for configured, patterns := range options.IssueTypeBranches {
+		if strings.EqualFold(configured, issueType) {
+			return patterns, true
+		}
+	}
+	return nil, false
+}
+
+// branchMatchesAny determines whether any of the patterns matches the whole name of the branch.
+// Invalid patterns are reported when validating the config, so they never match here.
+func branchMatchesAny(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			continue
+		}
+		if re.MatchString(branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// componentNames returns the names of the components the issue is filed against
+func componentNames(issue *jira.Issue) []string {
+	var names []string
+	if issue.Fields == nil {
+		return names
+	}
+	for _, component := range issue.Fields.Components {
+		if component != nil {
+			names = append(names, component.Name)
+		}
+	}
+	return names
+}
+
+// prettyComponents formats component names for validation messages
+func prettyComponents(components []string) string {
+	if len(components) == 0 {
+		return "no components"
+	}
+	return strings.Join(components, ", ")
+}
+
+// statusField is the ID of the Jira status field, used in links to edit the field
+const statusField = "status"
+
+// environmentField is the ID of the Jira environment field, used in links to edit the field
+const environmentField = "environment"
+
+// labelsField is the ID of the Jira labels field, used in links to edit the field
+const labelsField = "labels"
+
+// fieldEditLink returns a link to edit the field of the issue, to be appended to a validation
+// failure. It returns an empty string if no FieldEditURLTemplate is configured or it is invalid.
+func fieldEditLink(options JiraBranchOptions, key, field string) string {
+	if options.FieldEditURLTemplate == nil || *options.FieldEditURLTemplate == "" {
+		return ""
 	}
-	targetVersion, err := helpers.GetIssueTargetVersion(issue)
+	tmpl, err := template.New("field-edit-url").Parse(*options.FieldEditURLTemplate)
 	if err != nil {
-		return fmt.Errorf("failed to get target version for %s: %v", issueType, err)
+		logrus.WithError(err).Warn("Failed to parse the field edit URL template.")
+		return ""
 	}
-	if len(targetVersion) == 0 {
-		return fmt.Errorf("expected the %s to target the %q version, but no target version was set", issueType, requiredTargetVersion)
+	var url strings.Builder
+	if err := tmpl.Execute(&url, struct{ Key, Field string }{Key: key, Field: field}); err != nil {
+		logrus.WithError(err).Warn("Failed to execute the field edit URL template.")
+		return ""
 	}
-	if len(targetVersion) > 1 {
-		return fmt.Errorf("expected the %s to target only the %q version, but multiple target versions were set", issueType, requiredTargetVersion)
+	return fmt.Sprintf(" ([edit](%s))", url.String())
+}
+
+// securityTeamCC returns a line requesting the attention of the configured SecurityTeam, to be
+// appended to comments about bugs in security levels that are not allowed for the repo. It
+// returns an empty string if no team is configured.
+func securityTeamCC(options JiraBranchOptions) string {
+	if options.SecurityTeam == nil || strings.TrimPrefix(*options.SecurityTeam, "@") == "" {
+		return ""
 	}
-	//prefixedRequiredTargetVersion := fmt.Sprintf("openshift-%s", requiredTargetVersion)
-	//if requiredTargetVersion != targetVersion[0].Name && prefixedRequiredTargetVersion != targetVersion[0].Name {
-	//	return fmt.Errorf("expected the %s to target either version %q or %q, but it targets %q instead", issueType, requiredTargetVersion, prefixedRequiredTargetVersion, targetVersion[0].Name)
-	//}
-	// TODO: Remove this truncated version check...
-	truncatedRequiredTargetVersion := requiredTargetVersion
-	pieces := strings.Split(requiredTargetVersion, ".")
-	if len(pieces) >= 2 {
-		truncatedRequiredTargetVersion = fmt.Sprintf("%s.%s", pieces[0], pieces[1])
+	return fmt.Sprintf("\n\nRequesting triage of the access to the bug from the security team:\n/cc @%s", strings.TrimPrefix(*options.SecurityTeam, "@"))
+}
+
+// validationTransitionComment renders the ValidationTransitionComment template for a bug
+// moved to the given status after being validated for the pull request of the event.
+func validationTransitionComment(text, key string, e event, status string) (string, error) {
+	tmpl, err := template.New("validation-transition-comment").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the validation transition comment template: %w", err)
 	}
-	truncatedPrefixedRequiredTargetVersion := fmt.Sprintf("openshift-%s", truncatedRequiredTargetVersion)
-	if !strings.HasPrefix(targetVersion[0].Name, truncatedRequiredTargetVersion) && !strings.HasPrefix(targetVersion[0].Name, truncatedPrefixedRequiredTargetVersion) {
-		return fmt.Errorf("expected the %s to target either version %q or %q, but it targets %q instead", issueType, fmt.Sprintf("%s.*", truncatedRequiredTargetVersion), fmt.Sprintf("%s.*", truncatedPrefixedRequiredTargetVersion), targetVersion[0].Name)
+	var body strings.Builder
+	data := struct{ Key, PullRequestURL, Status string }{
+		Key:            key,
+		PullRequestURL: fmt.Sprintf("https://github.com/%s/%s/pull/%d", e.org, e.repo, e.number),
+		Status:         status,
 	}
-	return nil
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("failed to execute the validation transition comment template: %w", err)
+	}
+	return body.String(), nil
 }
 
-type prParts struct {
-	Org  string
-	Repo string
-	Num  int
+// acceptableTargetVersions returns the versions a bug may target to be valid for the branch,
+// treating TargetVersion as one more entry in TargetVersions.
+func acceptableTargetVersions(options JiraBranchOptions) []string {
+	var versions []string
+	if options.TargetVersion != nil {
+		versions = append(versions, *options.TargetVersion)
+	}
+	if options.TargetVersions != nil {
+		for _, version := range *options.TargetVersions {
+			if options.TargetVersion == nil || version != *options.TargetVersion {
+				versions = append(versions, version)
+			}
+		}
+	}
+	return versions
 }
 
-func handleMerge(e event, gc githubClient, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry, allRepos sets.String) error {
-	if options.StateAfterMerge == nil {
-		return nil
+// validationMarkerPrefix starts the hidden HTML comment holding the validation results
+// of a bug, which tooling can parse from the comments of a pull request
+const validationMarkerPrefix = "<!-- jira-lifecycle: "
+
+type validationMarker struct {
+	Key     string   `json:"key"`
+	Valid   bool     `json:"valid"`
+	Reasons []string `json:"reasons"`
+}
+
+// formatValidationMarker renders the validation results of a bug as a hidden HTML comment.
+// The JSON encoder escapes `<` and `>`, so the reasons cannot terminate the comment early.
+func formatValidationMarker(key string, valid bool, reasons []string) string {
+	if reasons == nil {
+		reasons = []string{}
 	}
-	if e.missing {
-		return nil
+	raw, err := json.Marshal(validationMarker{Key: key, Valid: valid, Reasons: reasons})
+	if err != nil {
+		// the marker only holds strings and a bool, so this cannot happen
+		return ""
 	}
-	comment := e.comment(gc)
+	return fmt.Sprintf("\n%s%s -->", validationMarkerPrefix, raw)
+}
 
-	msg := ""
-	for _, refBug := range e.bugs {
-		if !refBug.IsBug {
-			continue
+// parseValidationMarkers returns the validation results recorded in the validation markers of
+// the comment body. Markers that cannot be parsed are skipped.
+func parseValidationMarkers(body string) []validationMarker {
+	var markers []validationMarker
+	for {
+		start := strings.Index(body, validationMarkerPrefix)
+		if start == -1 {
+			return markers
 		}
-		if msg != "" {
-			msg += "\n\n"
+		body = body[start+len(validationMarkerPrefix):]
+		end := strings.Index(body, " -->")
+		if end == -1 {
+			return markers
 		}
-		bug, err := getJira(jc, refBug.Key, log, comment)
-		if err != nil || bug == nil {
-			return err
+		var marker validationMarker
+		if err := json.Unmarshal([]byte(body[:end]), &marker); err == nil {
+			markers = append(markers, marker)
 		}
-		if options.ValidStates != nil || options.StateAfterValidation != nil {
-			// we should only migrate if we can be fairly certain that the bug
-			// is not in a state that required human intervention to get to.
-			// For instance, if a bug is closed after a PR merges it should not
-			// be possible for /jira refresh to move it back to the post-merge
-			// state.
-			var allowed []JiraBugState
-			if options.ValidStates != nil {
-				allowed = append(allowed, *options.ValidStates...)
-			}
+		body = body[end:]
+	}
+}
 
-			if options.StateAfterValidation != nil {
-				allowed = append(allowed, *options.StateAfterValidation)
-			}
+// stateMarkerPrefix starts the hidden HTML comment holding the states of the bugs at the time
+// of a comment, used by CommentOncePerState to detect whether any of them changed since
+const stateMarkerPrefix = "<!-- jira-lifecycle-states: "
+
+// formatStateMarker renders the states of the bugs, given as key=state, as a hidden HTML comment
+func formatStateMarker(bugStates []string) string {
+	return fmt.Sprintf("\n%s%s -->", stateMarkerPrefix, strings.Join(bugStates, ", "))
+}
+
+// severityMarkerPrefix starts the hidden HTML comment holding the severities of the bugs at the
+// time of a comment, used by ReportSeverityChanges to detect whether any of them changed since
+const severityMarkerPrefix = "<!-- jira-lifecycle-severities: "
+
+// formatSeverityMarker renders the severities of the bugs, given as key=severity, as a hidden HTML comment
+func formatSeverityMarker(bugSeverities []string) string {
+	return fmt.Sprintf("\n%s%s -->", severityMarkerPrefix, strings.Join(bugSeverities, ", "))
+}
+
+// parseSeverityMarker returns the severities of the bugs recorded in the last severity marker
+// of the comment body, keyed by bug, or nil if the body holds no marker
+func parseSeverityMarker(body string) map[string]string {
+	start := strings.LastIndex(body, severityMarkerPrefix)
+	if start == -1 {
+		return nil
+	}
+	marker := body[start+len(severityMarkerPrefix):]
+	end := strings.Index(marker, " -->")
+	if end == -1 {
+		return nil
+	}
+	severities := map[string]string{}
+	for _, entry := range strings.Split(marker[:end], ", ") {
+		if key, severity, ok := strings.Cut(entry, "="); ok {
+			severities[key] = severity
+		}
+	}
+	return severities
+}
+
+// severityChanges returns a note listing the bugs whose severity changed since the last comment
+// of the bot that recorded their severities, or an empty string if none changed
+func severityChanges(e event, ghc githubClient, bugSeverities []string, jiraURL string, log *logrus.Entry) string {
+	comments, err := ghc.ListIssueComments(e.org, e.repo, e.number)
+	if err != nil {
+		log.WithError(err).Error("Failed to list issue comments.")
+		return ""
+	}
+	isBot, err := ghc.BotUserChecker()
+	if err != nil {
+		log.WithError(err).Error("Failed to create bot user checker.")
+		return ""
+	}
+	var previous map[string]string
+	// comments are returned in order of ID, which is oldest first, so search from the end
+	for i := len(comments) - 1; i >= 0 && previous == nil; i-- {
+		if isBot(comments[i].User.Login) {
+			previous = parseSeverityMarker(comments[i].Body)
+		}
+	}
+	var changes []string
+	for _, entry := range bugSeverities {
+		key, severity, _ := strings.Cut(entry, "=")
+		old, ok := previous[key]
+		if !ok || old == severity {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("The severity of "+issueLink+" changed from %s to %s since it was last validated.", key, jiraURL, key, displaySeverity(old), displaySeverity(severity)))
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+	return "\n\n" + strings.Join(changes, "\n")
+}
+
+// displaySeverity returns the severity for display, or a placeholder if it is not set
+func displaySeverity(severity string) string {
+	if severity == "" {
+		return "(unset)"
+	}
+	return severity
+}
+
+// issueState returns the pretty state of the issue, or an empty string if it has no status
+func issueState(issue *jira.Issue) string {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return ""
+	}
+	var resolution string
+	if issue.Fields.Resolution != nil {
+		resolution = issue.Fields.Resolution.Name
+	}
+	return PrettyStatus(issue.Fields.Status.Name, resolution)
+}
+
+// fixVersions returns the names of the fix versions of the bug
+func fixVersions(bug *jira.Issue) []string {
+	var names []string
+	for _, version := range bug.Fields.FixVersions {
+		if version != nil {
+			names = append(names, version.Name)
+		}
+	}
+	return names
+}
+
+// fixedInVersion returns the first of the given versions that the bug is resolved with as a
+// fix version, or an empty string if the bug is not resolved or has no matching fix version.
+func fixedInVersion(bug *jira.Issue, versions []string) string {
+	if bug.Fields == nil || bug.Fields.Resolution == nil {
+		return ""
+	}
+	acceptable := sets.NewString(versions...)
+	for _, version := range bug.Fields.FixVersions {
+		if version != nil && acceptable.Has(version.Name) {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+// validateTargetVersions checks that the issue targets any of the acceptable versions
+func validateTargetVersions(issue *jira.Issue, acceptableVersions []string, fields helpers.FieldMap) error {
+	if len(acceptableVersions) == 1 {
+		return validateTargetVersion(issue, acceptableVersions[0], fields)
+	}
+	for _, version := range acceptableVersions {
+		if validateTargetVersion(issue, version, fields) == nil {
+			return nil
+		}
+	}
+	issueType := "bug"
+	if issue.Fields != nil {
+		issueType = strings.ToLower(issue.Fields.Type.Name)
+	}
+	targetVersion, err := helpers.GetIssueTargetVersion(issue, fields)
+	if err != nil {
+		return fmt.Errorf("failed to get target version for %s: %v", issueType, err)
+	}
+	if len(targetVersion) == 0 {
+		return fmt.Errorf("expected the %s to target one of the following versions: %s, but no target version was set", issueType, strings.Join(acceptableVersions, ", "))
+	}
+	var actual []string
+	for _, version := range targetVersion {
+		actual = append(actual, strconv.Quote(version.Name))
+	}
+	return fmt.Errorf("expected the %s to target one of the following versions: %s, but it targets %s instead", issueType, strings.Join(acceptableVersions, ", "), strings.Join(actual, ", "))
+}
+
+func validateTargetVersion(issue *jira.Issue, requiredTargetVersion string, fields helpers.FieldMap) error {
+	issueType := ""
+	if issue.Fields != nil {
+		issueType = strings.ToLower(issue.Fields.Type.Name)
+	} else {
+		issueType = "bug"
+	}
+	targetVersion, err := helpers.GetIssueTargetVersion(issue, fields)
+	if err != nil {
+		return fmt.Errorf("failed to get target version for %s: %v", issueType, err)
+	}
+	if len(targetVersion) == 0 {
+		return fmt.Errorf("expected the %s to target the %q version, but no target version was set", issueType, requiredTargetVersion)
+	}
+	//prefixedRequiredTargetVersion := fmt.Sprintf("openshift-%s", requiredTargetVersion)
+	//if requiredTargetVersion != targetVersion[0].Name && prefixedRequiredTargetVersion != targetVersion[0].Name {
+	//	return fmt.Errorf("expected the %s to target either version %q or %q, but it targets %q instead", issueType, requiredTargetVersion, prefixedRequiredTargetVersion, targetVersion[0].Name)
+	//}
+	// TODO: Remove this truncated version check...
+	truncatedRequiredTargetVersion := requiredTargetVersion
+	pieces := strings.Split(requiredTargetVersion, ".")
+	if len(pieces) >= 2 {
+		truncatedRequiredTargetVersion = fmt.Sprintf("%s.%s", pieces[0], pieces[1])
+	}
+	truncatedPrefixedRequiredTargetVersion := fmt.Sprintf("openshift-%s", truncatedRequiredTargetVersion)
+	// if multiple target versions are set, the bug is valid as long as any of them match; the
+	// ambiguity is reported separately by multipleTargetVersionsWarning
+	var actual []string
+	for _, version := range targetVersion {
+		if strings.HasPrefix(version.Name, truncatedRequiredTargetVersion) || strings.HasPrefix(version.Name, truncatedPrefixedRequiredTargetVersion) {
+			return nil
+		}
+		actual = append(actual, strconv.Quote(version.Name))
+	}
+	return fmt.Errorf("expected the %s to target either version %q or %q, but it targets %s instead", issueType, fmt.Sprintf("%s.*", truncatedRequiredTargetVersion), fmt.Sprintf("%s.*", truncatedPrefixedRequiredTargetVersion), strings.Join(actual, ", "))
+}
+
+// multipleTargetVersionsWarning returns a warning for the comment if the issue has more than one
+// target version set, as this usually indicates a data-entry error.
+func multipleTargetVersionsWarning(issue *jira.Issue, fields helpers.FieldMap) string {
+	targetVersion, err := helpers.GetIssueTargetVersion(issue, fields)
+	if err != nil || len(targetVersion) < 2 {
+		return ""
+	}
+	var names []string
+	for _, version := range targetVersion {
+		names = append(names, version.Name)
+	}
+	return fmt.Sprintf("\n\nWarning: The referenced bug has multiple target versions set (%s). This usually indicates a data-entry error; please make sure that only one target version is set.", strings.Join(names, ", "))
+}
+
+// suspiciousStateWarning returns a warning for the comment if the issue is in one of the
+// SuspiciousStates while the pull request is still open, e.g. if the bug was verified before
+// the fix merged.
+func suspiciousStateWarning(issue *jira.Issue, e event, options JiraBranchOptions) string {
+	if options.SuspiciousStates == nil || e.merged || e.state == github.PullRequestStateClosed || !bugMatchesStates(issue, *options.SuspiciousStates) {
+		return ""
+	}
+	var status, resolution string
+	if issue.Fields.Status != nil {
+		status = issue.Fields.Status.Name
+	}
+	if issue.Fields.Resolution != nil {
+		resolution = issue.Fields.Resolution.Name
+	}
+	return fmt.Sprintf("\n\nWarning: The referenced bug is in the %s state although this pull request has not merged yet. Please make sure that the bug is not tracking a fix that is still pending.", PrettyStatus(status, resolution))
+}
+
+// defaultCherrypickRecommendation is the guidance added when RecommendCherrypick is set and no
+// CherrypickRecommendation is configured
+const defaultCherrypickRecommendation = "This pull request references a bug directly instead of being created through the cherrypick flow. Fixes for release branches are usually backported by commenting <code>/cherrypick %s</code> on the pull request that fixed the bug on the main branch, which clones the bug for this branch and references the clone. Please make sure that the backport process has been followed."
+
+// cherrypickRecommendation returns guidance recommending the cherrypick flow if it is enabled
+// for the branch and a pull request that is not a cherrypick was opened referencing a bug.
+// Cherrypicks are handled before validation, so opened pull requests validated here are not.
+func cherrypickRecommendation(e event, options JiraBranchOptions) string {
+	if options.RecommendCherrypick == nil || !*options.RecommendCherrypick || !e.opened || e.missing {
+		return ""
+	}
+	for _, refBug := range e.bugs {
+		if !refBug.IsBug {
+			continue
+		}
+		if options.CherrypickRecommendation != nil && *options.CherrypickRecommendation != "" {
+			return "\n\n" + *options.CherrypickRecommendation
+		}
+		return "\n\n" + fmt.Sprintf(defaultCherrypickRecommendation, e.baseRef)
+	}
+	return ""
+}
+
+// incompleteSubtasksWarning returns a warning listing the subtasks of the bug that are not done
+// yet, if enabled for the branch. Subtasks without a status category are done once closed.
+func incompleteSubtasksWarning(issue *jira.Issue, options JiraBranchOptions, jiraEndpoint string) string {
+	if options.WarnOnIncompleteSubtasks == nil || !*options.WarnOnIncompleteSubtasks || issue.Fields == nil {
+		return ""
+	}
+	var incomplete []string
+	for _, subtask := range issue.Fields.Subtasks {
+		if subtask == nil {
+			continue
+		}
+		// subtasks returned without their fields have no status and are reported in an unknown state
+		subtaskStatus := subtask.Fields.Status
+		if subtaskStatus != nil && (subtaskStatus.StatusCategory.Key == jira.StatusCategoryComplete || strings.EqualFold(subtaskStatus.Name, status.Closed)) {
+			continue
+		}
+		state := "unknown"
+		if subtaskStatus != nil {
+			state = subtaskStatus.Name
+		}
+		incomplete = append(incomplete, fmt.Sprintf(" * "+issueLink+" is in the %s state", subtask.Key, jiraEndpoint, subtask.Key, state))
+	}
+	if len(incomplete) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nWarning: The referenced bug has subtasks that are not done yet, so the fix may only be partial:\n%s", strings.Join(incomplete, "\n"))
+}
+
+type prParts struct {
+	Org  string
+	Repo string
+	Num  int
+}
+
+// prPartsFromURL parses the pull request a remote link points to. The returned bool is false
+// if the URL does not point to a GitHub pull request.
+func prPartsFromURL(url string) (prParts, bool, error) {
+	identifier := strings.TrimPrefix(url, "https://github.com/")
+	parts := strings.Split(identifier, "/")
+	if len(parts) >= 3 && parts[2] != "pull" {
+		return prParts{}, false, nil
+	}
+	if len(parts) != 4 && !(len(parts) == 5 && (parts[4] == "" || parts[4] == "files")) && !(len(parts) == 6 && ((parts[4] == "files" && parts[5] == "") || parts[4] == "commits")) {
+		return prParts{}, true, fmt.Errorf("invalid pull identifier with %d parts: %q", len(parts), identifier)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return prParts{}, true, fmt.Errorf("invalid pull identifier: could not parse %s as number: %w", parts[3], err)
+	}
+	return prParts{Org: parts[0], Repo: parts[1], Num: number}, true, nil
+}
+
+// unmergedLinkedPRs returns the pull requests linked to the issue via remote links that have
+// not merged. Pull requests in repos that are not part of the Prow config are not checked.
+func unmergedLinkedPRs(e event, gc githubClient, jc jiraclient.Client, issueID string, allRepos sets.String) ([]string, error) {
+	links, err := jc.GetRemoteLinks(issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote links: %w", err)
+	}
+	var unmerged []string
+	for _, link := range links {
+		if link.Object == nil {
+			continue
+		}
+		item, isPR, err := prPartsFromURL(link.Object.URL)
+		if !isPR {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		merged, _, managed, err := pullRequestState(e, gc, item, allRepos)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check the state of https://github.com/%s/%s/pull/%d: %w", item.Org, item.Repo, item.Num, err)
+		}
+		if managed && !merged {
+			unmerged = append(unmerged, fmt.Sprintf("[%s/%s#%d](https://github.com/%s/%s/pull/%d)", item.Org, item.Repo, item.Num, item.Org, item.Repo, item.Num))
+		}
+	}
+	return unmerged, nil
+}
+
+// pullRequestState returns whether the pull request has merged as well as its state. The state of
+// the pull request of the event is taken from the event. Pull requests in repos that are not part
+// of the Prow config could be literally anything, so they are not looked up and the returned
+// managed bool is false.
+func pullRequestState(e event, gc githubClient, item prParts, allRepos sets.String) (merged bool, state string, managed bool, err error) {
+	if e.org == item.Org && e.repo == item.Repo && e.number == item.Num {
+		return e.merged, e.state, true, nil
+	}
+	if !allRepos.Has(item.Org + "/" + item.Repo) {
+		return false, "", false, nil
+	}
+	pr, err := gc.GetPullRequest(item.Org, item.Repo, item.Num)
+	if err != nil {
+		return false, "", true, err
+	}
+	return pr.Merged, pr.State, true, nil
+}
+
+func handleMerge(e event, gc githubClient, jc jiraclient.Client, ac agileClient, options JiraBranchOptions, log *logrus.Entry, allRepos sets.String) error {
+	if options.StateAfterMerge == nil {
+		return nil
+	}
+	if e.missing {
+		return nil
+	}
+	comment := e.comment(gc)
+
+	msg := ""
+	for _, refBug := range e.bugs {
+		if !refBug.IsBug {
+			continue
+		}
+		if msg != "" {
+			msg += "\n\n"
+		}
+		// a pull request may fix several bugs; failing to handle one of them must not keep the
+		// others from moving, so the outcome for each bug is reported in a combined comment
+		bug, _, lookupMsg := lookupJira(jc, refBug.Key, log)
+		if bug == nil {
+			msg += lookupMsg
+			continue
+		}
+		// the merge is only recorded once, when the pull request merges, and not when the
+		// merged pull request is handled again, e.g. on a refresh
+		if options.CommentOnJiraAtMerge != nil && *options.CommentOnJiraAtMerge && e.closed && !e.refresh {
+			visibility := PrivateVisibility
+			if options.MergeCommentVisibility != nil {
+				visibility = *options.MergeCommentVisibility
+			}
+			mergeComment := &jira.Comment{Body: fmt.Sprintf("The fix for %s has merged in https://github.com/%s/%s/pull/%d.", bug.Key, e.org, e.repo, e.number), Visibility: visibility}
+			if _, err := jc.AddComment(bug.ID, mergeComment); err != nil {
+				log.WithError(err).Warn("Unexpected error adding comment to jira issue.")
+				msg += formatError("adding a comment about the merge", jc.JiraURL(), refBug.Key, err) + "\n\n"
+			}
+		}
+		if options.BlockedStates != nil && bugMatchesStates(bug, *options.BlockedStates) {
+			var status, resolution string
+			if bug.Fields.Status != nil {
+				status = bug.Fields.Status.Name
+			}
+			if bug.Fields.Resolution != nil {
+				resolution = bug.Fields.Resolution.Name
+			}
+			msg += fmt.Sprintf(issueLink+" is in the %s state, which blocks it pending more information, and will not be moved to the %s state. Once the bug has been updated, request a bug refresh with <code>/jira refresh</code>.", refBug.Key, jc.JiraURL(), refBug.Key, PrettyStatus(status, resolution), options.StateAfterMerge)
+			continue
+		}
+		if options.ValidStates != nil || options.StateAfterValidation != nil {
+			// we should only migrate if we can be fairly certain that the bug
+			// is not in a state that required human intervention to get to.
+			// For instance, if a bug is closed after a PR merges it should not
+			// be possible for /jira refresh to move it back to the post-merge
+			// state.
+			var allowed []JiraBugState
+			if options.ValidStates != nil {
+				allowed = append(allowed, *options.ValidStates...)
+			}
+
+			if options.StateAfterValidation != nil {
+				allowed = append(allowed, *options.StateAfterValidation)
+			}
 			if !bugMatchesStates(bug, allowed) {
 				msg += fmt.Sprintf(issueLink+" is in an unrecognized state (%s) and will not be moved to the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, bug.Fields.Status.Name, options.StateAfterMerge)
 				continue
@@ -1483,55 +3666,45 @@ func handleMerge(e event, gc githubClient, jc jiraclient.Client, options JiraBra
 		links, err := jc.GetRemoteLinks(bug.ID)
 		if err != nil {
 			log.WithError(err).Warn("Unexpected error listing external tracker bugs for Jira bug.")
-			msg += formatError("searching for external tracker bugs", jc.JiraURL(), refBug.Key, err)
+			// without the linked pull requests it is unknown whether all of them have merged, so the bug must not be moved
+			msg += fmt.Sprintf(`Could not read the pull requests linked to `+issueLink+`, so it will not be moved to the %s state as it is unknown whether all of them have merged. Request a bug refresh with <code>/jira refresh</code> to try again.
+
+<details><summary>Full error message.</summary>
+
+<code>
+%v
+</code>
+
+</details>`, refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterMerge, err)
 			continue
 		}
+		mergeRepos := allRepos
+		if repos := options.MergeRepos[projectFromKey(bug.Key)]; len(repos) > 0 {
+			mergeRepos = allRepos.Union(sets.NewString(repos...))
+		}
 		shouldMigrate := true
 		var mergedPRs []prParts
 		unmergedPrStates := map[prParts]string{}
 		for _, link := range links {
-			identifier := strings.TrimPrefix(link.Object.URL, "https://github.com/")
-			parts := strings.Split(identifier, "/")
-			if len(parts) >= 3 && parts[2] != "pull" {
+			item, isPR, err := prPartsFromURL(link.Object.URL)
+			if !isPR {
 				// this is not a github link
 				continue
 			}
-			if len(parts) != 4 && !(len(parts) == 5 && (parts[4] == "" || parts[4] == "files")) && !(len(parts) == 6 && ((parts[4] == "files" && parts[5] == "") || parts[4] == "commits")) {
+			if err != nil {
 				log.WithError(err).Warn("Unexpected error splitting github URL for Jira external link.")
-				msg += formatError(fmt.Sprintf("invalid pull identifier with %d parts: %q", len(parts), identifier), jc.JiraURL(), refBug.Key, err)
+				msg += formatError("parsing the pull request of an external link", jc.JiraURL(), refBug.Key, err)
 				continue
 			}
-			number, err := strconv.Atoi(parts[3])
+			merged, state, managed, err := pullRequestState(e, gc, item, mergeRepos)
 			if err != nil {
-				log.WithError(err).Warn("Unexpected error splitting github URL for Jira external link.")
-				msg += formatError(fmt.Sprintf("invalid pull identifier: could not parse %s as number", parts[3]), jc.JiraURL(), refBug.Key, err)
+				log.WithError(err).Warn("Unexpected error checking merge state of related pull request.")
+				msg += formatError(fmt.Sprintf("checking the state of a related pull request at https://github.com/%s/%s/pull/%d", item.Org, item.Repo, item.Num), jc.JiraURL(), refBug.Key, err)
 				continue
 			}
-			item := prParts{
-				Org:  parts[0],
-				Repo: parts[1],
-				Num:  number,
-			}
-			var merged bool
-			var state string
-			if e.org == item.Org && e.repo == item.Repo && e.number == item.Num {
-				merged = e.merged
-				state = e.state
-			} else {
-				// This could be literally anything, only process PRs in repos that are mentioned in our config, otherwise this will potentially
-				// fail.
-				if !allRepos.Has(item.Org + "/" + item.Repo) {
-					logrus.WithField("pr", item.Org+"/"+item.Repo+"#"+strconv.Itoa(item.Num)).Debug("Not processing PR from third-party repo")
-					continue
-				}
-				pr, err := gc.GetPullRequest(item.Org, item.Repo, item.Num)
-				if err != nil {
-					log.WithError(err).Warn("Unexpected error checking merge state of related pull request.")
-					msg += formatError(fmt.Sprintf("checking the state of a related pull request at https://github.com/%s/%s/pull/%d", item.Org, item.Repo, item.Num), jc.JiraURL(), refBug.Key, err)
-					continue
-				}
-				merged = pr.Merged
-				state = pr.State
+			if !managed {
+				logrus.WithField("pr", item.Org+"/"+item.Repo+"#"+strconv.Itoa(item.Num)).Debug("Not processing PR from third-party repo")
+				continue
 			}
 			if merged {
 				mergedPRs = append(mergedPRs, item)
@@ -1578,6 +3751,63 @@ These pull request must merge or be unlinked from the Jira bug in order for it t
 			return fmt.Sprintf(issueLink+" has %sbeen moved to the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, action, options.StateAfterMerge)
 		}
 
+		if shouldMigrate && options.BlockClosureIfBlocksOpen != nil && *options.BlockClosureIfBlocksOpen {
+			blocked, err := openBlockedBugs(jc, bug)
+			if err != nil {
+				log.WithError(err).Warn("Unexpected error checking the bugs blocked by the Jira bug.")
+				msg += formatError("checking the bugs blocked by the bug", jc.JiraURL(), refBug.Key, err)
+				continue
+			}
+			if len(blocked) > 0 {
+				var blockedLinks []string
+				for _, blockedBug := range blocked {
+					blockedLinks = append(blockedLinks, fmt.Sprintf(" * "+issueLink+" is in the %s state", blockedBug.Key, jc.JiraURL(), blockedBug.Key, PrettyStatus(blockedBug.Fields.Status.Name, "")))
+				}
+				msg += fmt.Sprintf(issueLink+`: %sThe bug blocks the following bugs, which are still open:
+%s
+
+The bug will not be moved to the %s state until they are closed. Once they are, request a bug refresh with <code>/jira refresh</code>.`, refBug.Key, jc.JiraURL(), refBug.Key, mergedMessage("All"), strings.Join(blockedLinks, "\n"), options.StateAfterMerge)
+				continue
+			}
+		}
+
+		if shouldMigrate && options.ValidateFixVersionOnMerge != nil && *options.ValidateFixVersionOnMerge {
+			names := fixVersions(bug)
+			if versions := acceptableTargetVersions(options); len(versions) > 0 && !sets.NewString(names...).HasAny(versions...) {
+				current := "none"
+				if len(names) > 0 {
+					current = strings.Join(names, ", ")
+				}
+				msg += fmt.Sprintf(issueLink+`: %sThe fix versions of the bug (%s) do not include the target version of the branch (%s).
+
+The bug will not be moved to the %s state until its fix versions are corrected. Once they are, request a bug refresh with <code>/jira refresh</code>.`, refBug.Key, jc.JiraURL(), refBug.Key, mergedMessage("All"), current, strings.Join(versions, ", "), options.StateAfterMerge)
+				continue
+			}
+		}
+
+		if shouldMigrate && options.MergeBoardColumns != nil && options.RequiredBoardID != nil {
+			var statusID string
+			if bug.Fields.Status != nil {
+				statusID = bug.Fields.Status.ID
+			}
+			column, err := ac.BoardColumn(*options.RequiredBoardID, statusID)
+			if err != nil {
+				log.WithError(err).Warn("Unexpected error checking the board column of the Jira bug.")
+				msg += formatError(fmt.Sprintf("checking the column of the bug on board %d", *options.RequiredBoardID), jc.JiraURL(), refBug.Key, err)
+				continue
+			}
+			if !sets.NewString(*options.MergeBoardColumns...).Has(column) {
+				current := "is not in any column"
+				if column != "" {
+					current = fmt.Sprintf("is in the %s column", column)
+				}
+				msg += fmt.Sprintf(issueLink+`: %sThe bug %s of board %d, but it is expected to be in one of the following columns: %s.
+
+The bug will not be moved to the %s state until it is moved to one of the expected columns. Once it is, request a bug refresh with <code>/jira refresh</code>.`, refBug.Key, jc.JiraURL(), refBug.Key, mergedMessage("All"), current, *options.RequiredBoardID, strings.Join(*options.MergeBoardColumns, ", "), options.StateAfterMerge)
+				continue
+			}
+		}
+
 		if shouldMigrate {
 			labels, err := gc.GetIssueLabels(e.org, e.repo, e.number)
 			if err != nil {
@@ -1630,9 +3860,37 @@ These pull request must merge or be unlinked from the Jira bug in order for it t
 	}
 	if msg == "" {
 		return nil
-	} else {
-		return comment(msg)
 	}
+	if e.simulateMerge {
+		msg = "This is a simulation of the merge of this pull request, which has not actually merged. The bugs were handled as they would have been on merge:\n\n" + msg
+	}
+	return comment(msg)
+}
+
+// openBlockedBugs returns the bugs blocked by the given bug that are still open, meaning that
+// they are neither resolved nor closed.
+func openBlockedBugs(jc jiraclient.Client, bug *jira.Issue) ([]*jira.Issue, error) {
+	var blocked []*jira.Issue
+	for _, link := range bug.Fields.IssueLinks {
+		// the outward issue of the Blocks type is the issue blocked by the provided issue
+		if link.Type.Name != "Blocks" || link.OutwardIssue == nil {
+			continue
+		}
+		id := link.OutwardIssue.Key
+		if id == "" {
+			id = link.OutwardIssue.ID
+		}
+		// the issue in the link is very trimmed down; get the full issue for its state
+		blockedBug, err := jc.GetIssue(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s, which is blocked by %s: %w", id, bug.Key, err)
+		}
+		if blockedBug.Fields.Resolution != nil || blockedBug.Fields.Status == nil || strings.EqualFold(blockedBug.Fields.Status.Name, "Closed") {
+			continue
+		}
+		blocked = append(blocked, blockedBug)
+	}
+	return blocked, nil
 }
 
 func identifyClones(issue *jira.Issue) []*jira.Issue {
@@ -1649,9 +3907,24 @@ func identifyClones(issue *jira.Issue) []*jira.Issue {
 
 func handleCherrypick(e event, gc githubClient, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry) error {
 	comment := e.comment(gc)
+	if options.AllowCherrypickClone != nil && !*options.AllowCherrypickClone {
+		return comment(fmt.Sprintf("Automated cloning of bugs for cherrypicks is disabled for the %s branch in the jira plugin config, so no bugs have been cloned. Clone the bugs manually and reference the clones in the title of this pull request instead.", e.baseRef))
+	}
 	var bugs []referencedBug
+	msg := ""
+	title := e.title
 	if e.cherrypickCmd {
 		bugs = e.bugs
+		if conflicting := conflictingTitleBugs(e.titleBugs, e.bugs); len(conflicting) > 0 {
+			if options.CherrypickCommandOverridesTitle == nil || !*options.CherrypickCommandOverridesTitle {
+				return comment(fmt.Sprintf("The title of this pull request already references %s, which is not one of the bugs given to <code>/jira cherrypick</code> (%s), so no bugs have been cherrypicked. To cherrypick a different bug, remove the existing bug reference from the pull request title and run the command again.", strings.Join(conflicting, ", "), strings.Join(referencedBugKeys(e.bugs), ", ")))
+			}
+			// the command takes precedence, so the title is rewritten to only reference the clones
+			msg += fmt.Sprintf("The title of this pull request references %s, but the bugs given to <code>/jira cherrypick</code> take precedence, so %s will be cherrypicked instead.", strings.Join(conflicting, ", "), strings.Join(referencedBugKeys(e.bugs), ", ")) + "\n\n"
+			if match := titleMatchJiraIssue.FindStringIndex(title); match != nil {
+				title = title[:match[0]] + strings.TrimLeft(title[match[1]:], " ")
+			}
+		}
 	} else {
 		// get the info for the PR being cherrypicked from
 		pr, err := gc.GetPullRequest(e.org, e.repo, e.cherrypickFromPRNum)
@@ -1671,9 +3944,7 @@ func handleCherrypick(e event, gc githubClient, jc jiraclient.Client, options Ji
 	commentWithPrefix := func(body string) error {
 		return comment(fmt.Sprintf("Failed to create a cherry-pick bug in Jira: %s", body))
 	}
-	msg := ""
 	retitleList := make(map[string]string)
-refBugLoop:
 	for _, refBug := range bugs {
 		bug, err := getJira(jc, refBug.Key, log, commentWithPrefix)
 		if err != nil || bug == nil {
@@ -1687,205 +3958,937 @@ refBugLoop:
 				continue
 			}
 		}
-		allowed, err := isBugAllowed(bug, options.AllowedSecurityLevels)
+		allowed, err := isBugAllowed(bug, options.AllowedSecurityLevels, options.DeniedSecurityLevels)
+		if err != nil {
+			return fmt.Errorf("failed to check is issue is in allowed security level: %w", err)
+		}
+		if !allowed {
+			// ignore bugs that are in non-allowed groups for this repo
+			continue
+		}
+		oldLink := fmt.Sprintf(issueLink, refBug.Key, jc.JiraURL(), refBug.Key)
+		if options.RequireBackportReady != nil && *options.RequireBackportReady {
+			if options.BackportReadyField == nil || *options.BackportReadyField == "" {
+				msg += fmt.Sprintf("Could not clone %s as it must be marked as ready for backport, but no ready for backport field is configured for this repository.", oldLink) + "\n\n"
+				continue
+			}
+			ready, err := helpers.GetIssueBackportReady(bug, *options.BackportReadyField)
+			if err != nil {
+				msg += fmt.Sprintf("Could not clone %s as checking whether it is ready for backport failed: %v", oldLink, err) + "\n\n"
+				continue
+			}
+			if !ready {
+				msg += fmt.Sprintf("%s is not marked as ready for backport, so it has not been cloned. Once it is, clone it with <code>/jira cherrypick %s</code>.", oldLink, bug.Key) + "\n\n"
+				continue
+			}
+		}
+		if len(e.cherrypickBranches) > 0 {
+			for _, branch := range e.cherrypickBranches {
+				targetVersion, ok := e.cherrypickTargetVersions[branch]
+				if !ok {
+					msg += fmt.Sprintf("Could not clone %s for the %s branch as the target version is not set for that branch in the jira plugin config.", oldLink, branch) + "\n\n"
+					continue
+				}
+				clone, created, cloneMsg, err := cherrypickClone(jc, bug, targetVersion, options, log)
+				if err != nil {
+					return err
+				}
+				if clone == nil {
+					msg += cloneMsg + "\n\n"
+					continue
+				}
+				// only the clone for the branch of this pull request replaces the bug in the title
+				if branch == e.baseRef {
+					retitleList[bug.Key] = clone.Key
+				}
+				cloneLink := fmt.Sprintf(issueLink, clone.Key, jc.JiraURL(), clone.Key)
+				if created {
+					msg += fmt.Sprintf("%s has been cloned as %s for the %s branch.", oldLink, cloneLink, branch) + cloneMsg + "\n\n"
+				} else {
+					msg += fmt.Sprintf("Detected clone %s of %s for the %s branch.", cloneLink, oldLink, branch) + "\n\n"
+				}
+			}
+			continue
+		}
+		if options.TargetVersion == nil {
+			msg += fmt.Sprintf("Could not make automatic cherrypick of %s for this PR as the target version is not set for this branch in the jira plugin config. Running refresh:\n/jira refresh", oldLink) + "\n\n"
+			continue
+		}
+		clone, created, cloneMsg, err := cherrypickClone(jc, bug, *options.TargetVersion, options, log)
+		if err != nil {
+			return err
+		}
+		if clone == nil {
+			msg += cloneMsg + "\n\n"
+			continue
+		}
+		retitleList[bug.Key] = clone.Key
+		if !created {
+			msg += fmt.Sprintf("Detected clone of %s with correct target version. Will retitle the PR to link to the clone.", oldLink) + "\n\n"
+			continue
+		}
+		cloneLink := fmt.Sprintf(issueLink, clone.Key, jc.JiraURL(), clone.Key)
+		msg += fmt.Sprintf("%s has been cloned as %s. Will retitle bug to link to clone.", oldLink, cloneLink) + cloneMsg + "\n\n"
+	}
+	msg = strings.TrimSuffix(msg, "\n\n")
+	if len(retitleList) > 0 {
+		// Replace old bugID(s) in title with new cloneID(s)
+		var newTitle string
+		if e.cherrypickCmd {
+			// use a set to allow sorted keys for deterministic results
+			keySet := sets.NewString()
+			for _, newBug := range retitleList {
+				keySet.Insert(newBug)
+			}
+			var keyList string
+			for _, newBug := range keySet.List() {
+				keyList += newBug + ","
+			}
+			keyList = strings.TrimSuffix(keyList, ",")
+			newTitle = fmt.Sprintf("%s: %s", keyList, title)
+		} else {
+			newTitle = normalizeTitle(e.title)
+			for oldKey, newKey := range retitleList {
+				newTitle = strings.ReplaceAll(newTitle, oldKey, newKey)
+			}
+		}
+		retitleCommand := defaultRetitleCommand
+		if options.RetitleCommand != nil {
+			retitleCommand = *options.RetitleCommand
+		}
+		msg += fmt.Sprintf("\n%s %s", retitleCommand, newTitle)
+	}
+	return comment(msg)
+}
+
+// clonedFrom returns the issue that the given issue was cloned from, as referenced in the
+// Cloners link of the issue, or nil if the issue is not a clone
+func clonedFrom(issue *jira.Issue) *jira.Issue {
+	if issue.Fields == nil {
+		return nil
+	}
+	for _, link := range issue.Fields.IssueLinks {
+		// the outward issue of the Cloners type is always the original of the provided issue
+		if link.Type.Name == "Cloners" && link.OutwardIssue != nil {
+			return link.OutwardIssue
+		}
+	}
+	return nil
+}
+
+// handleReclone replaces the clone referenced in the title of the pull request, which was cloned
+// from the wrong bug, with a clone of the bug given to the reclone command. The links between the
+// existing clone and its parent are only removed once the replacement exists, and the existing
+// clone is not deleted, but gets a comment pointing to its replacement so that it is not lost.
+func handleReclone(e event, gc githubClient, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry) error {
+	comment := e.comment(gc)
+	if e.missing || len(e.bugs) != 1 {
+		return comment("The title of this pull request needs to reference exactly one bug, the clone to replace, for <code>/jira reclone</code> to be used.")
+	}
+	oldClone, err := getJira(jc, e.bugs[0].Key, log, comment)
+	if err != nil || oldClone == nil {
+		return err
+	}
+	oldCloneLink := fmt.Sprintf(issueLink, oldClone.Key, jc.JiraURL(), oldClone.Key)
+	parentRef := clonedFrom(oldClone)
+	if parentRef == nil {
+		return comment(fmt.Sprintf("%s is not a clone of another bug, so it cannot be recloned. Use <code>/jira cherrypick</code> to clone a bug for this pull request instead.", oldCloneLink))
+	}
+	id := parentRef.Key
+	if id == "" {
+		id = parentRef.ID
+	}
+	wrongParent, err := jc.GetIssue(id)
+	if err != nil {
+		log.WithError(err).Warn("Unexpected error getting the parent of the clone.")
+		return comment(formatError(fmt.Sprintf("searching for %s, which %s was cloned from", id, oldClone.Key), jc.JiraURL(), oldClone.Key, err))
+	}
+	wrongParentLink := fmt.Sprintf(issueLink, wrongParent.Key, jc.JiraURL(), wrongParent.Key)
+	parent, err := getJira(jc, e.recloneParent, log, comment)
+	if err != nil || parent == nil {
+		return err
+	}
+	parentLink := fmt.Sprintf(issueLink, parent.Key, jc.JiraURL(), parent.Key)
+	if parent.ID == wrongParent.ID {
+		return comment(fmt.Sprintf("%s is already a clone of %s, so it has not been recloned.", oldCloneLink, parentLink))
+	}
+	if options.TargetVersion == nil {
+		return comment(fmt.Sprintf("Could not reclone %s from %s as the target version is not set for this branch in the jira plugin config.", oldCloneLink, parentLink))
+	}
+	if options.AllowCherrypickClone != nil && !*options.AllowCherrypickClone {
+		return comment(fmt.Sprintf("Could not reclone %s from %s as automated cloning of bugs is disabled for the %s branch in the jira plugin config.", oldCloneLink, parentLink, e.baseRef))
+	}
+	clone, created, cloneMsg, err := cherrypickClone(jc, parent, *options.TargetVersion, options, log)
+	if err != nil {
+		return err
+	}
+	if clone == nil {
+		return comment(cloneMsg)
+	}
+	cloneLink := fmt.Sprintf(issueLink, clone.Key, jc.JiraURL(), clone.Key)
+	msg := fmt.Sprintf("%s has been cloned as %s to replace %s, which was cloned from %s.", parentLink, cloneLink, oldCloneLink, wrongParentLink) + cloneMsg
+	if !created {
+		msg = fmt.Sprintf("Detected clone %s of %s, which replaces %s, which was cloned from %s.", cloneLink, parentLink, oldCloneLink, wrongParentLink)
+	}
+	// now that the replacement exists, the existing clone is detached from the wrong parent; the
+	// links are collected first as deleting them may modify the links of the issue
+	var parentLinks []jira.IssueLink
+	for _, link := range oldClone.Fields.IssueLinks {
+		if link.Type.Name != "Cloners" && link.Type.Name != "Blocks" {
+			continue
+		}
+		linked := link.OutwardIssue
+		if linked == nil {
+			linked = link.InwardIssue
+		}
+		if linked != nil && (linked.ID == wrongParent.ID || linked.Key == wrongParent.Key) {
+			parentLinks = append(parentLinks, *link)
+		}
+	}
+	for _, link := range parentLinks {
+		if err := jc.DeleteLink(link.ID); err != nil {
+			log.WithError(err).Warn("Unexpected error removing the link between the clone and its parent.")
+			msg += "\n\n" + formatError(fmt.Sprintf("removing the %s link between %s and %s", link.Type.Name, oldClone.Key, wrongParent.Key), jc.JiraURL(), oldClone.Key, err)
+		}
+	}
+	jiraComment := &jira.Comment{Body: fmt.Sprintf("This issue was cloned from %s by mistake and has been replaced by %s, a clone of %s, in PR https://github.com/%s/%s/pull/%d. Close this issue if it is not needed anymore.", wrongParent.Key, clone.Key, parent.Key, e.org, e.repo, e.number), Visibility: PrivateVisibility}
+	if _, err := jc.AddComment(oldClone.ID, jiraComment); err != nil {
+		msg += fmt.Sprintf("\n\nWarning: Failed to comment on %s about its replacement.", oldCloneLink)
+	}
+	retitleCommand := defaultRetitleCommand
+	if options.RetitleCommand != nil {
+		retitleCommand = *options.RetitleCommand
+	}
+	msg += fmt.Sprintf("\n%s %s", retitleCommand, strings.ReplaceAll(normalizeTitle(e.title), oldClone.Key, clone.Key))
+	return comment(msg)
+}
+
+// inProject returns a copy of the issue filed in the project with the given key, so that
+// cloning the copy creates the clone in that project. Only the fields of the issue are copied.
+func inProject(issue *jira.Issue, project string) *jira.Issue {
+	fields := *issue.Fields
+	// the project of a new issue is identified by its key; the ID of the original project
+	// would take precedence over it
+	fields.Project = jira.Project{Key: project, Name: project}
+	copied := *issue
+	copied.Fields = &fields
+	return &copied
+}
+
+// cherrypickClone finds the clone of the bug that targets the given version or, if there is none,
+// creates one. It returns the clone and whether it was created by this call. If no clone could be
+// found or created, the returned clone is nil and the message explains why; otherwise the message
+// holds warnings about the created clone, if any.
+func cherrypickClone(jc jiraclient.Client, bug *jira.Issue, targetVersion string, options JiraBranchOptions, log *logrus.Entry) (*jira.Issue, bool, string, error) {
+	for _, baseClone := range identifyClones(bug) {
+		// get full issue struct; links to clones created while handling this event may only
+		// hold the ID of the clone
+		id := baseClone.Key
+		if id == "" {
+			id = baseClone.ID
+		}
+		clone, err := jc.GetIssue(id)
+		if err != nil {
+			return nil, false, "", fmt.Errorf("failed to get %s, which is a clone of %s: %w", id, bug.Key, err)
+		}
+		cloneVersion, err := helpers.GetIssueTargetVersion(clone, options.customFields())
+		if err != nil {
+			return nil, false, formatError(fmt.Sprintf("getting the target version for clone %s", clone.Key), jc.JiraURL(), bug.Key, err), nil
+		}
+		if len(cloneVersion) == 1 && cloneVersion[0].Name == targetVersion {
+			return clone, false, "", nil
+		}
+	}
+	parent := bug
+	if options.CloneTargetProject != nil && *options.CloneTargetProject != "" && *options.CloneTargetProject != projectFromKey(bug.Key) {
+		parent = inProject(bug, *options.CloneTargetProject)
+	}
+	clone, err := jc.CloneIssue(parent)
+	if err != nil {
+		log.WithError(err).Debugf("Failed to clone bug %s", bug.Key)
+		return nil, false, formatError("cloning bug for cherrypick", jc.JiraURL(), bug.Key, err), nil
+	}
+	oldLink := fmt.Sprintf(issueLink, bug.Key, jc.JiraURL(), bug.Key)
+	cloneLink := fmt.Sprintf(issueLink, clone.Key, jc.JiraURL(), clone.Key)
+	// add blocking issue link between parent and clone, unless disabled for this branch
+	if options.CreateBlocksLinkOnClone == nil || *options.CreateBlocksLinkOnClone {
+		blockLink := jira.IssueLink{
+			OutwardIssue: &jira.Issue{ID: clone.ID},
+			InwardIssue:  &jira.Issue{ID: bug.ID},
+			Type: jira.IssueLinkType{
+				Name:    "Blocks",
+				Inward:  "is blocked by",
+				Outward: "blocks",
+			},
+		}
+		if err := jc.CreateIssueLink(&blockLink); err != nil {
+			log.WithError(err).Debugf("Unable to create blocks link for bug %s", clone.Key)
+			return nil, false, formatError(fmt.Sprintf("updating cherry-pick bug in Jira: Created cherrypick %s, but encountered error creating `Blocks` type link with original bug", cloneLink), jc.JiraURL(), clone.Key, err), nil
+		}
+	}
+	var warnings string
+	// Update the version of the bug to the target release. The parent's components are set
+	// again as they may have been dropped if Jira refused to set them when creating the clone.
+	update := jira.Issue{
+		Key: clone.Key,
+		Fields: &jira.IssueFields{
+			Components: bug.Fields.Components,
+			Unknowns: tcontainer.MarshalMap{
+				options.customFields().TargetVersion: []*jira.Version{{Name: targetVersion}},
+			},
+		},
+	}
+	if _, err := jc.UpdateIssue(&update); err != nil {
+		warnings += fmt.Sprintf(`
+
+WARNING: Failed to update the target version for the clone. Please update the target version manually. Full error below:
+<details><summary>Full error message.</summary>
+
+<code>
+%v
+</code>
+
+</details>`, err)
+	}
+	if options.MinParentDescriptionLength != nil {
+		if length := len(strings.TrimSpace(bug.Fields.Description)); length < *options.MinParentDescriptionLength {
+			description := "empty"
+			if length > 0 {
+				description = fmt.Sprintf("only %d characters long", length)
+			}
+			warnings += fmt.Sprintf("\n\nWARNING: The description of %s is %s, which is shorter than the expected minimum of %d characters, and %s inherits it. Please document the bug so that the backport can be understood on its own.", oldLink, description, *options.MinParentDescriptionLength, cloneLink)
+		}
+	}
+	return clone, true, warnings, nil
+}
+
+// conflictingTitleBugs returns the keys of the bugs referenced in the title that were not
+// given to the cherrypick command
+func conflictingTitleBugs(titleBugs, commandBugs []referencedBug) []string {
+	commandKeys := sets.NewString(referencedBugKeys(commandBugs)...)
+	var conflicting []string
+	for _, bug := range titleBugs {
+		if bug.IsBug && !commandKeys.Has(bug.Key) {
+			conflicting = append(conflicting, bug.Key)
+		}
+	}
+	return conflicting
+}
+
+func referencedBugKeys(bugs []referencedBug) []string {
+	var keys []string
+	for _, bug := range bugs {
+		keys = append(keys, bug.Key)
+	}
+	return keys
+}
+
+// normalizeTitle returns the title with a space after the colon that ends the bug reference.
+// Titles like `OCPBUGS-123:summary` reference the bug like `OCPBUGS-123: summary` does, but the
+// titles suggested by the plugin always use the latter form.
+func normalizeTitle(title string) string {
+	match := titleMatchJiraIssue.FindStringIndex(title)
+	if match == nil || match[1] == len(title) || title[match[1]] == ' ' {
+		return title
+	}
+	return title[:match[1]] + " " + title[match[1]:]
+}
+
+// jiraKeyFromTitle identifies the Jira keys referenced in the title. Bugzilla references (e.g. `Bug 34:`)
+// are never treated as bugs by this plugin, so when a title contains both a Jira key and a Bugzilla ID
+// the Jira key always wins and cherrypicks will only ever clone the Jira bug.
+// return values:
+// 1: issues as an array of referencedBug, if exists
+// 2: missing: true/false based on whether the title is missing a jira ref
+// 3: noJira: true/false based on whether the title contains jira excluding term (i.e. "NO-JIRA" or "NO-ISSUE")
+func jiraKeyFromTitle(title string) ([]referencedBug, bool, bool) {
+	/*
+		// we only match stuff before a ":"
+		splitTitle := strings.Split(title, ":")
+		if len(splitTitle) < 2 {
+			return nil, true, false
+		}
+		trimmedTitle := splitTitle[0]
+		// if there is a space before the `:`,  we ignore this, as the title is improperly formatted
+		if string(trimmedTitle[len(trimmedTitle)-1]) == " " {
+			return nil, true, false
+		}
+		// if there are square brackets, remove them and everything between them
+		leftBracket := strings.Index(title, "[")
+		rightBracket := strings.Index(title, "]")
+		if rightBracket-leftBracket != 0 {
+			trimmedTitle = trimmedTitle[0:leftBracket] + trimmedTitle[rightBracket+1:]
+		}
+	*/
+	matches := titleMatchJiraIssue.FindString(title)
+	if len(matches) == 0 {
+		return nil, true, false
+	}
+	bugs := []referencedBug{}
+	splitMatch := strings.Split(matches, ",")
+	for _, match := range splitMatch {
+		match = strings.TrimSuffix(match, ":")
+		if strings.EqualFold(match, "NO-ISSUE") || strings.EqualFold(match, "NO-JIRA") {
+			return nil, false, true
+		}
+		bugs = append(bugs, referencedBug{
+			Key:   match,
+			IsBug: strings.Contains(match, "OCPBUGS-"),
+		})
+	}
+	return bugs, false, false
+}
+
+// isRevertTitle determines whether the title is the one of a pull request reverting another,
+// e.g. `Revert "OCPBUGS-123: fix it"`, in which case the referenced bugs are the reverted ones
+func isRevertTitle(title string) bool {
+	return revertTitleMatch.MatchString(title)
+}
+
+func getJira(jc jiraclient.Client, jiraKey string, log *logrus.Entry, comment func(string) error) (*jira.Issue, error) {
+	issue, _, msg := lookupJira(jc, jiraKey, log)
+	if issue == nil {
+		return nil, comment(msg)
+	}
+	return issue, nil
+}
+
+// lookupJira gets the Jira issue with the given key. If it cannot be found, the returned issue
+// is nil and the message explains why, for callers that report on several issues at once.
+// notFound distinguishes issues that do not exist from errors reaching the Jira server.
+func lookupJira(jc jiraclient.Client, jiraKey string, log *logrus.Entry) (issue *jira.Issue, notFound bool, msg string) {
+	issue, err := jc.GetIssue(jiraKey)
+	if err != nil && !jiraclient.IsNotFound(err) {
+		log.WithError(err).Warn("Unexpected error searching for Jira issue.")
+		return nil, false, formatError("searching", jc.JiraURL(), jiraKey, err)
+	}
+	if jiraclient.IsNotFound(err) || issue == nil {
+		log.Debug("No jira issue found.")
+		return nil, true, fmt.Sprintf(`No Jira issue with key %s exists in the tracker at %s.
+Once a valid jira issue is referenced in the title of this pull request, request a refresh with <code>/jira refresh</code>.`,
+			jiraKey, jc.JiraURL())
+	}
+	return issue, false, ""
+}
+
+// handleDeletedJira cleans up after a referenced bug that no longer exists, e.g. because it was
+// deleted after the pull request was validated: the labels claiming a valid reference are removed
+// and the comment says what happened. It returns false if the pull request has none of these
+// labels, as the bug then likely never existed and the caller reports it as usual.
+func handleDeletedJira(e event, gc githubClient, jc jiraclient.Client, jiraKey string, log *logrus.Entry) (bool, error) {
+	currentLabels, err := gc.GetIssueLabels(e.org, e.repo, e.number)
+	if err != nil {
+		return false, fmt.Errorf("failed to list labels on PR: %w", err)
+	}
+	var removed []string
+	for _, l := range currentLabels {
+		if l.Name != labels.JiraValidRef && l.Name != labels.JiraValidBug {
+			continue
+		}
+		if err := gc.RemoveLabel(e.org, e.repo, e.number, l.Name); err != nil {
+			log.WithError(err).Errorf("Failed to remove %s label.", l.Name)
+			continue
+		}
+		removed = append(removed, l.Name)
+	}
+	if len(removed) == 0 {
+		return false, nil
+	}
+	log.Info("Referenced Jira issue no longer exists, removed valid labels.")
+	return true, e.comment(gc)(fmt.Sprintf(`The Jira issue %s referenced by this pull request no longer exists in the tracker at %s; it may have been deleted. The following labels have been removed: %s.
+Once a valid jira issue is referenced in the title of this pull request, request a refresh with <code>/jira refresh</code>.`,
+		jiraKey, jc.JiraURL(), strings.Join(removed, ", ")))
+}
+
+func formatError(action, endpoint, bugKey string, err error) string {
+	knownErrors := map[string]string{
+		// TODO: Most of this code is copied from the bugzilla client. If Jira rate limits us the same way, this could come in handy. We will keep this for now in case it is needed
+		//"There was an error reported for a GitHub REST call": "The Bugzilla server failed to load data from GitHub when creating the bug. This is usually caused by rate-limiting, please try again later.",
+	}
+	var applicable []string
+	for key, value := range knownErrors {
+		if strings.Contains(err.Error(), key) {
+			applicable = append(applicable, value)
+		}
+	}
+	digest := "No known errors were detected, please see the full error message for details."
+	if len(applicable) > 0 {
+		digest = "We were able to detect the following conditions from the error:\n\n"
+		for _, item := range applicable {
+			digest = fmt.Sprintf("%s- %s\n", digest, item)
+		}
+	}
+	return fmt.Sprintf(`An error was encountered %s for bug %s on the Jira server at %s. %s
+
+<details><summary>Full error message.</summary>
+
+<code>
+%v
+</code>
+
+</details>
+
+Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.`,
+		action, bugKey, endpoint, digest, err)
+}
+
+// handleConvertedToDraft notes that the bugs referenced by a pull request that was converted to
+// a draft will not advance until it is marked as ready for review again
+func handleConvertedToDraft(e event, gc githubClient, options JiraBranchOptions) error {
+	if options.StateAfterValidation == nil && options.PreMergeStateAfterValidation == nil {
+		return nil
+	}
+	var keys []string
+	for _, refBug := range e.bugs {
+		if refBug.IsBug {
+			keys = append(keys, refBug.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return e.comment(gc)(fmt.Sprintf("This pull request has been converted to a draft, so %s will not be moved to a new state until it is marked as ready for review again.", strings.Join(keys, ", ")))
+}
+
+// dependentsOf collects the bugs the issue depends on, as validated by validateBug. The pull
+// requests linked to the dependents are only checked if RequireDependentPRsMerged is set. On
+// error, action describes what failed for use with formatError.
+func dependentsOf(e event, ghc githubClient, jc jiraclient.Client, issue *jira.Issue, options JiraBranchOptions, allRepos sets.String) (dependents []dependent, action string, err error) {
+	for _, link := range issue.Fields.IssueLinks {
+		// identify if bug depends on this link; multiple different types of links may be blocker types; more can be added as they are identified
+		dependsOn := false
+		dependsOn = dependsOn || (link.InwardIssue != nil && link.Type.Name == "Blocks" && link.Type.Inward == "is blocked by")
+		dependsOn = dependsOn || (link.OutwardIssue != nil && link.Type.Name == "Depend" && link.Type.Outward == "depends on")
+		if !dependsOn {
+			continue
+		}
+		// link may be either an outward or inward issue; depends on the link type
+		linkIssue := link.InwardIssue
+		if linkIssue == nil {
+			linkIssue = link.OutwardIssue
+		}
+		// the issue in the link is very trimmed down; get full link for dependentIssue list
+		dependentIssue, err := jc.GetIssue(linkIssue.Key)
+		if err != nil {
+			return nil, fmt.Sprintf("searching for dependent bug %s", linkIssue.Key), err
+		}
+		targetVersion, err := helpers.GetIssueTargetVersion(dependentIssue, options.customFields())
+		if err != nil {
+			return nil, fmt.Sprintf("failed to get target version for %s", dependentIssue.Key), err
+		}
+		var targetVersionString *string
+		if len(targetVersion) != 0 {
+			targetVersionString = &targetVersion[0].Name
+		}
+		dependentState := JiraBugState{}
+		if dependentIssue.Fields.Status != nil {
+			dependentState.Status = dependentIssue.Fields.Status.Name
+		}
+		if dependentIssue.Fields.Resolution != nil {
+			dependentState.Resolution = dependentIssue.Fields.Resolution.Name
+		}
+		newDependent := dependent{
+			key:           dependentIssue.Key,
+			targetVersion: targetVersionString,
+			bugState:      dependentState,
+			components:    componentNames(dependentIssue),
+		}
+		if options.RequireDependentPRsMerged != nil && *options.RequireDependentPRsMerged {
+			unmerged, err := unmergedLinkedPRs(e, ghc, jc, dependentIssue.ID, allRepos)
+			if err != nil {
+				return nil, fmt.Sprintf("checking the pull requests linked to dependent bug %s", dependentIssue.Key), err
+			}
+			newDependent.unmergedPRs = unmerged
+		}
+		dependents = append(dependents, newDependent)
+	}
+	return dependents, "", nil
+}
+
+// handleDeps lists the dependents of the bugs referenced in the title of the pull request as
+// they are seen by the validation, to help understand why dependent checks failed.
+func handleDeps(e event, gc githubClient, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry, allRepos sets.String) error {
+	comment := e.comment(gc)
+	if e.missing || e.noJira || len(e.bugs) == 0 {
+		return comment("No Jira issue is referenced in the title of this pull request, so there are no dependent bugs to list.")
+	}
+	var responses []string
+	for _, refBug := range e.bugs {
+		issue, err := getJira(jc, refBug.Key, log, comment)
+		if err != nil || issue == nil {
+			return err
+		}
+		dependents, action, err := dependentsOf(e, gc, jc, issue, options, allRepos)
+		if err != nil {
+			log.WithError(err).Warn("Unexpected error collecting the dependents of the Jira bug.")
+			return comment(formatError(action, jc.JiraURL(), refBug.Key, err))
+		}
+		if len(dependents) == 0 {
+			responses = append(responses, fmt.Sprintf(issueLink+" does not depend on any bugs.", refBug.Key, jc.JiraURL(), refBug.Key))
+			continue
+		}
+		var statements []string
+		for _, dependent := range dependents {
+			targetVersion := "no target version"
+			if dependent.targetVersion != nil {
+				targetVersion = "target version " + *dependent.targetVersion
+			}
+			statement := fmt.Sprintf(" * "+issueLink+" is in the %s state, with %s", dependent.key, jc.JiraURL(), dependent.key, PrettyStatus(dependent.bugState.Status, dependent.bugState.Resolution), targetVersion)
+			if len(dependent.unmergedPRs) > 0 {
+				statement += fmt.Sprintf(" and unmerged pull requests %s", strings.Join(dependent.unmergedPRs, ", "))
+			}
+			statements = append(statements, statement)
+		}
+		responses = append(responses, fmt.Sprintf(issueLink+" depends on the following bugs:\n%s", refBug.Key, jc.JiraURL(), refBug.Key, strings.Join(statements, "\n")))
+	}
+	return comment(strings.Join(responses, "\n\n"))
+}
+
+// handleListPRs comments with the pull requests linked to the referenced issues via remote links
+// and whether they have merged.
+func handleListPRs(e event, gc githubClient, jc jiraclient.Client, log *logrus.Entry, allRepos sets.String) error {
+	comment := e.comment(gc)
+	if e.missing || e.noJira || len(e.bugs) == 0 {
+		return comment("No Jira issue is referenced in the title of this pull request, so there are no linked pull requests to list.")
+	}
+	var responses []string
+	for _, refBug := range e.bugs {
+		issue, err := getJira(jc, refBug.Key, log, comment)
+		if err != nil || issue == nil {
+			return err
+		}
+		links, err := jc.GetRemoteLinks(issue.ID)
+		if err != nil {
+			log.WithError(err).Warn("Unexpected error listing external tracker bugs for Jira bug.")
+			return comment(formatError("searching for external tracker bugs", jc.JiraURL(), refBug.Key, err))
+		}
+		var statements []string
+		for _, link := range links {
+			if link.Object == nil {
+				continue
+			}
+			item, isPR, err := prPartsFromURL(link.Object.URL)
+			if !isPR {
+				continue
+			}
+			if err != nil {
+				log.WithError(err).Warn("Unexpected error splitting github URL for Jira external link.")
+				statements = append(statements, fmt.Sprintf(" * %s could not be parsed: %v", link.Object.URL, err))
+				continue
+			}
+			prLink := fmt.Sprintf("[%s/%s#%d](https://github.com/%s/%s/pull/%d)", item.Org, item.Repo, item.Num, item.Org, item.Repo, item.Num)
+			merged, state, managed, err := pullRequestState(e, gc, item, allRepos)
+			switch {
+			case err != nil:
+				log.WithError(err).Warn("Unexpected error checking merge state of related pull request.")
+				statements = append(statements, fmt.Sprintf(" * %s could not be checked: %v", prLink, err))
+			case !managed:
+				statements = append(statements, fmt.Sprintf(" * %s is in a repository that is not managed by this plugin", prLink))
+			case merged:
+				statements = append(statements, fmt.Sprintf(" * %s is merged", prLink))
+			case state == github.PullRequestStateClosed:
+				statements = append(statements, fmt.Sprintf(" * %s is closed without merging", prLink))
+			default:
+				statements = append(statements, fmt.Sprintf(" * %s is %s", prLink, state))
+			}
+		}
+		if len(statements) == 0 {
+			responses = append(responses, fmt.Sprintf(issueLink+" is not linked to any pull requests.", refBug.Key, jc.JiraURL(), refBug.Key))
+			continue
+		}
+		responses = append(responses, fmt.Sprintf(issueLink+" is linked to the following pull requests:\n%s", refBug.Key, jc.JiraURL(), refBug.Key, strings.Join(statements, "\n")))
+	}
+	return comment(strings.Join(responses, "\n\n"))
+}
+
+// handleRetry re-attempts the state transition the plugin intends to make for the pull request
+// in its current state, without re-running the full validation. Merged and closed pull requests
+// are handled as on the merge or close event, while the bugs referenced by open pull requests
+// that were found to be valid are moved to the StateAfterValidation.
+func handleRetry(e event, gc githubClient, jc jiraclient.Client, ac agileClient, options JiraBranchOptions, log *logrus.Entry, allRepos sets.String) error {
+	comment := e.comment(gc)
+	if e.merged {
+		return handleMerge(e, gc, jc, ac, options, log, allRepos)
+	}
+	if e.closed {
+		return handleClose(e, gc, jc, options, log, allRepos)
+	}
+	if e.missing || e.noJira {
+		return comment("No Jira bug is referenced in the title of this pull request, so there is no state transition to retry.")
+	}
+	if options.StateAfterValidation == nil || options.StateAfterValidation.Status == "" {
+		return comment("No state is configured for valid bugs in this repository, so there is no state transition to retry.")
+	}
+	if e.draft {
+		// the transition is performed once the pull request is marked as ready for review
+		return comment("This pull request is a draft, so the referenced bugs will not be moved to a new state until it is marked as ready for review.")
+	}
+	currentLabels, err := gc.GetIssueLabels(e.org, e.repo, e.number)
+	if err != nil {
+		return fmt.Errorf("failed to list labels on PR: %w", err)
+	}
+	var hasJiraValidBugLabel bool
+	for _, l := range currentLabels {
+		if l.Name == labels.JiraValidBug {
+			hasJiraValidBugLabel = true
+		}
+	}
+	if !hasJiraValidBugLabel {
+		return comment(fmt.Sprintf("This pull request does not have the %s label, so no state transition is pending. To re-validate the referenced bug, request a bug refresh with <code>/jira refresh</code>.", labels.JiraValidBug))
+	}
+
+	var responses []string
+	for _, refBug := range e.bugs {
+		if !refBug.IsBug {
+			continue
+		}
+		issue, err := getJira(jc, refBug.Key, log, comment)
+		if err != nil || issue == nil {
+			return err
+		}
+		if issue.Fields.Status != nil && strings.EqualFold(options.StateAfterValidation.Status, issue.Fields.Status.Name) {
+			responses = append(responses, fmt.Sprintf(issueLink+" is already in the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterValidation))
+			continue
+		}
+		reachable, available, action, err := transitionBug(jc, issue, *options.StateAfterValidation)
 		if err != nil {
-			return fmt.Errorf("failed to check is issue is in allowed security level: %w", err)
+			log.WithError(err).Warn("Unexpected error transitioning jira issue.")
+			return comment(formatError(action, jc.JiraURL(), refBug.Key, err))
 		}
-		if !allowed {
-			// ignore bugs that are in non-allowed groups for this repo
+		if !reachable {
+			responses = append(responses, fmt.Sprintf(issueLink+" could not be moved to the %s state because no transition to %s exists. Available transitions: %s.", refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterValidation, options.StateAfterValidation.Status, strings.Join(available, ", ")))
 			continue
 		}
-		clones := identifyClones(bug)
-		oldLink := fmt.Sprintf(issueLink, refBug.Key, jc.JiraURL(), refBug.Key)
-		if options.TargetVersion == nil {
-			msg += fmt.Sprintf("Could not make automatic cherrypick of %s for this PR as the target version is not set for this branch in the jira plugin config. Running refresh:\n/jira refresh", oldLink) + "\n\n"
+		responses = append(responses, fmt.Sprintf(issueLink+" has been moved to the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterValidation))
+	}
+	if len(responses) == 0 {
+		return comment("No Jira bug is referenced in the title of this pull request, so there is no state transition to retry.")
+	}
+	return comment(strings.Join(responses, "\n\n"))
+}
+
+// handleRevertMerge moves the bugs whose fix was reverted by the merged pull request to the
+// StateAfterRevert, as the fix is no longer present.
+func handleRevertMerge(e event, gc githubClient, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry) error {
+	comment := e.comment(gc)
+	var responses []string
+	for _, refBug := range e.bugs {
+		if !refBug.IsBug {
 			continue
 		}
-		targetVersion := *options.TargetVersion
-		for _, baseClone := range clones {
-			// get full issue struct
-			clone, err := jc.GetIssue(baseClone.Key)
-			if err != nil {
-				return fmt.Errorf("failed to get %s, which is a clone of %s: %w", baseClone.Key, bug.Key, err)
-			}
-			cloneVersion, err := helpers.GetIssueTargetVersion(clone)
-			if err != nil {
-				msg += formatError(fmt.Sprintf("getting the target version for clone %s", clone.Key), jc.JiraURL(), bug.Key, err) + "\n\n"
-				continue refBugLoop
-			}
-			if len(cloneVersion) == 1 && cloneVersion[0].Name == targetVersion {
-				msg += fmt.Sprintf("Detected clone of %s with correct target version. Will retitle the PR to link to the clone.", oldLink) + "\n\n"
-				retitleList[bug.Key] = clone.Key
-				continue refBugLoop
-			}
+		issue, err := getJira(jc, refBug.Key, log, comment)
+		if err != nil || issue == nil {
+			return err
 		}
-		clone, err := jc.CloneIssue(bug)
-		if err != nil {
-			log.WithError(err).Debugf("Failed to clone bug %+v", bugs)
-			msg += formatError("cloning bug for cherrypick", jc.JiraURL(), bug.Key, err) + "\n\n"
+		if issue.Fields.Status != nil && strings.EqualFold(issue.Fields.Status.Name, options.StateAfterRevert.Status) {
+			responses = append(responses, fmt.Sprintf("This pull request reverts the fix for "+issueLink+", which is already in the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterRevert))
 			continue
 		}
-		cloneLink := fmt.Sprintf(issueLink, clone.Key, jc.JiraURL(), clone.Key)
-		// add blocking issue link between parent and clone
-		blockLink := jira.IssueLink{
-			OutwardIssue: &jira.Issue{ID: clone.ID},
-			InwardIssue:  &jira.Issue{ID: bug.ID},
-			Type: jira.IssueLinkType{
-				Name:    "Blocks",
-				Inward:  "is blocked by",
-				Outward: "blocks",
-			},
+		reachable, available, action, err := transitionBug(jc, issue, *options.StateAfterRevert)
+		if err != nil {
+			log.WithError(err).Warn("Unexpected error transitioning jira issue.")
+			return comment(formatError(action, jc.JiraURL(), refBug.Key, err))
 		}
-		if err := jc.CreateIssueLink(&blockLink); err != nil {
-			log.WithError(err).Debugf("Unable to create blocks link for bug %s", clone.Key)
-			msg += formatError(fmt.Sprintf("updating cherry-pick bug in Jira: Created cherrypick %s, but encountered error creating `Blocks` type link with original bug", cloneLink), jc.JiraURL(), clone.Key, err) + "\n\n"
+		if !reachable {
+			responses = append(responses, fmt.Sprintf("This pull request reverts the fix for "+issueLink+", but it could not be moved to the %s state because no transition to %s exists. Available transitions: %s.", refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterRevert, options.StateAfterRevert.Status, strings.Join(available, ", ")))
 			continue
 		}
-		response := fmt.Sprintf("%s has been cloned as %s. Will retitle bug to link to clone.", oldLink, cloneLink)
-		retitleList[bug.Key] = clone.Key
-		// Update the version of the bug to the target release
-		update := jira.Issue{
-			Key: clone.Key,
-			Fields: &jira.IssueFields{
-				Unknowns: tcontainer.MarshalMap{
-					helpers.TargetVersionField: []*jira.Version{{Name: targetVersion}},
-				},
-			},
+		responses = append(responses, fmt.Sprintf("This pull request reverts the fix for "+issueLink+", which has been moved to the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterRevert))
+		jiraComment := &jira.Comment{Body: fmt.Sprintf("Bug status changed to %s as its fix was reverted by https://github.com/%s/%s/pull/%d", options.StateAfterRevert, e.org, e.repo, e.number), Visibility: PrivateVisibility}
+		if _, err := jc.AddComment(issue.ID, jiraComment); err != nil {
+			log.WithError(err).Warn("Failed to comment on the Jira bug with the reason for the changed state.")
 		}
-		_, err = jc.UpdateIssue(&update)
-		if err != nil {
-			response += fmt.Sprintf(`
-
-WARNING: Failed to update the target version for the clone. Please update the target version manually. Full error below:
-<details><summary>Full error message.</summary>
-
-<code>
-%v
-</code>
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return comment(strings.Join(responses, "\n\n"))
+}
 
-</details>`, err)
-		}
-		msg += response + "\n\n"
+// transitionBug moves the bug to the state, setting the resolution if one is given. If no
+// transition to the state exists, reachable is false and the available transitions are
+// returned instead. On error, action describes what failed for use with formatError.
+func transitionBug(jc jiraclient.Client, issue *jira.Issue, state JiraBugState) (reachable bool, available []string, action string, err error) {
+	reachable, available, err = isStatusReachable(jc, issue.ID, state.Status)
+	if err != nil {
+		return false, nil, "getting the available transitions", err
 	}
-	msg = strings.TrimSuffix(msg, "\n\n")
-	if len(retitleList) > 0 {
-		// Replace old bugID(s) in title with new cloneID(s)
-		var newTitle string
-		if e.cherrypickCmd {
-			// use a set to allow sorted keys for deterministic results
-			keySet := sets.NewString()
-			for _, newBug := range retitleList {
-				keySet.Insert(newBug)
-			}
-			var keyList string
-			for _, newBug := range keySet.List() {
-				keyList += newBug + ","
-			}
-			keyList = strings.TrimSuffix(keyList, ",")
-			newTitle = fmt.Sprintf("%s: %s", keyList, e.title)
-		} else {
-			newTitle = e.title
-			for oldKey, newKey := range retitleList {
-				newTitle = strings.ReplaceAll(newTitle, oldKey, newKey)
-			}
+	if !reachable {
+		return false, available, "", nil
+	}
+	if err := jc.UpdateStatus(issue.ID, state.Status); err != nil {
+		return true, nil, fmt.Sprintf("updating to the %s state", state.Status), err
+	}
+	if state.Resolution != "" && (issue.Fields.Resolution == nil || !strings.EqualFold(state.Resolution, issue.Fields.Resolution.Name)) {
+		updateIssue := jira.Issue{Key: issue.Key, Fields: &jira.IssueFields{Resolution: &jira.Resolution{Name: state.Resolution}}}
+		if _, err := jc.UpdateIssue(&updateIssue); err != nil {
+			return true, nil, fmt.Sprintf("updating to the %s resolution", state.Resolution), err
 		}
-		msg += "\n/retitle " + newTitle
 	}
-	return comment(msg)
+	return true, nil, "", nil
 }
 
-// return values:
-// 1: issues as an array of referencedBug, if exists
-// 2: missing: true/false based on whether the title is missing a jira ref
-// 3: noJira: true/false based on whether the title contains jira excluding term (i.e. "NO-JIRA" or "NO-ISSUE")
-func jiraKeyFromTitle(title string) ([]referencedBug, bool, bool) {
-	/*
-		// we only match stuff before a ":"
-		splitTitle := strings.Split(title, ":")
-		if len(splitTitle) < 2 {
-			return nil, true, false
+// handleVerify moves the bugs referenced by a merged pull request to the VERIFIED state on
+// behalf of an org member, for teams that verify their own fixes.
+func handleVerify(e event, gc githubClient, jc jiraclient.Client, log *logrus.Entry) error {
+	comment := e.comment(gc)
+	isMember, err := gc.IsMember(e.org, e.login)
+	if err != nil {
+		return fmt.Errorf("failed to check whether %s is a member of %s: %w", e.login, e.org, err)
+	}
+	if !isMember {
+		return comment(fmt.Sprintf("Only members of the %s organization can verify bugs with <code>/jira verify</code>.", e.org))
+	}
+	if !e.merged {
+		return comment("This pull request has not been merged yet. Bugs can only be verified with <code>/jira verify</code> once the pull request has been merged.")
+	}
+	verified := JiraBugState{Status: status.Verified}
+	var responses []string
+	for _, refBug := range e.bugs {
+		if !refBug.IsBug {
+			continue
 		}
-		trimmedTitle := splitTitle[0]
-		// if there is a space before the `:`,  we ignore this, as the title is improperly formatted
-		if string(trimmedTitle[len(trimmedTitle)-1]) == " " {
-			return nil, true, false
+		issue, err := getJira(jc, refBug.Key, log, comment)
+		if err != nil || issue == nil {
+			return err
 		}
-		// if there are square brackets, remove them and everything between them
-		leftBracket := strings.Index(title, "[")
-		rightBracket := strings.Index(title, "]")
-		if rightBracket-leftBracket != 0 {
-			trimmedTitle = trimmedTitle[0:leftBracket] + trimmedTitle[rightBracket+1:]
+		if issue.Fields.Status != nil && strings.EqualFold(verified.Status, issue.Fields.Status.Name) {
+			responses = append(responses, fmt.Sprintf(issueLink+" is already in the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, verified.Status))
+			continue
 		}
-	*/
-	matches := titleMatchJiraIssue.FindString(title)
-	if len(matches) == 0 {
-		return nil, true, false
-	}
-	bugs := []referencedBug{}
-	splitMatch := strings.Split(matches, ",")
-	for _, match := range splitMatch {
-		match = strings.TrimSuffix(match, ":")
-		if strings.EqualFold(match, "NO-ISSUE") || strings.EqualFold(match, "NO-JIRA") {
-			return nil, false, true
+		reachable, available, action, err := transitionBug(jc, issue, verified)
+		if err != nil {
+			log.WithError(err).Warn("Unexpected error transitioning jira issue.")
+			return comment(formatError(action, jc.JiraURL(), refBug.Key, err))
 		}
-		bugs = append(bugs, referencedBug{
-			Key:   match,
-			IsBug: strings.Contains(match, "OCPBUGS-"),
-		})
+		if !reachable {
+			responses = append(responses, fmt.Sprintf(issueLink+" could not be moved to the %s state because no transition to %s exists. Available transitions: %s.", refBug.Key, jc.JiraURL(), refBug.Key, verified.Status, verified.Status, strings.Join(available, ", ")))
+			continue
+		}
+		jiraComment := &jira.Comment{Body: fmt.Sprintf("Bug status changed to %s as %s verified the fix in merged PR https://github.com/%s/%s/pull/%d", verified.Status, e.login, e.org, e.repo, e.number), Visibility: PrivateVisibility}
+		if _, err := jc.AddComment(issue.ID, jiraComment); err != nil {
+			log.WithError(err).Warn("Unexpected error adding comment to jira issue.")
+			return comment(formatError("adding a comment about the verification", jc.JiraURL(), refBug.Key, err))
+		}
+		responses = append(responses, fmt.Sprintf(issueLink+" has been moved to the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, verified.Status))
 	}
-	return bugs, false, false
+	if len(responses) == 0 {
+		return comment("No Jira bug is referenced in the title of this pull request, so there is no bug to verify.")
+	}
+	return comment(strings.Join(responses, "\n\n"))
 }
 
-func getJira(jc jiraclient.Client, jiraKey string, log *logrus.Entry, comment func(string) error) (*jira.Issue, error) {
-	issue, err := jc.GetIssue(jiraKey)
-	if err != nil && !jiraclient.IsNotFound(err) {
-		log.WithError(err).Warn("Unexpected error searching for Jira issue.")
-		return nil, comment(formatError("searching", jc.JiraURL(), jiraKey, err))
+// handleSimulateMerge handles the pull request as if it had merged on behalf of an org member,
+// moving the referenced bugs to the state after merge if all of their linked pull requests have
+// merged. It is used to verify the configuration of a branch without merging a pull request.
+func handleSimulateMerge(e event, gc githubClient, jc jiraclient.Client, ac agileClient, options JiraBranchOptions, log *logrus.Entry, allRepos sets.String) error {
+	comment := e.comment(gc)
+	isMember, err := gc.IsMember(e.org, e.login)
+	if err != nil {
+		return fmt.Errorf("failed to check whether %s is a member of %s: %w", e.login, e.org, err)
 	}
-	if jiraclient.IsNotFound(err) || issue == nil {
-		log.Debug("No jira issue found.")
-		return nil, comment(fmt.Sprintf(`No Jira issue with key %s exists in the tracker at %s.
-Once a valid jira issue is referenced in the title of this pull request, request a refresh with <code>/jira refresh</code>.`,
-			jiraKey, jc.JiraURL()))
+	if !isMember {
+		return comment(fmt.Sprintf("Only members of the %s organization can simulate merges with <code>/jira simulate-merge</code>.", e.org))
 	}
-	return issue, nil
+	if e.merged {
+		return comment("This pull request has already merged, so there is no merge to simulate. Request a bug refresh with <code>/jira refresh</code> to handle the merge again.")
+	}
+	if options.StateAfterMerge == nil {
+		return comment(fmt.Sprintf("No state after merge is configured for the %s branch, so a merge of this pull request would not move any bug.", e.baseRef))
+	}
+	if e.missing {
+		return comment("No Jira bug is referenced in the title of this pull request, so there is no bug to simulate the merge for.")
+	}
+	// the pull request itself is considered merged when checking the pull requests linked to the bugs
+	e.merged = true
+	return handleMerge(e, gc, jc, ac, options, log, allRepos)
 }
 
-func formatError(action, endpoint, bugKey string, err error) string {
-	knownErrors := map[string]string{
-		// TODO: Most of this code is copied from the bugzilla client. If Jira rate limits us the same way, this could come in handy. We will keep this for now in case it is needed
-		//"There was an error reported for a GitHub REST call": "The Bugzilla server failed to load data from GitHub when creating the bug. This is usually caused by rate-limiting, please try again later.",
+// handleAddLink links the pull request on the bugs referenced in its title using the external bug
+// tracker on behalf of an org member, regardless of the AddExternalLink option of the branch.
+func handleAddLink(e event, gc githubClient, jc jiraclient.Client, log *logrus.Entry) error {
+	comment := e.comment(gc)
+	isMember, err := gc.IsMember(e.org, e.login)
+	if err != nil {
+		return fmt.Errorf("failed to check whether %s is a member of %s: %w", e.login, e.org, err)
 	}
-	var applicable []string
-	for key, value := range knownErrors {
-		if strings.Contains(err.Error(), key) {
-			applicable = append(applicable, value)
-		}
+	if !isMember {
+		return comment(fmt.Sprintf("Only members of the %s organization can link bugs with <code>/jira add-link</code>.", e.org))
 	}
-	digest := "No known errors were detected, please see the full error message for details."
-	if len(applicable) > 0 {
-		digest = "We were able to detect the following conditions from the error:\n\n"
-		for _, item := range applicable {
-			digest = fmt.Sprintf("%s- %s\n", digest, item)
+	var responses []string
+	for _, refBug := range e.bugs {
+		issue, err := getJira(jc, refBug.Key, log, comment)
+		if err != nil || issue == nil {
+			return err
+		}
+		unlock := remoteLinkLocks.lock(issue.Key)
+		changed, err := upsertGitHubLinkToIssue(log, issue.ID, jc, e)
+		unlock()
+		if err != nil {
+			log.WithError(err).Warn("Unexpected error adding external tracker bug to Jira bug.")
+			return comment(formatError("adding this pull request to the external tracker bugs", jc.JiraURL(), refBug.Key, err))
+		}
+		if changed {
+			responses = append(responses, fmt.Sprintf(issueLink+" has been updated to refer to the pull request using the external bug tracker.", refBug.Key, jc.JiraURL(), refBug.Key))
+		} else {
+			responses = append(responses, fmt.Sprintf(issueLink+" already refers to the pull request using the external bug tracker.", refBug.Key, jc.JiraURL(), refBug.Key))
 		}
 	}
-	return fmt.Sprintf(`An error was encountered %s for bug %s on the Jira server at %s. %s
-
-<details><summary>Full error message.</summary>
-
-<code>
-%v
-</code>
-
-</details>
+	if len(responses) == 0 {
+		return comment("No Jira issue is referenced in the title of this pull request, so there is no issue to link.")
+	}
+	return comment(strings.Join(responses, "\n\n"))
+}
 
-Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.`,
-		action, bugKey, endpoint, digest, err)
+// handleFixVersion sets the target version of the referenced bugs to the target version
+// configured for the branch. It returns whether the event was handled, which is the case
+// when the target version could not be set and the problem was commented instead.
+func handleFixVersion(e event, gc githubClient, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry) (bool, error) {
+	comment := e.comment(gc)
+	versions := acceptableTargetVersions(options)
+	if len(versions) == 0 {
+		return true, comment(fmt.Sprintf("No target version is configured for the %s branch, so the target version of the referenced bugs cannot be derived from it.", e.baseRef))
+	}
+	targetVersion := versions[0]
+	var bugs []string
+	for _, refBug := range e.bugs {
+		if !refBug.IsBug {
+			continue
+		}
+		bugs = append(bugs, refBug.Key)
+		issue, err := getJira(jc, refBug.Key, log, comment)
+		if err != nil || issue == nil {
+			return true, err
+		}
+		current, err := helpers.GetIssueTargetVersion(issue, options.customFields())
+		if err != nil {
+			return true, comment(formatError("getting the target version of the bug", jc.JiraURL(), refBug.Key, err))
+		}
+		if len(current) == 1 && current[0].Name == targetVersion {
+			continue
+		}
+		update := jira.Issue{
+			Key: issue.Key,
+			Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					options.customFields().TargetVersion: []*jira.Version{{Name: targetVersion}},
+				},
+			},
+		}
+		if _, err := jc.UpdateIssue(&update); err != nil {
+			log.WithError(err).Warn("Unexpected error updating the target version of the Jira bug.")
+			return true, comment(formatError(fmt.Sprintf("setting the target version of the bug to %s", targetVersion), jc.JiraURL(), refBug.Key, err))
+		}
+	}
+	if len(bugs) == 0 {
+		return true, comment("No Jira bug is referenced in the title of this pull request, so there is no target version to set.")
+	}
+	return false, nil
 }
 
 var PrivateVisibility = jira.CommentVisibility{Type: "group", Value: "Red Hat Employee"}
 
-func handleClose(e event, gc githubClient, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry) error {
+func handleClose(e event, gc githubClient, jc jiraclient.Client, options JiraBranchOptions, log *logrus.Entry, allRepos sets.String) error {
 	comment := e.comment(gc)
 	if e.missing {
 		return nil
@@ -1903,7 +4906,13 @@ func handleClose(e event, gc githubClient, jc jiraclient.Client, options JiraBra
 				msg += formatError("removing this pull request from the external tracker bugs", jc.JiraURL(), refBug.Key, err) + "\n\n"
 				continue
 			}
-			if options.StateAfterClose != nil || options.PreMergeStateAfterClose != nil {
+			if changed && options.CommentOnLinkRemoval != nil && *options.CommentOnLinkRemoval {
+				jiraComment := &jira.Comment{Body: fmt.Sprintf("Link to PR https://github.com/%s/%s/pull/%d removed as the PR has been closed without merging", e.org, e.repo, e.number), Visibility: PrivateVisibility}
+				if _, err := jc.AddComment(refBug.Key, jiraComment); err != nil {
+					response += "\nWarning: Failed to comment on Jira bug about the removed link."
+				}
+			}
+			if options.StateAfterCloseUnmerged == nil && (options.StateAfterClose != nil || options.PreMergeStateAfterClose != nil) {
 				issue, err := jc.GetIssue(refBug.Key)
 				if err != nil {
 					log.WithError(err).Warn("Unexpected error getting Jira issue.")
@@ -1965,7 +4974,7 @@ func handleClose(e event, gc githubClient, jc jiraclient.Client, options JiraBra
 							}
 						}
 						response += fmt.Sprintf(" All external bug links have been closed. The bug has been moved to the %s state.", PrettyStatus(updatedState.Status, updatedState.Resolution))
-						jiraComment := &jira.Comment{Body: fmt.Sprintf("Bug status changed to %s as previous linked PR https://github.com/%s/%s/pull/%d has been closed", options.StateAfterClose.Status, e.org, e.repo, e.number), Visibility: PrivateVisibility}
+						jiraComment := &jira.Comment{Body: fmt.Sprintf("Bug status changed to %s as previous linked PR https://github.com/%s/%s/pull/%d has been closed", PrettyStatus(updatedState.Status, updatedState.Resolution), e.org, e.repo, e.number), Visibility: PrivateVisibility}
 						if _, err := jc.AddComment(bug.ID, jiraComment); err != nil {
 							response += "\nWarning: Failed to comment on Jira bug with reason for changed state."
 						}
@@ -1976,6 +4985,11 @@ func handleClose(e event, gc githubClient, jc jiraclient.Client, options JiraBra
 				msg += response + "\n\n"
 			}
 		}
+		if options.StateAfterCloseUnmerged != nil {
+			if response := handleAbandonedBug(e, gc, jc, refBug, options, log, allRepos); response != "" {
+				msg += response + "\n\n"
+			}
+		}
 	}
 	if len(msg) != 0 {
 		msg = strings.TrimSuffix(msg, "\n\n")
@@ -1984,19 +4998,71 @@ func handleClose(e event, gc githubClient, jc jiraclient.Client, options JiraBra
 	return nil
 }
 
-func isBugAllowed(issue *jira.Issue, allowedSecurityLevel []string) (bool, error) {
+// handleAbandonedBug moves the bug to the StateAfterCloseUnmerged unless the closed pull request
+// is superseded by another pull request linked to the bug that is open or merged. It returns the
+// response to comment, which is empty if the bug is already in the state.
+func handleAbandonedBug(e event, gc githubClient, jc jiraclient.Client, refBug referencedBug, options JiraBranchOptions, log *logrus.Entry, allRepos sets.String) string {
+	issue, err := jc.GetIssue(refBug.Key)
+	if err != nil {
+		log.WithError(err).Warn("Unexpected error getting Jira issue.")
+		return formatError("getting issue", jc.JiraURL(), refBug.Key, err)
+	}
+	if bugMatchesStates(issue, []JiraBugState{*options.StateAfterCloseUnmerged}) {
+		return ""
+	}
+	links, err := jc.GetRemoteLinks(issue.ID)
+	if err != nil {
+		log.WithError(err).Warn("Unexpected error getting remote links for Jira issue.")
+		return formatError("getting remote links", jc.JiraURL(), refBug.Key, err)
+	}
+	for _, link := range links {
+		if link.Object == nil {
+			continue
+		}
+		item, isPR, err := prPartsFromURL(link.Object.URL)
+		if !isPR || err != nil {
+			continue
+		}
+		merged, state, managed, err := pullRequestState(e, gc, item, allRepos)
+		if err != nil {
+			log.WithError(err).Warn("Unexpected error checking merge state of related pull request.")
+			return formatError(fmt.Sprintf("checking the state of a related pull request at https://github.com/%s/%s/pull/%d", item.Org, item.Repo, item.Num), jc.JiraURL(), refBug.Key, err)
+		}
+		if managed && (merged || state == github.PullRequestStateOpen) {
+			return fmt.Sprintf("This pull request references "+issueLink+" and was closed without merging, but it is superseded by [%s/%s#%d](https://github.com/%s/%s/pull/%d), so the bug has not been moved to the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, item.Org, item.Repo, item.Num, item.Org, item.Repo, item.Num, options.StateAfterCloseUnmerged)
+		}
+	}
+	reachable, available, action, err := transitionBug(jc, issue, *options.StateAfterCloseUnmerged)
+	if err != nil {
+		log.WithError(err).Warn("Unexpected error transitioning jira issue.")
+		return formatError(action, jc.JiraURL(), refBug.Key, err)
+	}
+	if !reachable {
+		return fmt.Sprintf("This pull request references "+issueLink+" and was closed without merging, but the bug could not be moved to the %s state because no transition to %s exists. Available transitions: %s.", refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterCloseUnmerged, options.StateAfterCloseUnmerged.Status, strings.Join(available, ", "))
+	}
+	response := fmt.Sprintf("This pull request references "+issueLink+" and was closed without merging. The bug has been moved to the %s state.", refBug.Key, jc.JiraURL(), refBug.Key, options.StateAfterCloseUnmerged)
+	jiraComment := &jira.Comment{Body: fmt.Sprintf("Bug status changed to %s as linked PR https://github.com/%s/%s/pull/%d has been closed without merging", options.StateAfterCloseUnmerged, e.org, e.repo, e.number), Visibility: PrivateVisibility}
+	if _, err := jc.AddComment(issue.ID, jiraComment); err != nil {
+		response += "\nWarning: Failed to comment on Jira bug with reason for changed state."
+	}
+	return response
+}
+
+func isBugAllowed(issue *jira.Issue, allowedSecurityLevel, deniedSecurityLevel []string) (bool, error) {
+	// denied levels take precedence over allowed ones
+	denied, err := isBugDenied(issue, deniedSecurityLevel)
+	if err != nil || denied {
+		return false, err
+	}
+
 	// if no allowed visibilities are listed, assume all visibilities are allowed
 	if len(allowedSecurityLevel) == 0 {
 		return true, nil
 	}
 
-	level, err := helpers.GetIssueSecurityLevel(issue)
+	level, err := getSecurityLevel(issue)
 	if err != nil {
-		return false, fmt.Errorf("failed to get security level: %w", err)
-	}
-	if level == nil {
-		// default security level is empty; make a temporary "default" security level for this check
-		level = &helpers.SecurityLevel{Name: "default"}
+		return false, err
 	}
 	found := false
 	for _, allowed := range allowedSecurityLevel {
@@ -2008,11 +5074,59 @@ func isBugAllowed(issue *jira.Issue, allowedSecurityLevel []string) (bool, error
 	return found, nil
 }
 
+// isBugDenied determines whether the issue is in one of the denied security levels
+func isBugDenied(issue *jira.Issue, deniedSecurityLevel []string) (bool, error) {
+	if len(deniedSecurityLevel) == 0 {
+		return false, nil
+	}
+
+	level, err := getSecurityLevel(issue)
+	if err != nil {
+		return false, err
+	}
+	for _, denied := range deniedSecurityLevel {
+		if level.Name == denied {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func getSecurityLevel(issue *jira.Issue) (*helpers.SecurityLevel, error) {
+	level, err := helpers.GetIssueSecurityLevel(issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security level: %w", err)
+	}
+	if level == nil {
+		// default security level is empty; make a temporary "default" security level for this check
+		level = &helpers.SecurityLevel{Name: "default"}
+	}
+	return level, nil
+}
+
+// isStatusReachable determines whether the issue can be transitioned to the provided status. If it cannot,
+// the names of the transitions that are available for the issue are returned.
+func isStatusReachable(jc jiraclient.Client, issueID, statusName string) (bool, []string, error) {
+	transitions, err := jc.GetTransitions(issueID)
+	if err != nil {
+		return false, nil, err
+	}
+	var available []string
+	for _, transition := range transitions {
+		// this matches the transition lookup done by the jira client's UpdateStatus
+		if strings.EqualFold(transition.Name, statusName) {
+			return true, nil, nil
+		}
+		available = append(available, transition.Name)
+	}
+	return false, available, nil
+}
+
 func checkTargetVersion(options JiraBranchOptions) bool {
 	switch {
 	case options.SkipTargetVersionCheck != nil && *options.SkipTargetVersionCheck:
 		return false
-	case options.TargetVersion != nil:
+	case options.TargetVersion != nil, options.TargetVersions != nil && len(*options.TargetVersions) > 0:
 		return true
 	default:
 		return false
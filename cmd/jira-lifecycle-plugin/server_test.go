@@ -1,19 +1,28 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/andygrunwald/go-jira"
 	"github.com/google/go-cmp/cmp"
 	"github.com/openshift-eng/jira-lifecycle-plugin/pkg/helpers"
 	"github.com/openshift-eng/jira-lifecycle-plugin/pkg/labels"
 	"github.com/openshift-eng/jira-lifecycle-plugin/pkg/status"
+	githubql "github.com/shurcooL/githubv4"
 	"github.com/sirupsen/logrus"
 	"github.com/trivago/tgo/tcontainer"
+	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/yaml"
 
@@ -32,16 +41,96 @@ type fakeGHClient struct {
 	*fakegithub.FakeClient
 }
 
+func (f fakeGHClient) DismissReview(org, repo string, number int, review github.Review, message string) error {
+	for i := range f.Reviews[number] {
+		if f.Reviews[number][i].ID == review.ID {
+			f.Reviews[number][i].State = github.ReviewStateDismissed
+		}
+	}
+	return nil
+}
+
 func (f fakeGHClient) QueryWithGitHubAppsSupport(ctx context.Context, q interface{}, vars map[string]interface{}, org string) error {
 	return nil
 }
 
+func (f fakeGHClient) CreateCheckRun(org, repo string, checkRun github.CheckRun) error {
+	return nil
+}
+
+// checkRunRecordingClient records the check-runs created through it, as the test-infra
+// fake github client does not implement CreateCheckRun
+type checkRunRecordingClient struct {
+	fakeGHClient
+	checkRuns []github.CheckRun
+}
+
+func (c *checkRunRecordingClient) CreateCheckRun(org, repo string, checkRun github.CheckRun) error {
+	c.checkRuns = append(c.checkRuns, checkRun)
+	return nil
+}
+
+// fakeAgileClient answers board membership from a map of board IDs to the issue keys on them
+// and the versions of projects from a map of project keys to their versions. JQL searches are
+// answered from a map of filters to the keys of the issues matching them and board columns from
+// a map of board IDs to the columns that status IDs are mapped to
+type fakeAgileClient struct {
+	boards   map[int][]string
+	versions map[string][]string
+	filters  map[string][]string
+	columns  map[int]map[string]string
+}
+
+func (f *fakeAgileClient) IsIssueOnBoard(boardID int, issueKey string) (bool, error) {
+	for _, key := range f.boards[boardID] {
+		if key == issueKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeAgileClient) ProjectVersions(projectKey string) ([]string, error) {
+	versions, ok := f.versions[projectKey]
+	if !ok {
+		return nil, fmt.Errorf("project %s not found", projectKey)
+	}
+	return versions, nil
+}
+
+func (f *fakeAgileClient) IssueMatchesJQL(issueKey, jql string) (bool, error) {
+	keys, ok := f.filters[jql]
+	if !ok {
+		return false, fmt.Errorf("invalid filter %q", jql)
+	}
+	return sets.NewString(keys...).Has(issueKey), nil
+}
+
+func (f *fakeAgileClient) BoardColumn(boardID int, statusID string) (string, error) {
+	columns, ok := f.columns[boardID]
+	if !ok {
+		return "", fmt.Errorf("board %d not found", boardID)
+	}
+	return columns[statusID], nil
+}
+
 func TestHandle(t *testing.T) {
 	t.Parallel()
 	yes := true
 	open := true
 	v1Str := "v1"
 	v2Str := "v2"
+	one := 1
+	board := 42
+	filter := "labels = triaged"
+	backportProject := "BACKPORTS"
+	securityTeam := "@org/security-team"
+	filterMessage := "expected the bug to be triaged, but it is not"
+	retitleCommand := "/bot retitle"
+	transitionComment := "{{.Key}} moved to {{.Status}} as it is fixed by {{.PullRequestURL}}"
+	minDescriptionLength := 20
+	no := false
+	backportReadyField := "customfield_12345"
 	v1 := []*jira.Version{{Name: v1Str}}
 	v2 := []*jira.Version{{Name: v2Str}}
 	v3 := []*jira.Version{{Name: "v3"}}
@@ -100,6 +189,7 @@ func TestHandle(t *testing.T) {
 			},
 		},
 	}
+	severityLabelPrefix := "sev/"
 	severityCritical := struct {
 		Value string
 	}{Value: "<img alt=\"\" src=\"/images/icons/priorities/critical.svg\" width=\"16\" height=\"16\"> Critical"}
@@ -187,11 +277,22 @@ func TestHandle(t *testing.T) {
 		name                       string
 		labels                     []string
 		humanLabelled              bool
+		labelChangedBy             string
 		missing                    bool
 		merged                     bool
 		closed                     bool
 		opened                     bool
 		refresh                    bool
+		retry                      bool
+		verify                     bool
+		simulateMerge              bool
+		exemptBot                  bool
+		relabel                    bool
+		validationMarker           bool
+		orgMembers                 []string
+		trackedProjects            []string
+		boards                     map[int][]string
+		boardColumns               map[int]map[string]string
 		cherrypick                 bool
 		cherryPickFromPRNum        int
 		body                       string
@@ -203,8 +304,11 @@ func TestHandle(t *testing.T) {
 		prComments                 map[int][]github.IssueComment
 		issues                     []jira.Issue
 		issueGetErrors             map[string]error
+		projectVersions            map[string][]string
+		jqlFilters                 map[string][]string
 		issueCreateErrors          map[string]error
 		issueUpdateErrors          map[string]error
+		transitions                []jira.Transition
 		options                    JiraBranchOptions
 		expectedLabels             []string
 		expectedComment            string
@@ -267,6 +371,26 @@ In response to [this](https://github.com/org/repo/pull/1):
 >This PR fixes OCPBUGS-123
 
 
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "deleted bug on a previously validated PR removes the valid labels and leaves a comment",
+			issueGetErrors: map[string]error{"OCPBUGS-123": jiraclient.NewNotFoundError(errors.New("issue does not exist"))},
+			refresh:        true,
+			body:           "/jira refresh",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
+			expectedLabels: []string{labels.SeverityModerate},
+			expectedComment: `org/repo#1:@user: The Jira issue OCPBUGS-123 referenced by this pull request no longer exists in the tracker at https://my-jira.com; it may have been deleted. The following labels have been removed: jira/valid-bug, jira/valid-reference.
+Once a valid jira issue is referenced in the title of this pull request, request a refresh with <code>/jira refresh</code>.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
 		},
@@ -312,6 +436,26 @@ In response to [this](https://github.com/org/repo/pull/1):
 >This PR fixes OCPBUGS-123
 
 
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "valid bug replaces the severity label with the configured prefix",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			options:        JiraBranchOptions{SeverityLabelPrefix: &severityLabelPrefix},
+			labels:         []string{"sev/low"},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, "sev/critical"},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
 		},
@@ -333,6 +477,251 @@ In response to [this](https://github.com/org/repo/pull/1):
 >This PR fixes OCPBUGS-123
 
 
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:             "invalid bug with the validation marker enabled appends the validation results to the comment",
+			issues:           []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityImportant}}}},
+			options:          JiraBranchOptions{IsOpen: &open},
+			validationMarker: true,
+			labels:           []string{labels.JiraValidBug},
+			expectedLabels:   []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.SeverityImportant},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:
+ - expected the bug to be open, but it isn't
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+<!-- jira-lifecycle: {"key":"OCPBUGS-123","valid":false,"reasons":["expected the bug to be open, but it isn't"]} -->
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "valid bug with plain text severity adds severity label",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: "Critical"}}}},
+			options:        JiraBranchOptions{},
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "valid bug with hidden empty validations omits the validations block",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			options:        JiraBranchOptions{HideEmptyValidations: &yes},
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "bug on the required board is valid",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			options:        JiraBranchOptions{RequiredBoardID: &board},
+			boards:         map[int][]string{board: {"OCPBUGS-123"}},
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>1 validation was run on this bug</summary>
+
+* bug is on board 42</details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "bug not on the required board is invalid",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			options:        JiraBranchOptions{RequiredBoardID: &board},
+			boards:         map[int][]string{board: {"OCPBUGS-456"}},
+			labels:         []string{labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:
+ - expected the bug to be on board 42, but it is not
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name: "bug fields are read from the configured custom field IDs",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{
+				helpers.SeverityField:      severityLow,
+				helpers.TargetVersionField: &v2,
+				"customfield_98":           severityCritical,
+				"customfield_99":           &v1,
+			}}}},
+			options:        JiraBranchOptions{TargetVersion: &v1Str, CustomFields: &helpers.FieldMap{Severity: "customfield_98", TargetVersion: "customfield_99"}},
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>1 validation was run on this bug</summary>
+
+* bug target version (v1) matches configured target version for branch (v1)</details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "bug matching the validation filter is valid",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			options:        JiraBranchOptions{ValidationJQL: &filter},
+			jqlFilters:     map[string][]string{filter: {"OCPBUGS-123"}},
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>1 validation was run on this bug</summary>
+
+* bug matches the validation filter</details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "bug not matching the validation filter is invalid",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			options:        JiraBranchOptions{ValidationJQL: &filter},
+			jqlFilters:     map[string][]string{filter: {"OCPBUGS-456"}},
+			labels:         []string{labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:
+ - expected the bug to match the validation filter, but it does not
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "bug not matching the validation filter is invalid with the configured message",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			options:        JiraBranchOptions{ValidationJQL: &filter, ValidationJQLMessage: &filterMessage},
+			jqlFilters:     map[string][]string{filter: {}},
+			labels:         []string{labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:
+ - expected the bug to be triaged, but it is not
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:            "bug targeting a known version of its project is valid",
+			issues:          []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.TargetVersionField: &v1}}}},
+			options:         JiraBranchOptions{RequireKnownTargetVersion: &yes},
+			projectVersions: map[string][]string{"OCPBUGS": {v1Str, v2Str}},
+			labels:          []string{labels.JiraInvalidBug},
+			expectedLabels:  []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>1 validation was run on this bug</summary>
+
+* target version v1 is a known version for project OCPBUGS</details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:            "bug targeting an unknown version of its project is invalid",
+			issues:          []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.TargetVersionField: &v1}}}},
+			options:         JiraBranchOptions{RequireKnownTargetVersion: &yes},
+			projectVersions: map[string][]string{"OCPBUGS": {v2Str}},
+			labels:          []string{labels.JiraValidBug},
+			expectedLabels:  []string{labels.JiraValidRef, labels.JiraInvalidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:
+ - target version v1 is not a known version for project OCPBUGS
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
 		},
@@ -409,7 +798,7 @@ Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jir
 
 This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is valid.
 
-<details><summary>1 validation(s) were run on this bug</summary>
+<details><summary>1 validation was run on this bug</summary>
 
 * bug is open, matching expected state (open)</details>
 
@@ -435,7 +824,7 @@ Instructions for interacting with me using PR comments are available [here](http
 			expectedLabels:        []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.SeverityCritical},
 			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
 
-<details><summary>1 validation(s) were run on this bug</summary>
+<details><summary>1 validation was run on this bug</summary>
 
 * bug is open, matching expected state (open)</details>
 
@@ -466,13 +855,13 @@ Instructions for interacting with me using PR comments are available [here](http
 			expectedLabels:        []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
 			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
 
-<details><summary>1 validation(s) were run on this bug</summary>
+<details><summary>1 validation was run on this bug</summary>
 
 * bug is open, matching expected state (open)</details>
 
 This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is valid.
 
-<details><summary>1 validation(s) were run on this bug</summary>
+<details><summary>1 validation was run on this bug</summary>
 
 * bug is open, matching expected state (open)</details>
 
@@ -498,13 +887,13 @@ Instructions for interacting with me using PR comments are available [here](http
 			expectedLabels:        []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
 			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
 
-<details><summary>1 validation(s) were run on this bug</summary>
+<details><summary>1 validation was run on this bug</summary>
 
 * bug is open, matching expected state (open)</details>
 
 This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is valid.
 
-<details><summary>1 validation(s) were run on this bug</summary>
+<details><summary>1 validation was run on this bug</summary>
 
 * bug is open, matching expected state (open)</details>
 
@@ -543,11 +932,69 @@ Instructions for interacting with me using PR comments are available [here](http
 </details>`,
 		},
 		{
-			name:    "no bug removes all labels and comments",
-			missing: true,
-			labels:  []string{labels.JiraValidBug, labels.JiraInvalidBug},
-			expectedComment: `org/repo#1:@user: No Jira issue is referenced in the title of this pull request.
-To reference a jira issue, add 'XYZ-NNN:' to the title of this pull request and request another refresh with <code>/jira refresh</code>.
+			name:           "fix-labels removes contradictory and stale labels even if they were added manually",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityImportant}}}},
+			options:        JiraBranchOptions{IsOpen: &open},
+			humanLabelled:  true,
+			labels:         []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.JiraValidBug, labels.SeverityCritical, labels.SeverityImportant},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.SeverityImportant},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira fix-labels", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", fixLabels: true,
+			},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:
+ - expected the bug to be open, but it isn't
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira fix-labels
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "human adding the valid bug label to an invalid bug keeps it",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityImportant}}}},
+			options:        JiraBranchOptions{IsOpen: &open},
+			humanLabelled:  true,
+			labelChangedBy: "someone",
+			labels:         []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.JiraValidBug, labels.SeverityImportant},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityImportant},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:
+ - expected the bug to be open, but it isn't
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+Retaining the jira/valid-bug label as it was manually added.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "label changes by the bot itself are ignored",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityImportant}}}},
+			options:        JiraBranchOptions{IsOpen: &open},
+			labelChangedBy: "k8s-ci-robot",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityImportant},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityImportant},
+		},
+		{
+			name:    "no bug removes all labels and comments",
+			missing: true,
+			labels:  []string{labels.JiraValidBug, labels.JiraInvalidBug},
+			expectedComment: `org/repo#1:@user: No Jira issue is referenced in the title of this pull request.
+To reference a jira issue, add 'XYZ-NNN:' to the title of this pull request and request another refresh with <code>/jira refresh</code>.
 
 <details>
 
@@ -630,12 +1077,23 @@ Instructions for interacting with me using PR comments are available [here](http
 			}},
 		},
 		{
-			name:                  "valid jira removes invalid label, adds valid label, comments",
-			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
-			issues:                []jira.Issue{{ID: "1", Key: "JIRA-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate}}}},
-			labels:                []string{labels.JiraInvalidBug},
-			expectedLabels:        []string{labels.JiraValidRef},
-			expectedComment: `org/repo#1:@user: This pull request references JIRA-123 which is a valid jira issue.
+			name:   "valid bug with status update and a transition comment template comments on the bug in Jira",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate}}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			options:        JiraBranchOptions{StateAfterValidation: &updated, ValidationTransitionComment: &transitionComment},
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid. The bug has been moved to the UPDATED state.
+
+<details><summary>No validations were run on this bug</summary></details>
 
 <details>
 
@@ -646,45 +1104,53 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:   &jira.Status{Name: "UPDATED"},
+				Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body:       "OCPBUGS-123 moved to UPDATED as it is fixed by https://github.com/org/repo/pull/1",
+					Visibility: PrivateVisibility,
+				}}},
+			}},
 		},
 		{
-			name:                  "valid jira with incorrect version removes invalid label, adds valid label,comments",
-			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
-			issues:                []jira.Issue{{ID: "1", Key: "JIRA-123", Fields: &jira.IssueFields{Type: jira.IssueType{Name: "Issue"}, Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate}}}},
-			labels:                []string{labels.JiraInvalidBug},
-			expectedLabels:        []string{labels.JiraValidRef},
-			options:               JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: This pull request references JIRA-123 which is a valid jira issue.
-
-Warning: The referenced jira issue has an invalid target version for the target branch this PR targets: expected the issue to target the "v1" version, but no target version was set.
+			name:   "listing PRs comments with the merge status of all linked PRs",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {
+				{ID: 1, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/1", Title: "org/repo#1: OCPBUGS-123: fixed it!"}},
+				{ID: 2, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/2", Title: "org/repo#2: OCPBUGS-123: fixed it!"}},
+				{ID: 3, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/3", Title: "org/repo#3: OCPBUGS-123: fixed it!"}},
+				{ID: 4, Object: &jira.RemoteLinkObject{URL: "https://github.com/other/repo/pull/4", Title: "other/repo#4: OCPBUGS-123: fixed it!"}},
+				{ID: 5, Object: &jira.RemoteLinkObject{URL: "https://errata.example.com/advisory/1", Title: "advisory"}},
+			}},
+			prs: []github.PullRequest{{Number: 2, Merged: true, State: "closed"}, {Number: 3, State: "closed"}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira prs", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", listPRs: true,
+			},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is linked to the following pull requests:
+ * [org/repo#1](https://github.com/org/repo/pull/1) is open
+ * [org/repo#2](https://github.com/org/repo/pull/2) is merged
+ * [org/repo#3](https://github.com/org/repo/pull/3) is closed without merging
+ * [other/repo#4](https://github.com/other/repo/pull/4) is in a repository that is not managed by this plugin
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira prs
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
 		},
 		{
-			name: "valid bug and valid jira ref adds valid/severity labels and comments",
-			issues: []jira.Issue{
-				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Post}, Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}},
-				{ID: "2", Key: "JIRA-123", Fields: &jira.IssueFields{}},
+			name:   "pull request converted to draft notes that the bug will not move until it is ready for review",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", draft: true, convertedToDraft: true,
 			},
-			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "JIRA-123", IsBug: false}},
-			options:               JiraBranchOptions{IsOpen: &open},
-			labels:                []string{},
-			expectedLabels:        []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
-
-<details><summary>1 validation(s) were run on this bug</summary>
-
-* bug is open, matching expected state (open)</details>
-
-This pull request references JIRA-123 which is a valid jira issue.
+			options: JiraBranchOptions{StateAfterValidation: &updated},
+			expectedComment: `org/repo#1:@user: This pull request has been converted to a draft, so OCPBUGS-123 will not be moved to a new state until it is marked as ready for review again.
 
 <details>
 
@@ -695,14 +1161,19 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
 		},
 		{
-			name:                  "invalid jira with status update removes valid label, comments",
-			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
-			labels:                []string{labels.JiraValidRef},
-			expectedLabels:        []string{},
-			expectedComment: `org/repo#1:@user: No Jira issue with key JIRA-123 exists in the tracker at https://my-jira.com.
-Once a valid jira issue is referenced in the title of this pull request, request a refresh with <code>/jira refresh</code>.
+			name:   "valid bug of a draft pull request is not moved to the state after validation",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", draft: true,
+			},
+			options:        JiraBranchOptions{StateAfterValidation: &updated},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
 
 <details>
 
@@ -713,94 +1184,143 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
 		},
 		{
-			name:           "valid no-jira removes invalid label, adds valid label, comments",
-			noJira:         true,
-			labels:         []string{labels.JiraInvalidBug},
-			expectedLabels: []string{labels.JiraValidRef},
-			expectedComment: `org/repo#1:@user: This pull request explicitly references no jira issue.
+			name:   "listing PRs for a bug without linked PRs comments",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira prs", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", listPRs: true,
+			},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is not linked to any pull requests.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira prs
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
 		},
 		{
-			name:           "valid no-jira with no changes comments if there is no previous matching comment",
-			prComments:     map[int][]github.IssueComment{1: {{Body: "Hello", User: github.User{Login: "alex"}}, {Body: "Hello again", User: github.User{Login: fakegithub.Bot}}}},
-			noJira:         true,
-			labels:         []string{labels.JiraValidRef},
-			expectedLabels: []string{labels.JiraValidRef},
-			expectedComment: `org/repo#1:@user: This pull request explicitly references no jira issue.
+			name: "listing dependents comments with the state and target version of each dependent",
+			issues: []jira.Issue{
+				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+					IssueLinks: []*jira.IssueLink{
+						{Type: jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}, InwardIssue: &jira.Issue{Key: "OCPBUGS-124"}},
+						{Type: jira.IssueLinkType{Name: "Depend", Inward: "is depended on by", Outward: "depends on"}, OutwardIssue: &jira.Issue{Key: "OCPBUGS-125"}},
+						{Type: jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}, OutwardIssue: &jira.Issue{Key: "OCPBUGS-126"}},
+					},
+				}},
+				{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+					Status:   &jira.Status{Name: "MODIFIED"},
+					Unknowns: tcontainer.MarshalMap{helpers.TargetVersionField: &[]*jira.Version{{Name: v1Str}}},
+				}},
+				{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{Status: &jira.Status{Name: "CLOSED"}, Resolution: &jira.Resolution{Name: "ERRATA"}}},
+				{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
+			},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira deps", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", deps: true,
+			},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) depends on the following bugs:
+ * [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) is in the MODIFIED state, with target version v1
+ * [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125) is in the CLOSED (ERRATA) state, with no target version
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira deps
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
 		},
 		{
-			name: "valid no-jira with no changes comments if there if latest bot comment does not match",
-			prComments: map[int][]github.IssueComment{1: {{Body: "Hello", User: github.User{Login: "alex"}}, {Body: `org/repo#1:@user: This pull request explicitly references no jira issue.
+			name:           "verify on merged PR by org member moves the bug to VERIFIED and comments on the bug",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}}},
+			verify:         true,
+			merged:         true,
+			orgMembers:     []string{"user"},
+			body:           "/jira verify",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the VERIFIED state.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira verify
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
-</details>`, User: github.User{Login: fakegithub.Bot}}, {Body: "different comment", User: github.User{Login: fakegithub.Bot}}}},
-			noJira:         true,
-			labels:         []string{labels.JiraValidRef},
-			expectedLabels: []string{labels.JiraValidRef},
-			expectedComment: `org/repo#1:@user: This pull request explicitly references no jira issue.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "VERIFIED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body:       "Bug status changed to VERIFIED as user verified the fix in merged PR https://github.com/org/repo/pull/1",
+					Visibility: PrivateVisibility,
+				}}},
+			}},
+		},
+		{
+			name:       "add-link by org member links the PR on the bug even without external links configured",
+			issues:     []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			orgMembers: []string{"user"},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira add-link", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", addLink: true,
+			},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been updated to refer to the pull request using the external bug tracker.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira add-link
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
+			expectedNewRemoteLinks: []jira.RemoteLink{{Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			},
+			}},
 		},
 		{
-			name: "valid no-jira with no changes does not comments if latest bot comment matches",
-			prComments: map[int][]github.IssueComment{1: {{Body: "Hello", User: github.User{Login: "alex"}}, {Body: `org/repo#1:@user: This pull request explicitly references no jira issue.
+			name:   "add-link by a user who is not an org member is rejected",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira add-link", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", addLink: true,
+			},
+			expectedComment: `org/repo#1:@user: Only members of the org organization can link bugs with <code>/jira add-link</code>.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira add-link
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
-</details>`, User: github.User{Login: fakegithub.Bot}}}},
-			noJira:         true,
-			labels:         []string{labels.JiraValidRef},
-			expectedLabels: []string{labels.JiraValidRef},
+</details>`,
 		},
 		{
-			name:           "valid bug with status update removes invalid label, adds valid label, comments and updates status with resolution",
-			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityLow}}}},
-			options:        JiraBranchOptions{StateAfterValidation: &JiraBugState{Status: "CLOSED", Resolution: "VALIDATED"}}, // no requirements --> always valid
-			labels:         []string{labels.JiraInvalidBug},
-			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityLow},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid. The bug has been moved to the CLOSED (VALIDATED) state.
+			name:           "relabel replaces stale labels without moving the bug",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}, Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate}}}},
+			relabel:        true,
+			body:           "/jira relabel",
+			options:        JiraBranchOptions{StateAfterValidation: &updated},
+			labels:         []string{labels.JiraValidRef, labels.JiraInvalidBug, labels.SeverityCritical},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
 
 <details><summary>No validations were run on this bug</summary></details>
 
@@ -808,297 +1328,203 @@ Instructions for interacting with me using PR comments are available [here](http
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira relabel
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
 			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{
-					Name: "CLOSED",
-				},
-				Resolution: &jira.Resolution{
-					Name: "VALIDATED",
-				},
-				// due to the way `Unknowns` works, this has to be provided as a map[string]interface{}
-				Unknowns: tcontainer.MarshalMap{helpers.SeverityField: map[string]interface{}{"Value": string(`<img alt="" src="/images/icons/priorities/low.svg" width="16" height="16"> Low`)}},
-			},
-			},
+				Status:   &jira.Status{Name: "NEW"},
+				Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate},
+			}},
 		},
 		{
-			name:           "valid bug with status update removes invalid label, adds valid label, comments and does not update status when it is already correct",
-			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "UPDATED"}}}},
-			options:        JiraBranchOptions{StateAfterValidation: &updated}, // no requirements --> always valid
-			labels:         []string{labels.JiraInvalidBug},
-			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			name:           "relabel on merged PR reconciles labels instead of handling the merge",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}, Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityImportant}}}},
+			relabel:        true,
+			merged:         true,
+			body:           "/jira relabel",
+			options:        JiraBranchOptions{IsOpen: &open, StateAfterMerge: &modified},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityImportant},
 			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
 
-<details><summary>No validations were run on this bug</summary></details>
+<details><summary>1 validation was run on this bug</summary>
+
+* bug is open, matching expected state (open)</details>
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira relabel
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "UPDATED"}}},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:   &jira.Status{Name: "POST"},
+				Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityImportant},
+			}},
 		},
 		{
-			name:           "valid bug with external link removes invalid label, adds valid label, comments, makes an external bug link",
-			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
-			options:        JiraBranchOptions{AddExternalLink: &yes}, // no requirements --> always valid
-			labels:         []string{labels.JiraInvalidBug},
+			name:           "verify on unmerged PR is rejected",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}}},
+			verify:         true,
+			orgMembers:     []string{"user"},
+			body:           "/jira verify",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
 			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
-
-<details><summary>No validations were run on this bug</summary></details>
-
-The bug has been updated to refer to the pull request using the external bug tracker.
+			expectedComment: `org/repo#1:@user: This pull request has not been merged yet. Bugs can only be verified with <code>/jira verify</code> once the pull request has been merged.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira verify
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
-			expectedNewRemoteLinks: []jira.RemoteLink{{Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			},
-			}},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
 		},
 		{
-			name:   "valid bug with already existing external link removes invalid label, adds valid label, comments to say nothing changed",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			options:        JiraBranchOptions{AddExternalLink: &yes}, // no requirements --> always valid
-			labels:         []string{labels.JiraInvalidBug},
+			name:           "verify by a user who is not an org member is rejected",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}}},
+			verify:         true,
+			merged:         true,
+			body:           "/jira verify",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
 			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
-
-<details><summary>No validations were run on this bug</summary></details>
+			expectedComment: `org/repo#1:@user: Only members of the org organization can verify bugs with <code>/jira verify</code>.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira verify
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
 		},
 		{
-			name: "failure to fetch dependent bug results in a comment",
-			issues: []jira.Issue{{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
-			}}},
-			overrideEvent: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-124", IsBug: true}}, body: "This PR fixes OCPBUGS-124", title: "OCPBUGS-124: fixed it!", htmlUrl: "https://github.com/org/repo/pull/2", login: "user",
-			},
-			existingIssueLinks: []*jira.IssueLink{&cloneBetween123to124, &blocksBetween123to124},
-			issueGetErrors:     map[string]error{"OCPBUGS-123": errors.New("injected error getting bug")},
-			options:            JiraBranchOptions{DependentBugStates: &verified},
-			expectedComment: `org/repo#2:@user: An error was encountered searching for dependent bug OCPBUGS-123 for bug OCPBUGS-124 on the Jira server at https://my-jira.com. No known errors were detected, please see the full error message for details.
-
-<details><summary>Full error message.</summary>
-
-<code>
-injected error getting bug
-</code>
-
-</details>
-
-Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.
+			name:           "retry on valid bug moves the bug to the state after validation and comments",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			options:        JiraBranchOptions{StateAfterValidation: &updated},
+			retry:          true,
+			body:           "/jira retry",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the UPDATED state.
 
 <details>
 
-In response to [this](https://github.com/org/repo/pull/2):
+In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-124
+>/jira retry
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "UPDATED"}}},
 		},
 		{
-			name: "valid bug with dependent bugs removes invalid label, adds valid label, comments",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status:     &jira.Status{Name: "VERIFIED"},
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.TargetVersionField: &v2,
-				},
-			},
-			}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status:     &jira.Status{Name: "MODIFIED"},
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.TargetVersionField: &v1,
-				},
-			}}},
+			name:   "retry on a draft pull request does not move the bug",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
 			overrideEvent: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-124", IsBug: true}}, body: "This PR fixes OCPBUGS-124", title: "OCPBUGS-124: fixed it!", htmlUrl: "https://github.com/org/repo/pull/2", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira retry", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", draft: true,
 			},
-			existingIssueLinks: []*jira.IssueLink{&cloneBetween123to124, &blocksBetween123to124},
-			options:            JiraBranchOptions{IsOpen: &yes, TargetVersion: &v1Str, DependentBugStates: &verified, DependentBugTargetVersions: &[]string{v2Str}},
-			labels:             []string{labels.JiraInvalidBug},
-			expectedLabels:     []string{labels.JiraValidRef, labels.JiraValidBug},
-			expectedComment: `org/repo#2:@user: This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is valid.
-
-<details><summary>5 validation(s) were run on this bug</summary>
-
-* bug is open, matching expected state (open)
-* bug target version (v1) matches configured target version for branch (v1)
-* dependent bug [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in the state VERIFIED, which is one of the valid states (VERIFIED)
-* dependent [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) targets the "v2" version, which is one of the valid target versions: v2
-* bug has dependents</details>
+			options:        JiraBranchOptions{StateAfterValidation: &updated},
+			retry:          true,
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request is a draft, so the referenced bugs will not be moved to a new state until it is marked as ready for review.
 
 <details>
 
-In response to [this](https://github.com/org/repo/pull/2):
+In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-124
+>/jira retry
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
 		},
 		{
-			name:   "valid bug on merged PR with one external link migrates to new state with resolution and comments",
-			merged: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "MODIFIED"},
-			}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
-			options: JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}}, // no requirements --> always valid
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
-
-[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the CLOSED (MERGED) state.
+			name:           "retry on bug already in the state after validation comments without moving the bug",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "UPDATED"}}}},
+			options:        JiraBranchOptions{StateAfterValidation: &updated},
+			retry:          true,
+			body:           "/jira retry",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is already in the UPDATED state.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira retry
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status:     &jira.Status{Name: "CLOSED"},
-				Resolution: &jira.Resolution{Name: "MERGED"},
-				Unknowns:   tcontainer.MarshalMap{},
-			}},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "UPDATED"}}},
 		},
 		{
-			name:   "valid premerge bug on merged PR with one external link migrates to new state with resolution and comments",
-			merged: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status:          &jira.Status{Name: "MODIFIED"},
-				FixVersions:     []*jira.FixVersion{{Name: "premerge"}},
-				AffectsVersions: []*jira.AffectsVersion{{Name: "premerge"}},
-			}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			labels:         []string{labels.QEApproved},
-			expectedLabels: []string{labels.QEApproved},
-			prs:            []github.PullRequest{{Number: base.number, Merged: true}},
-			options:        JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}, PreMergeStateAfterMerge: &JiraBugState{Status: "UPDATED2", Resolution: "MERGED2"}}, // no requirements --> always valid
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
-
-[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the UPDATED2 (MERGED2) state.
+			name:           "retry on PR without valid bug label comments without moving the bug",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			options:        JiraBranchOptions{StateAfterValidation: &updated},
+			retry:          true,
+			body:           "/jira retry",
+			labels:         []string{labels.JiraValidRef, labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug},
+			expectedComment: `org/repo#1:@user: This pull request does not have the jira/valid-bug label, so no state transition is pending. To re-validate the referenced bug, request a bug refresh with <code>/jira refresh</code>.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira retry
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status:          &jira.Status{Name: "UPDATED2"},
-				Resolution:      &jira.Resolution{Name: "MERGED2"},
-				FixVersions:     []*jira.FixVersion{{Name: "premerge"}},
-				AffectsVersions: []*jira.AffectsVersion{{Name: "premerge"}},
-				Unknowns:        tcontainer.MarshalMap{},
-			}},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
 		},
 		{
-			name:   "2 valid bugs on merged PR with one external link migrates to new state with resolution and comments",
-			merged: true,
-			issues: []jira.Issue{
-				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Modified}}},
-				{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Modified}}},
-			},
-			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}}}, "OCPBUGS-124": {{ID: 2, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}}},
-			},
-			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
-			options: JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}}, // no requirements --> always valid
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
+			name:           "retry without a configured state after validation comments",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			retry:          true,
+			body:           "/jira retry",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: No state is configured for valid bugs in this repository, so there is no state transition to retry.
 
-[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the CLOSED (MERGED) state.
+<details>
 
-[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124): All pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
+In response to [this](https://github.com/org/repo/pull/1):
 
-[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been moved to the CLOSED (MERGED) state.
+>/jira retry
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
+		},
+		{
+			name:           "valid bug with no transition to the state after validation comments without moving the bug",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			transitions:    []jira.Transition{{ID: "1", Name: "NEW", To: jira.Status{Name: "NEW"}}, {ID: "2", Name: "MODIFIED", To: jira.Status{Name: "MODIFIED"}}},
+			options:        JiraBranchOptions{StateAfterValidation: &updated}, // no requirements --> always valid
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid. The bug could not be moved to the UPDATED state because no transition to UPDATED exists. Available transitions: NEW, MODIFIED.
+
+<details><summary>No validations were run on this bug</summary></details>
 
 <details>
 
@@ -1109,36 +1535,15 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status:     &jira.Status{Name: "CLOSED"},
-				Resolution: &jira.Resolution{Name: "MERGED"},
-				Unknowns:   tcontainer.MarshalMap{},
-			}},
-			expectedIssue2: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status:     &jira.Status{Name: "CLOSED"},
-				Resolution: &jira.Resolution{Name: "MERGED"},
-				Unknowns:   tcontainer.MarshalMap{},
-			}},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
 		},
 		{
-			name:   "valid bug on merged PR with one external link migrates to new state and comments",
-			merged: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
-			options: JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
-
-[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
+			name:                  "valid jira removes invalid label, adds valid label, comments",
+			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
+			issues:                []jira.Issue{{ID: "1", Key: "JIRA-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate}}}},
+			labels:                []string{labels.JiraInvalidBug},
+			expectedLabels:        []string{labels.JiraValidRef},
+			expectedComment: `org/repo#1:@user: This pull request references JIRA-123 which is a valid jira issue.
 
 <details>
 
@@ -1149,52 +1554,14 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
 		},
 		{
-			name:   "valid bug on merged PR with many external links migrates to new state and comments",
-			merged: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{
-				ID: 1,
-				Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/pull/1",
-					Title: "org/repo#1: OCPBUGS-123: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				},
-			}, {
-				ID: 2,
-				Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/pull/22/commits/1234567890",
-					Title: "org/repo#22: OCPBUGS-123: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				},
-			}, {
-				ID: 2,
-				Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/pull/23/files",
-					Title: "org/repo#23: OCPBUGS-123: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				},
-			},
-			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: true}, {Number: 22, Merged: true}, {Number: 23, Merged: true}},
-			options: JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
- * [org/repo#22](https://github.com/org/repo/pull/22)
- * [org/repo#23](https://github.com/org/repo/pull/23)
-
-[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
+			name:                  "jira in untracked project comments and applies no labels",
+			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
+			issues:                []jira.Issue{{ID: "1", Key: "JIRA-123", Fields: &jira.IssueFields{}}},
+			trackedProjects:       []string{"OCPBUGS"},
+			refresh:               true,
+			expectedComment: `org/repo#1:@user: This pull request references JIRA-123, which is in the JIRA project. Issues in the JIRA project are not managed by this plugin, so no labels have been applied.
 
 <details>
 
@@ -1205,46 +1572,41 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
 		},
 		{
-			name:   "valid bug on merged PR with unmerged external links does nothing",
-			merged: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{
-				ID: 1,
-				Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/pull/1",
-					Title: "org/repo#1: OCPBUGS-123: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				},
-			}, {
-				ID: 2,
-				Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/pull/22",
-					Title: "org/repo#22: OCPBUGS-123: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				},
-			},
-			}},
-			prs:           []github.PullRequest{{Number: base.number, Merged: true}, {Number: 22, Merged: false, State: "open"}},
-			options:       JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): Some pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
+			name:                  "jira in untracked project is ignored without a refresh",
+			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
+			issues:                []jira.Issue{{ID: "1", Key: "JIRA-123", Fields: &jira.IssueFields{}}},
+			trackedProjects:       []string{"OCPBUGS"},
+		},
+		{
+			name:                  "jira in tracked project adds valid label, comments",
+			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
+			issues:                []jira.Issue{{ID: "1", Key: "JIRA-123", Fields: &jira.IssueFields{}}},
+			trackedProjects:       []string{"OCPBUGS", "JIRA"},
+			expectedLabels:        []string{labels.JiraValidRef},
+			expectedComment: `org/repo#1:@user: This pull request references JIRA-123 which is a valid jira issue.
 
-The following pull requests linked via external trackers have not merged:
- * [org/repo#22](https://github.com/org/repo/pull/22) is open
+<details>
 
-These pull request must merge or be unlinked from the Jira bug in order for it to move to the next state. Once unlinked, request a bug refresh with <code>/jira refresh</code>.
+In response to [this](https://github.com/org/repo/pull/1):
 
-[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has not been moved to the MODIFIED state.
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:                  "valid jira with incorrect version removes invalid label, adds valid label,comments",
+			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
+			issues:                []jira.Issue{{ID: "1", Key: "JIRA-123", Fields: &jira.IssueFields{Type: jira.IssueType{Name: "Issue"}, Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate}}}},
+			labels:                []string{labels.JiraInvalidBug},
+			expectedLabels:        []string{labels.JiraValidRef},
+			options:               JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: This pull request references JIRA-123 which is a valid jira issue.
+
+Warning: The referenced jira issue has an invalid target version for the target branch this PR targets: expected the issue to target the "v1" version, but no target version was set.
 
 <details>
 
@@ -1257,58 +1619,22 @@ Instructions for interacting with me using PR comments are available [here](http
 </details>`,
 		},
 		{
-			name:   "2 valid bugs on merged PR, one with unmerged external links and one with merged external links",
-			merged: true,
+			name: "valid bug and valid jira ref adds valid/severity labels and comments",
 			issues: []jira.Issue{
-				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Modified}}},
-				{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Modified}}},
-			},
-			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{
-				ID: 1, Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/pull/1",
-					Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				},
-			}, {
-				ID: 2,
-				Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/pull/22",
-					Title: "org/repo#22: OCPBUGS-123: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				},
-			},
-			}, "OCPBUGS-124": {{ID: 2, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}}},
+				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Post}, Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}},
+				{ID: "2", Key: "JIRA-123", Fields: &jira.IssueFields{}},
 			},
-			prs:     []github.PullRequest{{Number: base.number, Merged: true}, {Number: 22, Merged: false, State: "open"}},
-			options: JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): Some pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
-
-The following pull requests linked via external trackers have not merged:
- * [org/repo#22](https://github.com/org/repo/pull/22) is open
-
-These pull request must merge or be unlinked from the Jira bug in order for it to move to the next state. Once unlinked, request a bug refresh with <code>/jira refresh</code>.
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "JIRA-123", IsBug: false}},
+			options:               JiraBranchOptions{IsOpen: &open},
+			labels:                []string{},
+			expectedLabels:        []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
 
-[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has not been moved to the CLOSED (MERGED) state.
+<details><summary>1 validation was run on this bug</summary>
 
-[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124): All pull requests linked via external trackers have merged:
- * [org/repo#1](https://github.com/org/repo/pull/1)
+* bug is open, matching expected state (open)</details>
 
-[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been moved to the CLOSED (MERGED) state.
+This pull request references JIRA-123 which is a valid jira issue.
 
 <details>
 
@@ -1319,35 +1645,14 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "MODIFIED"},
-			}},
-			expectedIssue2: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status:     &jira.Status{Name: "CLOSED"},
-				Resolution: &jira.Resolution{Name: "MERGED"},
-				Unknowns:   tcontainer.MarshalMap{},
-			}},
 		},
 		{
-			name:   "External bug on rep that is not in our config is ignored, bug gets set to MODIFIED",
-			merged: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/unreferenced/repo/pull/22",
-				Title: "unreferenced/repo#22: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:           []github.PullRequest{{Number: 22, Merged: false, State: "open"}},
-			options:       JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
-
-
-[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
+			name:                  "invalid jira with status update removes valid label, comments",
+			replaceReferencedBugs: []referencedBug{{Key: "JIRA-123", IsBug: false}},
+			labels:                []string{labels.JiraValidRef},
+			expectedLabels:        []string{},
+			expectedComment: `org/repo#1:@user: No Jira issue with key JIRA-123 exists in the tracker at https://my-jira.com.
+Once a valid jira issue is referenced in the title of this pull request, request a refresh with <code>/jira refresh</code>.
 
 <details>
 
@@ -1360,100 +1665,68 @@ Instructions for interacting with me using PR comments are available [here](http
 </details>`,
 		},
 		{
-			name:   "valid bug on merged PR with one external link but no status after merge configured does nothing",
-			merged: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:           []github.PullRequest{{Number: base.number, Merged: true}},
-			options:       JiraBranchOptions{}, // no requirements --> always valid
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+			name:           "refresh with comments once per state comments with the state of the bugs",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			refresh:        true,
+			body:           "/jira refresh",
+			options:        JiraBranchOptions{CommentOncePerState: &yes},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+<!-- jira-lifecycle-states: OCPBUGS-123=NEW -->
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
 		},
 		{
-			name:    "valid bug on merged PR with one external link but no referenced bug in the title does nothing",
-			merged:  true,
-			missing: true,
-			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:           []github.PullRequest{{Number: base.number, Merged: true}},
-			options:       JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+			name:           "refresh with comments once per state does not comment again if the state did not change",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			refresh:        true,
+			body:           "/jira refresh",
+			options:        JiraBranchOptions{CommentOncePerState: &yes},
+			prComments:     map[int][]github.IssueComment{1: {{Body: "org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.\n\n<details><summary>No validations were run on this bug</summary></details>\n<!-- jira-lifecycle-states: OCPBUGS-123=NEW -->", User: github.User{Login: fakegithub.Bot}}, {Body: "/jira refresh", User: github.User{Login: "user"}}}},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
 		},
 		{
-			name:           "valid bug on merged PR with one external link fails to update bug and comments",
-			merged:         true,
-			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
-			issueGetErrors: map[string]error{"OCPBUGS-123": errors.New("injected error getting bug")},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
-			options: JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
-			expectedComment: `org/repo#1:@user: An error was encountered searching for bug OCPBUGS-123 on the Jira server at https://my-jira.com. No known errors were detected, please see the full error message for details.
-
-<details><summary>Full error message.</summary>
-
-<code>
-injected error getting bug
-</code>
-
-</details>
+			name:           "refresh with comments once per state comments again if the state changed",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			refresh:        true,
+			body:           "/jira refresh",
+			options:        JiraBranchOptions{CommentOncePerState: &yes},
+			prComments:     map[int][]github.IssueComment{1: {{Body: "org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.\n\n<details><summary>No validations were run on this bug</summary></details>\n<!-- jira-lifecycle-states: OCPBUGS-123=NEW -->", User: github.User{Login: fakegithub.Bot}}}},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
 
-Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.
+<details><summary>No validations were run on this bug</summary></details>
+<!-- jira-lifecycle-states: OCPBUGS-123=POST -->
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-123
+>/jira refresh
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
 		},
 		{
-			name:   "valid bug on merged PR with merged external links but unknown status does not migrate to new state and comments",
-			merged: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
-			options: JiraBranchOptions{StateAfterValidation: &updated, StateAfterMerge: &modified}, // no requirements --> always valid
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in an unrecognized state (CLOSED) and will not be moved to the MODIFIED state.
+			name:           "valid no-jira removes invalid label, adds valid label, comments",
+			noJira:         true,
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef},
+			expectedComment: `org/repo#1:@user: This pull request explicitly references no jira issue.
 
 <details>
 
@@ -1464,36 +1737,79 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}},
 		},
 		{
-			name:   "closed PR removes link and comments",
-			merged: false,
-			closed: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
-			options: JiraBranchOptions{AddExternalLink: &yes},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker.
+			name:           "valid no-jira with no changes comments if there is no previous matching comment",
+			prComments:     map[int][]github.IssueComment{1: {{Body: "Hello", User: github.User{Login: "alex"}}, {Body: "Hello again", User: github.User{Login: fakegithub.Bot}}}},
+			noJira:         true,
+			labels:         []string{labels.JiraValidRef},
+			expectedLabels: []string{labels.JiraValidRef},
+			expectedComment: `org/repo#1:@user: This pull request explicitly references no jira issue.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name: "valid no-jira with no changes comments if there if latest bot comment does not match",
+			prComments: map[int][]github.IssueComment{1: {{Body: "Hello", User: github.User{Login: "alex"}}, {Body: `org/repo#1:@user: This pull request explicitly references no jira issue.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`, User: github.User{Login: fakegithub.Bot}}, {Body: "different comment", User: github.User{Login: fakegithub.Bot}}}},
+			noJira:         true,
+			labels:         []string{labels.JiraValidRef},
+			expectedLabels: []string{labels.JiraValidRef},
+			expectedComment: `org/repo#1:@user: This pull request explicitly references no jira issue.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name: "valid no-jira with no changes does not comments if latest bot comment matches",
+			prComments: map[int][]github.IssueComment{1: {{Body: "Hello", User: github.User{Login: "alex"}}, {Body: `org/repo#1:@user: This pull request explicitly references no jira issue.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`, User: github.User{Login: fakegithub.Bot}}}},
+			noJira:         true,
+			labels:         []string{labels.JiraValidRef},
+			expectedLabels: []string{labels.JiraValidRef},
+		},
+		{
+			name:           "valid bug with status update removes invalid label, adds valid label, comments and updates status with resolution",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityLow}}}},
+			options:        JiraBranchOptions{StateAfterValidation: &JiraBugState{Status: "CLOSED", Resolution: "VALIDATED"}}, // no requirements --> always valid
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityLow},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid. The bug has been moved to the CLOSED (VALIDATED) state.
+
+<details><summary>No validations were run on this bug</summary></details>
 
 <details>
 
@@ -1505,65 +1821,49 @@ In response to [this](https://github.com/org/repo/pull/1):
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
 			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
+				Status: &jira.Status{
+					Name: "CLOSED",
 				},
-			}},
-			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
+				Resolution: &jira.Resolution{
+					Name: "VALIDATED",
 				},
-			}},
+				// due to the way `Unknowns` works, this has to be provided as a map[string]interface{}
+				Unknowns: tcontainer.MarshalMap{helpers.SeverityField: map[string]interface{}{"Value": string(`<img alt="" src="/images/icons/priorities/low.svg" width="16" height="16"> Low`)}},
+			},
 			},
 		},
 		{
-			name:   "closed PR without a link does nothing",
-			merged: false,
-			closed: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
-			options: JiraBranchOptions{AddExternalLink: &yes},
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}},
+			name:           "valid bug with status update removes invalid label, adds valid label, comments and does not update status when it is already correct",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "UPDATED"}}}},
+			options:        JiraBranchOptions{StateAfterValidation: &updated}, // no requirements --> always valid
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "UPDATED"}}},
 		},
 		{
-			name:   "closed PR removes link, changes bug state, and comments",
-			merged: false,
-			closed: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Status: &jira.Status{Name: "POST"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
-			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the NEW state.
+			name:           "valid bug with external link removes invalid label, adds valid label, comments, makes an external bug link",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
+			options:        JiraBranchOptions{AddExternalLink: &yes}, // no requirements --> always valid
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+The bug has been updated to refer to the pull request using the external bug tracker.
 
 <details>
 
@@ -1574,57 +1874,46 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "NEW"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}, {
-					Body:       "Bug status changed to NEW as previous linked PR https://github.com/org/repo/pull/1 has been closed",
-					Visibility: PrivateVisibility,
-				}}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}},
-			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+			expectedNewRemoteLinks: []jira.RemoteLink{{Object: &jira.RemoteLinkObject{
 				URL:   "https://github.com/org/repo/pull/1",
 				Title: "org/repo#1: OCPBUGS-123: fixed it!",
 				Icon: &jira.RemoteLinkIcon{
 					Url16x16: "https://github.com/favicon.ico",
 					Title:    "GitHub",
 				},
-			}},
 			},
+			}},
 		},
 		{
-			name:   "closed PR of premerge bug removes link, changes bug state, and comments",
-			merged: false,
-			closed: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Status: &jira.Status{Name: "POST"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
+			name:   "valid bug already linked to more pull requests than the threshold warns when adding an external link",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 2, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/2",
+				Title: "org/repo#2: OCPBUGS-123: first fix",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
 				},
-				FixVersions:     []*jira.FixVersion{{Name: "premerge"}},
-				AffectsVersions: []*jira.AffectsVersion{{Name: "premerge"}},
-			}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+			}}, {ID: 3, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/3",
+				Title: "org/repo#3: OCPBUGS-123: second fix",
 				Icon: &jira.RemoteLinkIcon{
 					Url16x16: "https://github.com/favicon.ico",
 					Title:    "GitHub",
 				},
-			}},
-			}},
-			labels:         []string{labels.QEApproved},
-			expectedLabels: []string{labels.QEApproved},
-			prs:            []github.PullRequest{{Number: base.number, Merged: false}},
-			options:        JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}, PreMergeStateAfterClose: &JiraBugState{Status: "NEW2"}},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the NEW2 state.
+			}}}},
+			options:        JiraBranchOptions{AddExternalLink: &yes, LinkedPullRequestsWarningThreshold: &one},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+Warning: The referenced bug is already linked to 2 pull requests, which is more than the 1 expected for this repository. Please make sure that this pull request references the correct bug. The bug is already linked to:
+- [org/repo#2: OCPBUGS-123: first fix](https://github.com/org/repo/pull/2)
+- [org/repo#3: OCPBUGS-123: second fix](https://github.com/org/repo/pull/3)
+
+The bug has been updated to refer to the pull request using the external bug tracker.
 
 <details>
 
@@ -1635,74 +1924,35 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "NEW2"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}, {
-					Body:       "Bug status changed to NEW as previous linked PR https://github.com/org/repo/pull/1 has been closed",
-					Visibility: PrivateVisibility,
-				}}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-				FixVersions:     []*jira.FixVersion{{Name: "premerge"}},
-				AffectsVersions: []*jira.AffectsVersion{{Name: "premerge"}},
-			}},
-			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+			expectedNewRemoteLinks: []jira.RemoteLink{{Object: &jira.RemoteLinkObject{
 				URL:   "https://github.com/org/repo/pull/1",
 				Title: "org/repo#1: OCPBUGS-123: fixed it!",
 				Icon: &jira.RemoteLinkIcon{
 					Url16x16: "https://github.com/favicon.ico",
 					Title:    "GitHub",
 				},
-			}},
 			},
+			}},
 		},
 		{
-			name:                  "closed PR for multiple bugs removes links, changes bug states, and comments",
-			merged:                false,
-			closed:                true,
-			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Status: &jira.Status{Name: "POST"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is also a bug",
-				}}},
-				Status: &jira.Status{Name: "POST"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			}, "OCPBUGS-124": {{ID: 2, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+			name:   "valid bug linked to as many pull requests as the threshold does not warn when adding an external link",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 2, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/2",
+				Title: "org/repo#2: OCPBUGS-123: first fix",
 				Icon: &jira.RemoteLinkIcon{
 					Url16x16: "https://github.com/favicon.ico",
 					Title:    "GitHub",
 				},
-			}},
-			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
-			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the NEW state.
+			}}}},
+			options:        JiraBranchOptions{AddExternalLink: &yes, LinkedPullRequestsWarningThreshold: &one},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
 
-This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the NEW state.
+<details><summary>No validations were run on this bug</summary></details>
+
+The bug has been updated to refer to the pull request using the external bug tracker.
 
 <details>
 
@@ -1713,89 +1963,35 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "NEW"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}, {
-					Body:       "Bug status changed to NEW as previous linked PR https://github.com/org/repo/pull/1 has been closed",
-					Visibility: PrivateVisibility,
-				}}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}},
-			expectedIssue2: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "NEW"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is also a bug",
-				}, {
-					Body:       "Bug status changed to NEW as previous linked PR https://github.com/org/repo/pull/1 has been closed",
-					Visibility: PrivateVisibility,
-				}}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}},
-			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+			expectedNewRemoteLinks: []jira.RemoteLink{{Object: &jira.RemoteLinkObject{
 				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
 				Icon: &jira.RemoteLinkIcon{
 					Url16x16: "https://github.com/favicon.ico",
 					Title:    "GitHub",
 				},
-			}}, {ID: 2, Object: &jira.RemoteLinkObject{
+			},
+			}},
+		},
+		{
+			name:   "valid bug with already existing external link removes invalid label, adds valid label, comments to say nothing changed",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
 				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
 				Icon: &jira.RemoteLinkIcon{
 					Url16x16: "https://github.com/favicon.ico",
 					Title:    "GitHub",
 				},
-			}}},
-		},
-		{
-			name:    "closed PR with missing bug does nothing",
-			merged:  false,
-			closed:  true,
-			missing: true,
-			issues:  []jira.Issue{},
-			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
-			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}},
-		},
-		{
-			name:   "closed PR with multiple external links removes link, does not change bug state, and comments",
-			merged: false,
-			closed: true,
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "POST"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}}},
-			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{
-				ID: 1,
-				Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/pull/1",
-					Title: "org/repo#1: OCPBUGS-123: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				},
-			}, {
-				ID: 2,
-				Object: &jira.RemoteLinkObject{
-					URL:   "https://github.com/org/repo/issues/42",
-					Title: "org/repo#42: OCPBUGS-123: fixed it!",
-					Icon: &jira.RemoteLinkIcon{
-						Url16x16: "https://github.com/favicon.ico",
-						Title:    "GitHub",
-					},
-				}},
 			}},
-			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
-			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker.
+			}},
+			options:        JiraBranchOptions{AddExternalLink: &yes}, // no requirements --> always valid
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
 
 <details>
 
@@ -1806,209 +2002,179 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "POST"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField: severityCritical,
-				},
-			}},
-			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
-				URL:   "https://github.com/org/repo/pull/1",
-				Title: "org/repo#1: OCPBUGS-123: fixed it!",
-				Icon: &jira.RemoteLinkIcon{
-					Url16x16: "https://github.com/favicon.ico",
-					Title:    "GitHub",
-				},
-			}},
-			},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
 		},
 		{
-			name: "Cherrypick PR results in cloned bug creation",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
+			name: "failure to fetch dependent bug results in a comment",
+			issues: []jira.Issue{{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
 			}}},
-			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
-			title:               "[v1] " + base.title,
-			cherrypick:          true,
-			cherryPickFromPRNum: 1,
-			options:             JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
-/retitle [v1] OCPBUGS-124: fixed it!
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-124", IsBug: true}}, body: "This PR fixes OCPBUGS-124", title: "OCPBUGS-124: fixed it!", htmlUrl: "https://github.com/org/repo/pull/2", login: "user",
+			},
+			existingIssueLinks: []*jira.IssueLink{&cloneBetween123to124, &blocksBetween123to124},
+			issueGetErrors:     map[string]error{"OCPBUGS-123": errors.New("injected error getting bug")},
+			options:            JiraBranchOptions{DependentBugStates: &verified},
+			expectedComment: `org/repo#2:@user: An error was encountered searching for dependent bug OCPBUGS-123 for bug OCPBUGS-124 on the Jira server at https://my-jira.com. No known errors were detected, please see the full error message for details.
+
+<details><summary>Full error message.</summary>
+
+<code>
+injected error getting bug
+</code>
+
+</details>
+
+Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.
 
 <details>
 
-In response to [this](https://github.com/org/repo/pull/1):
+In response to [this](https://github.com/org/repo/pull/2):
 
->This PR fixes OCPBUGS-123
+>This PR fixes OCPBUGS-124
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
-				},
-			}},
 		},
 		{
-			name: "Cherrypick PR for multiple bugs results in multiple cloned bug creation",
+			name: "bug with dependent whose linked PRs are merged is valid",
 			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
-			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
+				Status:     &jira.Status{Name: "VERIFIED"},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
+			},
+			}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "MODIFIED"},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
 			}}},
-			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
-			prs:                   []github.PullRequest{{Number: base.number, Body: base.body, Title: "OCPBUGS-123,OCPBUGS-124: fixed it!"}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] OCPBUGS-123,OCPBUGS-124: fixed it!"}},
-			title:                 "[v1] OCPBUGS-123,OCPBUGS-124: fixed it!",
-			cherrypick:            true,
-			cherryPickFromPRNum:   1,
-			options:               JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125). Will retitle bug to link to clone.
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {
+				{ID: 1, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/10", Title: "org/repo#10: OCPBUGS-123: fixed it!"}},
+				{ID: 2, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/11", Title: "org/repo#11: OCPBUGS-123: fixed it again!"}},
+			}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-124", IsBug: true}}, body: "This PR fixes OCPBUGS-124", title: "OCPBUGS-124: fixed it!", htmlUrl: "https://github.com/org/repo/pull/2", login: "user",
+			},
+			existingIssueLinks: []*jira.IssueLink{&cloneBetween123to124, &blocksBetween123to124},
+			options:            JiraBranchOptions{RequireDependentPRsMerged: &yes},
+			prs:                []github.PullRequest{{Number: 10, Merged: true, State: "closed"}, {Number: 11, Merged: true, State: "closed"}},
+			expectedLabels:     []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#2:@user: This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is valid.
 
-[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been cloned as [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126). Will retitle bug to link to clone.
-/retitle [v1] OCPBUGS-125,OCPBUGS-126: fixed it!
+<details><summary>2 validations were run on this bug</summary>
+
+* all pull requests linked to dependent [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) are merged
+* bug has dependents</details>
 
 <details>
 
-In response to [this](https://github.com/org/repo/pull/1):
+In response to [this](https://github.com/org/repo/pull/2):
 
->This PR fixes OCPBUGS-123
+>This PR fixes OCPBUGS-124
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
-				},
-			}},
-			expectedIssue2: &jira.Issue{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-124. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{{
-					Type: jira.IssueLinkType{
-						Name:    "Cloners",
-						Inward:  "is cloned by",
-						Outward: "clones",
-					},
-					OutwardIssue: &jira.Issue{ID: "2"},
-				}, {
-					Type: jira.IssueLinkType{
-						Name:    "Blocks",
-						Inward:  "is blocked by",
-						Outward: "blocks",
-					},
-					InwardIssue: &jira.Issue{ID: "2"},
-				}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
-				},
+		},
+		{
+			name: "bug with dependent that has an unmerged linked PR is invalid",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "VERIFIED"},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
+			},
+			}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "MODIFIED"},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {
+				{ID: 1, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/10", Title: "org/repo#10: OCPBUGS-123: fixed it!"}},
+				{ID: 2, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/11", Title: "org/repo#11: OCPBUGS-123: fixed it again!"}},
 			}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-124", IsBug: true}}, body: "This PR fixes OCPBUGS-124", title: "OCPBUGS-124: fixed it!", htmlUrl: "https://github.com/org/repo/pull/2", login: "user",
+			},
+			existingIssueLinks: []*jira.IssueLink{&cloneBetween123to124, &blocksBetween123to124},
+			options:            JiraBranchOptions{RequireDependentPRsMerged: &yes},
+			prs:                []github.PullRequest{{Number: 10, Merged: true, State: "closed"}, {Number: 11, State: "open"}},
+			expectedLabels:     []string{labels.JiraValidRef, labels.JiraInvalidBug},
+			expectedComment: `org/repo#2:@user: This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is invalid:
+ - expected all pull requests linked to dependent [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) to be merged, but the following are not: [org/repo#11](https://github.com/org/repo/pull/11)
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/2):
+
+>This PR fixes OCPBUGS-124
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
 		},
 		{
-			name: "Cherrypick PR for multiple bugs with 1 failure still comments for both bugs",
+			name: "valid bug with dependent bugs removes invalid label, adds valid label, comments",
 			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
+				Status:     &jira.Status{Name: "VERIFIED"},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
 				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
 					helpers.TargetVersionField: &v2,
 				},
-			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
+			},
+			}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "MODIFIED"},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
 				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
+					helpers.TargetVersionField: &v1,
 				},
 			}}},
-			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
-			issueUpdateErrors:     map[string]error{"OCPBUGS-125": errors.New("injected error updating bug OCPBUGS-125")},
-			prs:                   []github.PullRequest{{Number: base.number, Body: base.body, Title: "OCPBUGS-123,OCPBUGS-124: fixed it!"}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] OCPBUGS-123,OCPBUGS-124: fixed it!"}},
-			title:                 "[v1] OCPBUGS-123,OCPBUGS-124: fixed it!",
-			cherrypick:            true,
-			cherryPickFromPRNum:   1,
-			options:               JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125). Will retitle bug to link to clone.
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-124", IsBug: true}}, body: "This PR fixes OCPBUGS-124", title: "OCPBUGS-124: fixed it!", htmlUrl: "https://github.com/org/repo/pull/2", login: "user",
+			},
+			existingIssueLinks: []*jira.IssueLink{&cloneBetween123to124, &blocksBetween123to124},
+			options:            JiraBranchOptions{IsOpen: &yes, TargetVersion: &v1Str, DependentBugStates: &verified, DependentBugTargetVersions: &[]string{v2Str}},
+			labels:             []string{labels.JiraInvalidBug},
+			expectedLabels:     []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#2:@user: This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is valid.
 
-WARNING: Failed to update the target version for the clone. Please update the target version manually. Full error below:
-<details><summary>Full error message.</summary>
+<details><summary>5 validations were run on this bug</summary>
 
-<code>
-injected error updating bug OCPBUGS-125
-</code>
+* bug is open, matching expected state (open)
+* bug target version (v1) matches configured target version for branch (v1)
+* dependent bug [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in the state VERIFIED, which is one of the valid states (VERIFIED)
+* dependent [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) targets the "v2" version, which is one of the valid target versions: v2
+* bug has dependents</details>
 
-</details>
+<details>
 
-[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been cloned as [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126). Will retitle bug to link to clone.
-/retitle [v1] OCPBUGS-125,OCPBUGS-126: fixed it!
+In response to [this](https://github.com/org/repo/pull/2):
+
+>This PR fixes OCPBUGS-124
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:   "valid bug on merged PR with one external link migrates to new state with resolution and comments",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "MODIFIED"},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}}, // no requirements --> always valid
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the CLOSED (MERGED) state.
 
 <details>
 
@@ -2019,214 +2185,123 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v2Str}},
-				},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "MERGED"},
+				Unknowns:   tcontainer.MarshalMap{},
 			}},
-			expectedIssue2: &jira.Issue{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-124. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{{
-					Type: jira.IssueLinkType{
-						Name:    "Cloners",
-						Inward:  "is cloned by",
-						Outward: "clones",
-					},
-					OutwardIssue: &jira.Issue{ID: "2"},
-				}, {
-					Type: jira.IssueLinkType{
-						Name:    "Blocks",
-						Inward:  "is blocked by",
-						Outward: "blocks",
+		},
+		{
+			name:   "valid bug on merged PR that blocks an open bug is not migrated when closure is blocked by open bugs",
+			merged: true,
+			issues: []jira.Issue{
+				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+					Status: &jira.Status{Name: "MODIFIED"},
+					IssueLinks: []*jira.IssueLink{
+						{Type: jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}, OutwardIssue: &jira.Issue{Key: "OCPBUGS-124"}},
+						{Type: jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}, OutwardIssue: &jira.Issue{Key: "OCPBUGS-125"}},
 					},
-					InwardIssue: &jira.Issue{ID: "2"},
 				}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
+				{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{Status: &jira.Status{Name: "CLOSED"}, Resolution: &jira.Resolution{Name: "ERRATA"}}},
+			},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
 				},
 			}},
-		},
-		{
-			name: "Cherrypick comment results in cloned bug creation",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
-			}}},
-			prs: []github.PullRequest{{Number: 2, Body: "This is a manually created cherrypick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
-			overrideEvent: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira cherrypick OCPBUGS-123", title: "fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", cherrypick: true, cherrypickCmd: true, missing: true,
-			},
-			cherrypick: true,
-			missing:    true,
-			options:    JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#2:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
-/retitle OCPBUGS-124: fixed it!
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}, BlockClosureIfBlocksOpen: &yes},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+The bug blocks the following bugs, which are still open:
+ * [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) is in the NEW state
+
+The bug will not be moved to the CLOSED (MERGED) state until they are closed. Once they are, request a bug refresh with <code>/jira refresh</code>.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->/jira cherrypick OCPBUGS-123
+>This PR fixes OCPBUGS-123
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "MODIFIED"},
+				IssueLinks: []*jira.IssueLink{
+					{Type: jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}, OutwardIssue: &jira.Issue{Key: "OCPBUGS-124"}},
+					{Type: jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}, OutwardIssue: &jira.Issue{Key: "OCPBUGS-125"}},
 				},
 			}},
 		},
 		{
-			name: "Cherrypick comment for multiple bugs results in multiple cloned bug creation",
+			name:   "valid bug on merged PR is not migrated when its fix versions do not include the target version",
+			merged: true,
 			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
-			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
+				Status:      &jira.Status{Name: "MODIFIED"},
+				FixVersions: []*jira.FixVersion{{Name: "v2"}},
 			}}},
-			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
-			prs:                   []github.PullRequest{{Number: 2, Body: "This is a manually created cherrypick of #1.\n\n/assign user", Title: "[v1] fixing stuff"}},
-			overrideEvent: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}}, body: "/jira cherrypick OCPBUGS-123,OCPBUGS-124", title: "fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", cherrypick: true, cherrypickCmd: true, missing: true,
-			},
-			cherrypick: true,
-			missing:    true,
-			options:    JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#2:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125). Will retitle bug to link to clone.
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{TargetVersion: &v1Str, StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}, ValidateFixVersionOnMerge: &yes},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
 
-[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been cloned as [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126). Will retitle bug to link to clone.
-/retitle OCPBUGS-125,OCPBUGS-126: fixed it!
+The fix versions of the bug (v2) do not include the target version of the branch (v1).
+
+The bug will not be moved to the CLOSED (MERGED) state until its fix versions are corrected. Once they are, request a bug refresh with <code>/jira refresh</code>.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->/jira cherrypick OCPBUGS-123,OCPBUGS-124
+>This PR fixes OCPBUGS-123
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
-				},
-			}},
-			expectedIssue2: &jira.Issue{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-124. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{{
-					Type: jira.IssueLinkType{
-						Name:    "Cloners",
-						Inward:  "is cloned by",
-						Outward: "clones",
-					},
-					OutwardIssue: &jira.Issue{ID: "2"},
-				}, {
-					Type: jira.IssueLinkType{
-						Name:    "Blocks",
-						Inward:  "is blocked by",
-						Outward: "blocks",
-					},
-					InwardIssue: &jira.Issue{ID: "2"},
-				}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
-				},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:      &jira.Status{Name: "MODIFIED"},
+				FixVersions: []*jira.FixVersion{{Name: "v2"}},
 			}},
 		},
 		{
-			name: "parent PR of cherrypick not existing results in error",
+			name:   "valid bug on merged PR records the merge in a Jira comment with the configured visibility",
+			merged: true,
+			closed: true,
 			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
+				Status: &jira.Status{Name: "MODIFIED"},
 			}}},
-			prs:                 []github.PullRequest{{Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
-			title:               "[v1] " + base.title,
-			cherrypick:          true,
-			cherryPickFromPRNum: 1,
-			options:             JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: Error creating a cherry-pick bug in Jira: failed to check the state of cherrypicked pull request at https://github.com/org/repo/pull/1: pull request number 1 does not exist.
-Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}, CommentOnJiraAtMerge: &yes, MergeCommentVisibility: &jira.CommentVisibility{Type: "role", Value: "Developers"}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the CLOSED (MERGED) state.
 
 <details>
 
@@ -2237,31 +2312,56 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "MERGED"},
+				Unknowns:   tcontainer.MarshalMap{},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body:       "The fix for OCPBUGS-123 has merged in https://github.com/org/repo/pull/1.",
+					Visibility: jira.CommentVisibility{Type: "role", Value: "Developers"},
+				}}},
+			}},
 		},
 		{
-			name: "failure to obtain parent bug for cherrypick results in error",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
+			name:   "valid bugs on merged PR where one fails to migrate still migrate the other and comment on both",
+			merged: true,
+			issues: []jira.Issue{
+				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+				{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+			},
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
+			remoteLinks: map[string][]jira.RemoteLink{
+				"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/1",
+					Title: "org/repo#1: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
 				}}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
-			}}},
-			issueGetErrors:      map[string]error{"OCPBUGS-123": errors.New("injected error getting bug")},
-			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
-			title:               "[v1] " + base.title,
-			cherrypick:          true,
-			cherryPickFromPRNum: 1,
-			options:             JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: An error was encountered searching for bug OCPBUGS-123 on the Jira server at https://my-jira.com. No known errors were detected, please see the full error message for details.
+				"OCPBUGS-124": {{ID: 1, Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/1",
+					Title: "org/repo#1: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
+				}}},
+			},
+			issueUpdateErrors: map[string]error{"OCPBUGS-124": errors.New("injected error updating bug OCPBUGS-124")},
+			prs:               []github.PullRequest{{Number: base.number, Merged: true}},
+			options:           JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the CLOSED (MERGED) state.
+
+An error was encountered updating to the MERGED resolution for bug OCPBUGS-124 on the Jira server at https://my-jira.com. No known errors were detected, please see the full error message for details.
 
 <details><summary>Full error message.</summary>
 
 <code>
-injected error getting bug
+injected error updating bug OCPBUGS-124
 </code>
 
 </details>
@@ -2277,38 +2377,38 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-		}, {
-			name: "failure to update bug retitles the PR and prints a warning in the comment",
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "MERGED"},
+				Unknowns:   tcontainer.MarshalMap{},
+			}},
+			expectedIssue2: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{Status: &jira.Status{Name: "CLOSED"}}},
+		},
+		{
+			name:   "valid premerge bug on merged PR with one external link migrates to new state with resolution and comments",
+			merged: true,
 			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
+				Status:          &jira.Status{Name: "MODIFIED"},
+				FixVersions:     []*jira.FixVersion{{Name: "premerge"}},
+				AffectsVersions: []*jira.AffectsVersion{{Name: "premerge"}},
 			}}},
-			issueUpdateErrors:   map[string]error{"OCPBUGS-124": errors.New("injected error updating bug OCPBUGS-124")},
-			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
-			title:               "[v1] " + base.title,
-			cherrypick:          true,
-			cherryPickFromPRNum: 1,
-			options:             JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
-
-WARNING: Failed to update the target version for the clone. Please update the target version manually. Full error below:
-<details><summary>Full error message.</summary>
-
-<code>
-injected error updating bug OCPBUGS-124
-</code>
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			labels:         []string{labels.QEApproved},
+			expectedLabels: []string{labels.QEApproved},
+			prs:            []github.PullRequest{{Number: base.number, Merged: true}},
+			options:        JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}, PreMergeStateAfterMerge: &JiraBugState{Status: "UPDATED2", Resolution: "MERGED2"}}, // no requirements --> always valid
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
 
-</details>
-/retitle [v1] OCPBUGS-124: fixed it!
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the UPDATED2 (MERGED2) state.
 
 <details>
 
@@ -2319,34 +2419,49 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-		}, {
-			name: "If bug clone with correct target version already exists, just retitle PR",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
-				Status:     &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
-			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
-				Status:     &jira.Status{Name: "NEW"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v1,
-				},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:          &jira.Status{Name: "UPDATED2"},
+				Resolution:      &jira.Resolution{Name: "MERGED2"},
+				FixVersions:     []*jira.FixVersion{{Name: "premerge"}},
+				AffectsVersions: []*jira.AffectsVersion{{Name: "premerge"}},
+				Unknowns:        tcontainer.MarshalMap{},
 			}},
+		},
+		{
+			name:   "2 valid bugs on merged PR with one external link migrates to new state with resolution and comments",
+			merged: true,
+			issues: []jira.Issue{
+				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Modified}}},
+				{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Modified}}},
 			},
-			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
-			title:               "[v1] " + base.title,
-			cherrypick:          true,
-			cherryPickFromPRNum: 1,
-			options:             JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: Detected clone of [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) with correct target version. Will retitle the PR to link to the clone.
-/retitle [v1] OCPBUGS-124: fixed it!
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}}}, "OCPBUGS-124": {{ID: 2, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}}},
+			},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}}, // no requirements --> always valid
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the CLOSED (MERGED) state.
+
+[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been moved to the CLOSED (MERGED) state.
 
 <details>
 
@@ -2357,45 +2472,36 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-		}, {
-			name: "If clone with correct target version already exists in multibug PR, retitle PR for correct clone and create clone for other bug",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
 				Status:     &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
-				},
-			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
-				Status:     &jira.Status{Name: "NEW"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v1,
-				},
-			}}, {ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v1,
+				Resolution: &jira.Resolution{Name: "MERGED"},
+				Unknowns:   tcontainer.MarshalMap{},
+			}},
+			expectedIssue2: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "MERGED"},
+				Unknowns:   tcontainer.MarshalMap{},
+			}},
+		},
+		{
+			name:   "valid bug on merged PR with one external link migrates to new state and comments",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
 				},
 			}},
-			},
-			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: "OCPBUGS-123,OCPBUGS-125: fixed it!"}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] OCPBUGS-123,OCPBUGS-125: fixed it!"}},
-			title:               "[v1] OCPBUGS-123,OCPBUGS-125: fixed it!",
-			cherrypick:          true,
-			cherryPickFromPRNum: 1,
-			options:             JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: Detected clone of [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) with correct target version. Will retitle the PR to link to the clone.
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
 
-[Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125) has been cloned as [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126). Will retitle bug to link to clone.
-/retitle [v1] OCPBUGS-124,OCPBUGS-126: fixed it!
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
 
 <details>
 
@@ -2406,61 +2512,52 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-125. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"},
-				Project: jira.Project{
-					Name: "OCPBUGS",
-				},
-				IssueLinks: []*jira.IssueLink{{
-					Type: jira.IssueLinkType{
-						Name:    "Cloners",
-						Inward:  "is cloned by",
-						Outward: "clones",
-					},
-					OutwardIssue: &jira.Issue{ID: "3"},
-				}, {
-					Type: jira.IssueLinkType{
-						Name:    "Blocks",
-						Inward:  "is blocked by",
-						Outward: "blocks",
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+		},
+		{
+			name:   "valid bug on merged PR with many external links migrates to new state and comments",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{
+				ID: 1,
+				Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/1",
+					Title: "org/repo#1: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
 					},
-					InwardIssue: &jira.Issue{ID: "3"},
-				}},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
-				},
-			}},
-		}, {
-			name: "Clone for different version does not block creation of new clone",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "CLOSED"},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
 				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v2,
+			}, {
+				ID: 2,
+				Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/22/commits/1234567890",
+					Title: "org/repo#22: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
 				},
-			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "NEW"},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      severityCritical,
-					helpers.TargetVersionField: &v3,
+			}, {
+				ID: 2,
+				Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/23/files",
+					Title: "org/repo#23: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
 				},
-			}},
 			},
-			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
-			title:               "[v1] " + base.title,
-			cherrypick:          true,
-			cherryPickFromPRNum: 1,
-			options:             JiraBranchOptions{TargetVersion: &v1Str},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125). Will retitle bug to link to clone.
-/retitle [v1] OCPBUGS-125: fixed it!
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}, {Number: 22, Merged: true}, {Number: 23, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+ * [org/repo#22](https://github.com/org/repo/pull/22)
+ * [org/repo#23](https://github.com/org/repo/pull/23)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
 
 <details>
 
@@ -2471,110 +2568,258 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
-				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
-				Status:      &jira.Status{Name: "CLOSED"}, // during a clone on a real jira server, this field would get unset/reset; the fake client copies
-				IssueLinks:  []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
-				Comments: &jira.Comments{Comments: []*jira.Comment{{
-					Body: "This is a bug",
-				}}},
-				Project: jira.Project{
-					Name: "OCPBUGS",
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+		},
+		{
+			name:   "valid bug on merged PR with unmerged external links does nothing",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{
+				ID: 1,
+				Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/1",
+					Title: "org/repo#1: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
 				},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
-					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+			}, {
+				ID: 2,
+				Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/22",
+					Title: "org/repo#22: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
 				},
+			},
 			}},
-		}, {
-			name:    "Bug with non-allowed security level is ignored",
-			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
-			options: JiraBranchOptions{AllowedSecurityLevels: []string{"internal"}},
-			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
-			// there should be no comment returned in this test case
-		}, {
-			name:           "Bug with security level on repo with no allowed security levels results in comment on /jira refresh",
-			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
-			prs:            []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
-			refresh:        true,
-			body:           "/jira refresh",
-			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
-
-<details><summary>No validations were run on this bug</summary></details>
-
+			prs:           []github.PullRequest{{Number: base.number, Merged: true}, {Number: 22, Merged: false, State: "open"}},
+			options:       JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): Some pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+The following pull requests linked via external trackers have not merged:
+ * [org/repo#22](https://github.com/org/repo/pull/22) is open
+
+These pull request must merge or be unlinked from the Jira bug in order for it to move to the next state. Once unlinked, request a bug refresh with <code>/jira refresh</code>.
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has not been moved to the MODIFIED state.
+
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->/jira refresh
+>This PR fixes OCPBUGS-123
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-		}, {
-			name: "Bug matching previous bot comment still comments on /jira refresh with no changes",
-			prComments: map[int][]github.IssueComment{1: {{Body: "Hello", User: github.User{Login: "alex"}}, {Body: `@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+		},
+		{
+			name:   "2 valid bugs on merged PR, one with unmerged external links and one with merged external links",
+			merged: true,
+			issues: []jira.Issue{
+				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Modified}}},
+				{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{Status: &jira.Status{Name: status.Modified}}},
+			},
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{
+				ID: 1, Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/1",
+					Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
+				},
+			}, {
+				ID: 2,
+				Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/22",
+					Title: "org/repo#22: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
+				},
+			},
+			}, "OCPBUGS-124": {{ID: 2, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}}},
+			},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}, {Number: 22, Merged: false, State: "open"}},
+			options: JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): Some pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
 
-<details><summary>No validations were run on this bug</summary></details>
+The following pull requests linked via external trackers have not merged:
+ * [org/repo#22](https://github.com/org/repo/pull/22) is open
+
+These pull request must merge or be unlinked from the Jira bug in order for it to move to the next state. Once unlinked, request a bug refresh with <code>/jira refresh</code>.
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has not been moved to the CLOSED (MERGED) state.
+
+[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been moved to the CLOSED (MERGED) state.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->/jira refresh
+>This PR fixes OCPBUGS-123
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
-</details>`, User: github.User{Login: fakegithub.Bot}}}},
-			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
-			prs:            []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
-			refresh:        true,
-			body:           "/jira refresh",
-			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
-			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "MODIFIED"},
+			}},
+			expectedIssue2: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "MERGED"},
+				Unknowns:   tcontainer.MarshalMap{},
+			}},
+		},
+		{
+			name:   "External bug on rep that is not in our config is ignored, bug gets set to MODIFIED",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/unreferenced/repo/pull/22",
+				Title: "unreferenced/repo#22: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:           []github.PullRequest{{Number: 22, Merged: false, State: "open"}},
+			options:       JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
 
-<details><summary>No validations were run on this bug</summary></details>
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->/jira refresh
+>This PR fixes OCPBUGS-123
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-		}, {
-			name:    "Bug with non-allowed security level results in comment on /jira refresh",
-			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
-			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
-			refresh: true,
-			body:    "/jira refresh",
-			options: JiraBranchOptions{AllowedSecurityLevels: []string{"internal"}},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in a security level that is not in the allowed security levels for this repo.
-Allowed security levels for this repo are:
-- internal
+		},
+		{
+			name:   "External bug on a repo configured for the bug's project is counted, bug is not moved while it is unmerged",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/unreferenced/repo/pull/22",
+				Title: "unreferenced/repo#22: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:           []github.PullRequest{{Number: 22, Merged: false, State: "open"}},
+			options:       JiraBranchOptions{StateAfterMerge: &modified, MergeRepos: map[string][]string{"OCPBUGS": {"unreferenced/repo"}}},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): Some pull requests linked via external trackers have merged:
+
+
+The following pull requests linked via external trackers have not merged:
+ * [unreferenced/repo#22](https://github.com/unreferenced/repo/pull/22) is open
+
+These pull request must merge or be unlinked from the Jira bug in order for it to move to the next state. Once unlinked, request a bug refresh with <code>/jira refresh</code>.
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has not been moved to the MODIFIED state.
 
 <details>
 
 In response to [this](https://github.com/org/repo/pull/1):
 
->/jira refresh
+>This PR fixes OCPBUGS-123
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-		}, {
-			name:    "Bug with non-allowed security level results in comment on PR creation",
-			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
-			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
-			opened:  true,
-			options: JiraBranchOptions{AllowedSecurityLevels: []string{"internal"}},
-			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in a security level that is not in the allowed security levels for this repo.
-Allowed security levels for this repo are:
-- internal
+		},
+		{
+			name:   "valid bug on merged PR with one external link but no status after merge configured does nothing",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:           []github.PullRequest{{Number: base.number, Merged: true}},
+			options:       JiraBranchOptions{}, // no requirements --> always valid
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+		},
+		{
+			name:    "valid bug on merged PR with one external link but no referenced bug in the title does nothing",
+			merged:  true,
+			missing: true,
+			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:           []github.PullRequest{{Number: base.number, Merged: true}},
+			options:       JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+		},
+		{
+			name:           "valid bug on merged PR with one external link fails to update bug and comments",
+			merged:         true,
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123"}},
+			issueGetErrors: map[string]error{"OCPBUGS-123": errors.New("injected error getting bug")},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
+			expectedComment: `org/repo#1:@user: An error was encountered searching for bug OCPBUGS-123 on the Jira server at https://my-jira.com. No known errors were detected, please see the full error message for details.
+
+<details><summary>Full error message.</summary>
+
+<code>
+injected error getting bug
+</code>
+
+</details>
+
+Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.
 
 <details>
 
@@ -2585,18 +2830,24 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-		}, {
-			name: "Bug with allowed group is properly handled",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{
-				"security":            jiraclient.SecurityLevel{Name: "security"},
-				helpers.SeverityField: severityModerate,
-			}}}},
-			options:        JiraBranchOptions{StateAfterValidation: &updated, AllowedSecurityLevels: []string{"security"}},
-			labels:         []string{labels.JiraInvalidBug},
-			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
-			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid. The bug has been moved to the UPDATED state.
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123"},
+		},
+		{
+			name:           "valid bug on merged PR whose external links cannot be read does not migrate to new state and comments",
+			merged:         true,
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			issueGetErrors: map[string]error{"1": errors.New("injected error getting remote links")},
+			prs:            []github.PullRequest{{Number: base.number, Merged: true}},
+			options:        JiraBranchOptions{StateAfterMerge: &modified}, // no requirements --> always valid
+			expectedComment: `org/repo#1:@user: Could not read the pull requests linked to [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), so it will not be moved to the MODIFIED state as it is unknown whether all of them have merged. Request a bug refresh with <code>/jira refresh</code> to try again.
 
-<details><summary>No validations were run on this bug</summary></details>
+<details><summary>Full error message.</summary>
+
+<code>
+Failed to get issue when chekcing from remote links: injected error getting remote links
+</code>
+
+</details>
 
 <details>
 
@@ -2607,452 +2858,4443 @@ In response to [this](https://github.com/org/repo/pull/1):
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
-			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
-				Unknowns: tcontainer.MarshalMap{
-					"security":            jiraclient.SecurityLevel{Name: "security"},
-					helpers.SeverityField: severityModerate,
-				}, Status: &jira.Status{Name: "UPDATED"},
-			}},
-		}, {
-			name: "Bug with dependent bug not in OCPBUGS is invalid",
-			issues: []jira.Issue{{ID: "1", Key: "OCPBUGSM-123", Fields: &jira.IssueFields{
-				Status:     &jira.Status{Name: "VERIFIED"},
-				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
-				Unknowns: tcontainer.MarshalMap{
-					helpers.TargetVersionField: &v2,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}},
+		},
+		{
+			name:   "valid bug on merged PR with merged external links but blocked status does not migrate to new state and comments",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEEDS INFO"},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
 				},
-			},
-			}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
-				Status: &jira.Status{Name: "MODIFIED"},
-				IssueLinks: []*jira.IssueLink{{
-					Type: jira.IssueLinkType{
-						Name:    "Blocks",
-						Inward:  "is blocked by",
-						Outward: "blocks",
-					},
-					InwardIssue: &jira.Issue{ID: "1", Key: "OCPBUGSM-123"},
-				}},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{ValidStates: &[]JiraBugState{{Status: "NEEDS INFO"}}, StateAfterMerge: &modified, BlockedStates: &[]JiraBugState{{Status: "NEEDS INFO"}}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in the NEEDS INFO state, which blocks it pending more information, and will not be moved to the MODIFIED state. Once the bug has been updated, request a bug refresh with <code>/jira refresh</code>.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEEDS INFO"},
+			}},
+		},
+		{
+			name:   "valid bug on merged PR with merged external links but unknown status does not migrate to new state and comments",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
 				Unknowns: tcontainer.MarshalMap{
-					helpers.TargetVersionField: &v1,
+					helpers.SeverityField: severityCritical,
 				},
 			}}},
-			overrideEvent: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-124", IsBug: true}}, body: "This PR fixes OCPBUGS-124", title: "OCPBUGS-124: fixed it!", htmlUrl: "https://github.com/org/repo/pull/2", login: "user",
-			},
-			existingIssueLinks: []*jira.IssueLink{{
-				Type: jira.IssueLinkType{
-					Name:    "Blocks",
-					Inward:  "is blocked by",
-					Outward: "blocks",
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
 				},
-				InwardIssue:  &jira.Issue{ID: "1", Key: "OCPBUGSM-123"},
-				OutwardIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124"},
 			}},
-			options:        JiraBranchOptions{IsOpen: &yes, TargetVersion: &v1Str, DependentBugStates: &verified, DependentBugTargetVersions: &[]string{v2Str}},
-			labels:         []string{},
-			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug},
-			expectedComment: `org/repo#2:@user: This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is invalid:
- - bug is open, matching expected state (open)
- - bug target version (v1) matches configured target version for branch (v1)
- - bug has dependents
- - dependent bug OCPBUGSM-123 is not in the required ` + "`OCPBUGS`" + ` project
-
-Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{StateAfterValidation: &updated, StateAfterMerge: &modified}, // no requirements --> always valid
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in an unrecognized state (CLOSED) and will not be moved to the MODIFIED state.
 
 <details>
 
-In response to [this](https://github.com/org/repo/pull/2):
+In response to [this](https://github.com/org/repo/pull/1):
 
->This PR fixes OCPBUGS-124
+>This PR fixes OCPBUGS-123
 
 
 Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
 </details>`,
+
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}},
 		},
-	}
+		{
+			name:   "closed PR removes link and comments",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{AddExternalLink: &yes},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker.
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			var ptrIssues []*jira.Issue
-			for index := range tc.issues {
-				ptrIssues = append(ptrIssues, &tc.issues[index])
-			}
-			jiraClient := &fakejira.FakeClient{
-				Issues:           ptrIssues,
-				ExistingLinks:    tc.remoteLinks,
-				GetIssueError:    tc.issueGetErrors,
-				CreateIssueError: tc.issueCreateErrors,
-				UpdateIssueError: tc.issueUpdateErrors,
-				Transitions:      jiraTransitions,
-			}
-			var testEvent event // copy so parallel tests don't collide
-			if tc.overrideEvent != nil {
-				testEvent = *tc.overrideEvent
-			} else {
-				testEvent = *base // copy so parallel tests don't collide
-			}
-			testEvent.refresh = tc.refresh
-			testEvent.missing = tc.missing
-			testEvent.merged = tc.merged
-			testEvent.closed = tc.closed || tc.merged
-			testEvent.opened = tc.opened
-			if tc.replaceReferencedBugs != nil {
-				newEvent := testEvent
-				newEvent.bugs = []referencedBug{}
-				testEvent.bugs = tc.replaceReferencedBugs
-			}
-			if tc.noJira {
-				testEvent.noJira = true
-				testEvent.bugs = nil
-			}
-			testEvent.cherrypick = tc.cherrypick
-			testEvent.cherrypickFromPRNum = tc.cherryPickFromPRNum
-			if tc.body != "" {
-				testEvent.body = tc.body
-			}
-			if tc.title != "" {
-				testEvent.title = tc.title
-			}
+<details>
 
-			gc := fakegithub.NewFakeClient()
-			gc.IssueLabelsExisting = []string{}
-			gc.IssueComments = map[int][]github.IssueComment{}
-			for key, comments := range tc.prComments {
-				gc.IssueComments[key] = comments
-			}
-			gc.PullRequests = map[int]*github.PullRequest{}
-			gc.WasLabelAddedByHumanVal = tc.humanLabelled
-			for _, label := range tc.labels {
-				gc.IssueLabelsExisting = append(gc.IssueLabelsExisting, fmt.Sprintf("%s/%s#%d:%s", testEvent.org, testEvent.repo, testEvent.number, label))
-			}
-			for _, pr := range tc.prs {
-				pr := pr
-				gc.PullRequests[pr.Number] = &pr
-			}
-			// the test-infra fake github client does not implement a Query function; we don't test the query functionality here, so we can just wrap the test-infra
-			// client with a custom one that has an empty Query function
-			// TODO: implement a basic fake query function in test-infra fakegithub library and start unit testing the query path
-			fakeClient := fakeGHClient{gc}
-			if err := handle(jiraClient, fakeClient, tc.options, logrus.WithField("testCase", tc.name), testEvent, sets.NewString("org/repo")); err != nil {
-				t.Fatalf("handle failed: %v", err)
-			}
+In response to [this](https://github.com/org/repo/pull/1):
 
-			if diff := cmp.Diff(jiraClient.NewLinks, tc.expectedNewRemoteLinks); diff != "" {
-				t.Errorf("new links differs from expected new links: %s", diff)
-			}
+>This PR fixes OCPBUGS-123
 
-			if diff := cmp.Diff(jiraClient.RemovedLinks, tc.expectedRemovedRemoteLinks); diff != "" {
-				t.Errorf("removed links differs from expected removed links: %s", diff)
-			}
 
-			if diff := cmp.Diff(gc.IssueCommentsEdited, tc.expectedCommentUpdates); diff != "" {
-				t.Errorf("comment updates differ from expected: %s", diff)
-			}
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}},
+			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			},
+		},
+		{
+			name:   "closed PR without a link does nothing",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{AddExternalLink: &yes},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}},
+		},
+		{
+			name:   "closed PR removes link, changes bug state, and comments",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Status: &jira.Status{Name: "POST"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the NEW state.
 
-			checkComments(gc, tc.name, tc.expectedComment, t)
+<details>
 
-			expected := sets.NewString()
-			for _, label := range tc.expectedLabels {
-				expected.Insert(fmt.Sprintf("%s/%s#%d:%s", testEvent.org, testEvent.repo, testEvent.number, label))
-			}
+In response to [this](https://github.com/org/repo/pull/1):
 
-			actual := sets.NewString(gc.IssueLabelsExisting...)
-			actual.Insert(gc.IssueLabelsAdded...)
-			actual.Delete(gc.IssueLabelsRemoved...)
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEW"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}, {
+					Body:       "Bug status changed to NEW as previous linked PR https://github.com/org/repo/pull/1 has been closed",
+					Visibility: PrivateVisibility,
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}},
+			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			},
+		},
+		{
+			name:   "closed PR with a resolution in the state after close notes the resolution",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Status: &jira.Status{Name: "POST"},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "CLOSED", Resolution: "NOTABUG"}},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the CLOSED (NOTABUG) state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "NOTABUG"},
+				Unknowns:   tcontainer.MarshalMap{},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}, {
+					Body:       "Bug status changed to CLOSED (NOTABUG) as previous linked PR https://github.com/org/repo/pull/1 has been closed",
+					Visibility: PrivateVisibility,
+				}}},
+			}},
+			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			},
+		},
+		{
+			name:   "closed PR of premerge bug removes link, changes bug state, and comments",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Status: &jira.Status{Name: "POST"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+				FixVersions:     []*jira.FixVersion{{Name: "premerge"}},
+				AffectsVersions: []*jira.AffectsVersion{{Name: "premerge"}},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			labels:         []string{labels.QEApproved},
+			expectedLabels: []string{labels.QEApproved},
+			prs:            []github.PullRequest{{Number: base.number, Merged: false}},
+			options:        JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}, PreMergeStateAfterClose: &JiraBugState{Status: "NEW2"}},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the NEW2 state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEW2"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}, {
+					Body:       "Bug status changed to NEW2 as previous linked PR https://github.com/org/repo/pull/1 has been closed",
+					Visibility: PrivateVisibility,
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+				FixVersions:     []*jira.FixVersion{{Name: "premerge"}},
+				AffectsVersions: []*jira.AffectsVersion{{Name: "premerge"}},
+			}},
+			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			},
+		},
+		{
+			name:                  "closed PR for multiple bugs removes links, changes bug states, and comments",
+			merged:                false,
+			closed:                true,
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Status: &jira.Status{Name: "POST"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is also a bug",
+				}}},
+				Status: &jira.Status{Name: "POST"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}, "OCPBUGS-124": {{ID: 2, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the NEW state.
+
+This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). The bug has been updated to no longer refer to the pull request using the external bug tracker. All external bug links have been closed. The bug has been moved to the NEW state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEW"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}, {
+					Body:       "Bug status changed to NEW as previous linked PR https://github.com/org/repo/pull/1 has been closed",
+					Visibility: PrivateVisibility,
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}},
+			expectedIssue2: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEW"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is also a bug",
+				}, {
+					Body:       "Bug status changed to NEW as previous linked PR https://github.com/org/repo/pull/1 has been closed",
+					Visibility: PrivateVisibility,
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}},
+			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}}, {ID: 2, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123,OCPBUGS-124: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}}},
+		},
+		{
+			name:    "closed PR with missing bug does nothing",
+			merged:  false,
+			closed:  true,
+			missing: true,
+			issues:  []jira.Issue{},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}},
+		},
+		{
+			name:   "closed PR with multiple external links removes link, does not change bug state, and comments",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "POST"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{
+				ID: 1,
+				Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/pull/1",
+					Title: "org/repo#1: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
+				},
+			}, {
+				ID: 2,
+				Object: &jira.RemoteLinkObject{
+					URL:   "https://github.com/org/repo/issues/42",
+					Title: "org/repo#42: OCPBUGS-123: fixed it!",
+					Icon: &jira.RemoteLinkIcon{
+						Url16x16: "https://github.com/favicon.ico",
+						Title:    "GitHub",
+					},
+				}},
+			}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{AddExternalLink: &yes, StateAfterClose: &JiraBugState{Status: "NEW"}},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "POST"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}},
+			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}},
+			},
+		},
+		{
+			name: "Cherrypick PR results in cloned bug creation",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+/retitle [v1] OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR on a branch with cloning disabled does not clone the bug and comments",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:  &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{Name: "OCPBUGS"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str, AllowCherrypickClone: &no},
+			expectedComment: `org/repo#1:@user: Automated cloning of bugs for cherrypicks is disabled for the branch branch in the jira plugin config, so no bugs have been cloned. Clone the bugs manually and reference the clones in the title of this pull request instead.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:  &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{Name: "OCPBUGS"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v2,
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR with a clone target project results in cloned bug creation in that project",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str, CloneTargetProject: &backportProject},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue BACKPORTS-1](https://my-jira.com/browse/BACKPORTS-1). Will retitle bug to link to clone.
+/retitle [v1] BACKPORTS-1: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "BACKPORTS-1", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Key:  "BACKPORTS",
+					Name: "BACKPORTS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR with blocks link disabled results in cloned bug with only the clone link",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str, CreateBlocksLinkOnClone: &no},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+/retitle [v1] OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR of a bug with a short description warns that the clone inherits it",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Description: "Crashes.",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str, MinParentDescriptionLength: &minDescriptionLength},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+
+WARNING: The description of [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is only 8 characters long, which is shorter than the expected minimum of 20 characters, and [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) inherits it. Please document the bug so that the backport can be understood on its own.
+/retitle [v1] OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\nCrashes.",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR with a custom retitle command uses it to retitle the PR",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str, RetitleCommand: &retitleCommand},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+/bot retitle [v1] OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR results in cloned bug with the parent's components",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Components: []*jira.Component{{Name: "Networking"}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+/retitle [v1] OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Components: []*jira.Component{{Name: "Networking"}},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR for multiple bugs results in multiple cloned bug creation",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
+			prs:                   []github.PullRequest{{Number: base.number, Body: base.body, Title: "OCPBUGS-123,OCPBUGS-124: fixed it!"}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] OCPBUGS-123,OCPBUGS-124: fixed it!"}},
+			title:                 "[v1] OCPBUGS-123,OCPBUGS-124: fixed it!",
+			cherrypick:            true,
+			cherryPickFromPRNum:   1,
+			options:               JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125). Will retitle bug to link to clone.
+
+[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been cloned as [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126). Will retitle bug to link to clone.
+/retitle [v1] OCPBUGS-125,OCPBUGS-126: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+			expectedIssue2: &jira.Issue{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-124. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{{
+					Type: jira.IssueLinkType{
+						Name:    "Cloners",
+						Inward:  "is cloned by",
+						Outward: "clones",
+					},
+					OutwardIssue: &jira.Issue{ID: "2"},
+				}, {
+					Type: jira.IssueLinkType{
+						Name:    "Blocks",
+						Inward:  "is blocked by",
+						Outward: "blocks",
+					},
+					InwardIssue: &jira.Issue{ID: "2"},
+				}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR for multiple bugs with 1 failure still comments for both bugs",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
+			issueUpdateErrors:     map[string]error{"OCPBUGS-125": errors.New("injected error updating bug OCPBUGS-125")},
+			prs:                   []github.PullRequest{{Number: base.number, Body: base.body, Title: "OCPBUGS-123,OCPBUGS-124: fixed it!"}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] OCPBUGS-123,OCPBUGS-124: fixed it!"}},
+			title:                 "[v1] OCPBUGS-123,OCPBUGS-124: fixed it!",
+			cherrypick:            true,
+			cherryPickFromPRNum:   1,
+			options:               JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125). Will retitle bug to link to clone.
+
+WARNING: Failed to update the target version for the clone. Please update the target version manually. Full error below:
+<details><summary>Full error message.</summary>
+
+<code>
+injected error updating bug OCPBUGS-125
+</code>
+
+</details>
+
+[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been cloned as [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126). Will retitle bug to link to clone.
+/retitle [v1] OCPBUGS-125,OCPBUGS-126: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v2Str}},
+				},
+			}},
+			expectedIssue2: &jira.Issue{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-124. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{{
+					Type: jira.IssueLinkType{
+						Name:    "Cloners",
+						Inward:  "is cloned by",
+						Outward: "clones",
+					},
+					OutwardIssue: &jira.Issue{ID: "2"},
+				}, {
+					Type: jira.IssueLinkType{
+						Name:    "Blocks",
+						Inward:  "is blocked by",
+						Outward: "blocks",
+					},
+					InwardIssue: &jira.Issue{ID: "2"},
+				}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick comment results in cloned bug creation",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs: []github.PullRequest{{Number: 2, Body: "This is a manually created cherrypick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira cherrypick OCPBUGS-123", title: "fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", cherrypick: true, cherrypickCmd: true, missing: true,
+			},
+			cherrypick: true,
+			missing:    true,
+			options:    JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#2:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+/retitle OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira cherrypick OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick comment for a bug other than the one in the title is rejected",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs: []github.PullRequest{{Number: 2, Body: "This is a manually created cherrypick of #1.\n\n/assign user", Title: "[v1] OCPBUGS-100: fixed it!"}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira cherrypick OCPBUGS-123", title: "[v1] OCPBUGS-100: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", cherrypick: true, cherrypickCmd: true, titleBugs: []referencedBug{{Key: "OCPBUGS-100", IsBug: true}},
+			},
+			cherrypick: true,
+			options:    JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#2:@user: The title of this pull request already references OCPBUGS-100, which is not one of the bugs given to <code>/jira cherrypick</code> (OCPBUGS-123), so no bugs have been cherrypicked. To cherrypick a different bug, remove the existing bug reference from the pull request title and run the command again.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira cherrypick OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name: "Cherrypick comment for a bug other than the one in the title takes precedence when configured",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs: []github.PullRequest{{Number: 2, Body: "This is a manually created cherrypick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira cherrypick OCPBUGS-123", title: "[v1] OCPBUGS-100: fixed it!", titleBugs: []referencedBug{{Key: "OCPBUGS-100", IsBug: true}}, htmlUrl: "https://github.com/org/repo/pull/1", login: "user", cherrypick: true, cherrypickCmd: true,
+			},
+			cherrypick: true,
+			missing:    true,
+			options:    JiraBranchOptions{TargetVersion: &v1Str, CherrypickCommandOverridesTitle: &yes},
+			expectedComment: `org/repo#2:@user: The title of this pull request references OCPBUGS-100, but the bugs given to <code>/jira cherrypick</code> take precedence, so OCPBUGS-123 will be cherrypicked instead.
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+/retitle OCPBUGS-124: [v1] fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira cherrypick OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick comment with branches clones the bug for the target version of each branch",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs: []github.PullRequest{{Number: 2, Body: "This is a manually created cherrypick of #1.\n\n/assign user", Title: "[v1] fixing stuff"}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira cherrypick OCPBUGS-123 --branches branch,release-v3,release-unknown", title: "fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", cherrypick: true, cherrypickCmd: true, missing: true,
+				cherrypickBranches:       []string{"branch", "release-v3", "release-unknown"},
+				cherrypickTargetVersions: map[string]string{"branch": v1Str, "release-v3": "v3"},
+			},
+			cherrypick: true,
+			missing:    true,
+			options:    JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#2:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) for the branch branch.
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125) for the release-v3 branch.
+
+Could not clone [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) for the release-unknown branch as the target version is not set for that branch in the jira plugin config.
+/retitle OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira cherrypick OCPBUGS-123 --branches branch,release-v3,release-unknown
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				// the fake client copies the links of the bug, which by now include the links to the first clone
+				IssueLinks: []*jira.IssueLink{{
+					Type:        jira.IssueLinkType{Name: "Cloners", Inward: "is cloned by", Outward: "clones"},
+					InwardIssue: &jira.Issue{ID: "2"},
+				}, {
+					Type:         jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+					OutwardIssue: &jira.Issue{ID: "2"},
+				}, {
+					Type:         jira.IssueLinkType{Name: "Cloners", Inward: "is cloned by", Outward: "clones"},
+					OutwardIssue: &jira.Issue{ID: "1"},
+				}, {
+					Type:        jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+					InwardIssue: &jira.Issue{ID: "1"},
+				}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": "v3"}},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick comment for multiple bugs results in multiple cloned bug creation",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}},
+			prs:                   []github.PullRequest{{Number: 2, Body: "This is a manually created cherrypick of #1.\n\n/assign user", Title: "[v1] fixing stuff"}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}}, body: "/jira cherrypick OCPBUGS-123,OCPBUGS-124", title: "fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", cherrypick: true, cherrypickCmd: true, missing: true,
+			},
+			cherrypick: true,
+			missing:    true,
+			options:    JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#2:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125). Will retitle bug to link to clone.
+
+[Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) has been cloned as [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126). Will retitle bug to link to clone.
+/retitle OCPBUGS-125,OCPBUGS-126: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira cherrypick OCPBUGS-123,OCPBUGS-124
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+			expectedIssue2: &jira.Issue{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-124. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{{
+					Type: jira.IssueLinkType{
+						Name:    "Cloners",
+						Inward:  "is cloned by",
+						Outward: "clones",
+					},
+					OutwardIssue: &jira.Issue{ID: "2"},
+				}, {
+					Type: jira.IssueLinkType{
+						Name:    "Blocks",
+						Inward:  "is blocked by",
+						Outward: "blocks",
+					},
+					InwardIssue: &jira.Issue{ID: "2"},
+				}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		},
+		{
+			name: "parent PR of cherrypick not existing results in error",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: Error creating a cherry-pick bug in Jira: failed to check the state of cherrypicked pull request at https://github.com/org/repo/pull/1: pull request number 1 does not exist.
+Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name: "failure to obtain parent bug for cherrypick results in error",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			issueGetErrors:      map[string]error{"OCPBUGS-123": errors.New("injected error getting bug")},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: An error was encountered searching for bug OCPBUGS-123 on the Jira server at https://my-jira.com. No known errors were detected, please see the full error message for details.
+
+<details><summary>Full error message.</summary>
+
+<code>
+injected error getting bug
+</code>
+
+</details>
+
+Please contact an administrator to resolve this issue, then request a bug refresh with <code>/jira refresh</code>.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name: "failure to update bug retitles the PR and prints a warning in the comment",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}},
+			issueUpdateErrors:   map[string]error{"OCPBUGS-124": errors.New("injected error updating bug OCPBUGS-124")},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+
+WARNING: Failed to update the target version for the clone. Please update the target version manually. Full error below:
+<details><summary>Full error message.</summary>
+
+<code>
+injected error updating bug OCPBUGS-124
+</code>
+
+</details>
+/retitle [v1] OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name: "If bug clone with correct target version already exists, just retitle PR",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
+				Status:     &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
+				Status:     &jira.Status{Name: "NEW"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v1,
+				},
+			}},
+			},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: Detected clone of [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) with correct target version. Will retitle the PR to link to the clone.
+/retitle [v1] OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name: "If clone with correct target version already exists in multibug PR, retitle PR for correct clone and create clone for other bug",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
+				Status:     &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo123, &blocksLinkTo123},
+				Status:     &jira.Status{Name: "NEW"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v1,
+				},
+			}}, {ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v1,
+				},
+			}},
+			},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: "OCPBUGS-123,OCPBUGS-125: fixed it!"}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] OCPBUGS-123,OCPBUGS-125: fixed it!"}},
+			title:               "[v1] OCPBUGS-123,OCPBUGS-125: fixed it!",
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: Detected clone of [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) with correct target version. Will retitle the PR to link to the clone.
+
+[Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125) has been cloned as [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126). Will retitle bug to link to clone.
+/retitle [v1] OCPBUGS-124,OCPBUGS-126: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "4", Key: "OCPBUGS-126", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-125. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				IssueLinks: []*jira.IssueLink{{
+					Type: jira.IssueLinkType{
+						Name:    "Cloners",
+						Inward:  "is cloned by",
+						Outward: "clones",
+					},
+					OutwardIssue: &jira.Issue{ID: "3"},
+				}, {
+					Type: jira.IssueLinkType{
+						Name:    "Blocks",
+						Inward:  "is blocked by",
+						Outward: "blocks",
+					},
+					InwardIssue: &jira.Issue{ID: "3"},
+				}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		}, {
+			name: "Clone for different version does not block creation of new clone",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v2,
+				},
+			}}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEW"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      severityCritical,
+					helpers.TargetVersionField: &v3,
+				},
+			}},
+			},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125). Will retitle bug to link to clone.
+/retitle [v1] OCPBUGS-125: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "3", Key: "OCPBUGS-125", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"}, // during a clone on a real jira server, this field would get unset/reset; the fake client copies
+				IssueLinks:  []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body: "This is a bug",
+				}}},
+				Project: jira.Project{
+					Name: "OCPBUGS",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField:      map[string]interface{}{"Value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+				},
+			}},
+		}, {
+			name:    "Bug with non-allowed security level is ignored",
+			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
+			options: JiraBranchOptions{AllowedSecurityLevels: []string{"internal"}},
+			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
+			// there should be no comment returned in this test case
+		}, {
+			name:           "Bug with security level on repo with no allowed security levels results in comment on /jira refresh",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
+			prs:            []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
+			refresh:        true,
+			body:           "/jira refresh",
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name: "Bug matching previous bot comment still comments on /jira refresh with no changes",
+			prComments: map[int][]github.IssueComment{1: {{Body: "Hello", User: github.User{Login: "alex"}}, {Body: `@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`, User: github.User{Login: fakegithub.Bot}}}},
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
+			prs:            []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
+			refresh:        true,
+			body:           "/jira refresh",
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name:    "Bug with non-allowed security level results in comment on /jira refresh",
+			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
+			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
+			refresh: true,
+			body:    "/jira refresh",
+			options: JiraBranchOptions{AllowedSecurityLevels: []string{"internal"}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in a security level that is not in the allowed security levels for this repo.
+Allowed security levels for this repo are:
+- internal
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name:    "Bug with denied security level results in comment on /jira refresh",
+			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "embargoed"}}}}},
+			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
+			refresh: true,
+			body:    "/jira refresh",
+			options: JiraBranchOptions{AllowedSecurityLevels: []string{"embargoed", "internal"}, DeniedSecurityLevels: []string{"embargoed"}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in a security level that is denied for this repo.
+Denied security levels for this repo are:
+- embargoed
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name:    "Bug with non-allowed security level cc's the configured security team",
+			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
+			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
+			refresh: true,
+			body:    "/jira refresh",
+			options: JiraBranchOptions{AllowedSecurityLevels: []string{"internal"}, SecurityTeam: &securityTeam},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in a security level that is not in the allowed security levels for this repo.
+Allowed security levels for this repo are:
+- internal
+
+Requesting triage of the access to the bug from the security team:
+/cc @org/security-team
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name:    "Bug with denied security level cc's the configured security team",
+			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "embargoed"}}}}},
+			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
+			refresh: true,
+			body:    "/jira refresh",
+			options: JiraBranchOptions{DeniedSecurityLevels: []string{"embargoed"}, SecurityTeam: &securityTeam},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in a security level that is denied for this repo.
+Denied security levels for this repo are:
+- embargoed
+
+Requesting triage of the access to the bug from the security team:
+/cc @org/security-team
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name:    "Bug with non-allowed security level results in comment on PR creation",
+			issues:  []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"security": jiraclient.SecurityLevel{Name: "security"}}}}},
+			prs:     []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}},
+			opened:  true,
+			options: JiraBranchOptions{AllowedSecurityLevels: []string{"internal"}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is in a security level that is not in the allowed security levels for this repo.
+Allowed security levels for this repo are:
+- internal
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		}, {
+			name: "Bug with allowed group is properly handled",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{
+				"security":            jiraclient.SecurityLevel{Name: "security"},
+				helpers.SeverityField: severityModerate,
+			}}}},
+			options:        JiraBranchOptions{StateAfterValidation: &updated, AllowedSecurityLevels: []string{"security"}},
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid. The bug has been moved to the UPDATED state.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					"security":            jiraclient.SecurityLevel{Name: "security"},
+					helpers.SeverityField: severityModerate,
+				}, Status: &jira.Status{Name: "UPDATED"},
+			}},
+		}, {
+			name: "Bug with allowed group does not cc the configured security team",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{
+				"security":            jiraclient.SecurityLevel{Name: "security"},
+				helpers.SeverityField: severityModerate,
+			}}}},
+			options:        JiraBranchOptions{StateAfterValidation: &updated, AllowedSecurityLevels: []string{"security"}, SecurityTeam: &securityTeam},
+			labels:         []string{labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid. The bug has been moved to the UPDATED state.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					"security":            jiraclient.SecurityLevel{Name: "security"},
+					helpers.SeverityField: severityModerate,
+				}, Status: &jira.Status{Name: "UPDATED"},
+			}},
+		}, {
+			name: "Bug with dependent bug not in OCPBUGS is invalid",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGSM-123", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "VERIFIED"},
+				IssueLinks: []*jira.IssueLink{&cloneLinkTo124, &blocksLinkTo124},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v2,
+				},
+			},
+			}, {ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "MODIFIED"},
+				IssueLinks: []*jira.IssueLink{{
+					Type: jira.IssueLinkType{
+						Name:    "Blocks",
+						Inward:  "is blocked by",
+						Outward: "blocks",
+					},
+					InwardIssue: &jira.Issue{ID: "1", Key: "OCPBUGSM-123"},
+				}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v1,
+				},
+			}}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 2, bugs: []referencedBug{{Key: "OCPBUGS-124", IsBug: true}}, body: "This PR fixes OCPBUGS-124", title: "OCPBUGS-124: fixed it!", htmlUrl: "https://github.com/org/repo/pull/2", login: "user",
+			},
+			existingIssueLinks: []*jira.IssueLink{{
+				Type: jira.IssueLinkType{
+					Name:    "Blocks",
+					Inward:  "is blocked by",
+					Outward: "blocks",
+				},
+				InwardIssue:  &jira.Issue{ID: "1", Key: "OCPBUGSM-123"},
+				OutwardIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124"},
+			}},
+			options:        JiraBranchOptions{IsOpen: &yes, TargetVersion: &v1Str, DependentBugStates: &verified, DependentBugTargetVersions: &[]string{v2Str}},
+			labels:         []string{},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug},
+			expectedComment: `org/repo#2:@user: This pull request references [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124), which is invalid:
+ - bug is open, matching expected state (open)
+ - bug target version (v1) matches configured target version for branch (v1)
+ - bug has dependents
+ - dependent bug OCPBUGSM-123 is not in the required ` + "`OCPBUGS`" + ` project
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/2):
+
+>This PR fixes OCPBUGS-124
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name: "valid bug with multiple target versions warns about the ambiguity",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &[]*jira.Version{{Name: v1Str}, {Name: v2Str}},
+				},
+			}}},
+			options:        JiraBranchOptions{TargetVersion: &v1Str},
+			labels:         []string{},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>1 validation was run on this bug</summary>
+
+* bug target version (v1) matches configured target version for branch (v1)</details>
+
+Warning: The referenced bug has multiple target versions set (v1, v2). This usually indicates a data-entry error; please make sure that only one target version is set.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "verified bug on an open PR warns about the suspicious state",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "VERIFIED"}}}},
+			options:        JiraBranchOptions{SuspiciousStates: &[]JiraBugState{{Status: "VERIFIED"}, {Status: "CLOSED", Resolution: "ERRATA"}}},
+			labels:         []string{},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+Warning: The referenced bug is in the VERIFIED state although this pull request has not merged yet. Please make sure that the bug is not tracking a fix that is still pending.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:   "PR opened on a release branch referencing a bug directly recommends the cherrypick flow",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			opened: true,
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "release-4.10", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+			},
+			options:        JiraBranchOptions{RecommendCherrypick: &yes},
+			labels:         []string{},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+This pull request references a bug directly instead of being created through the cherrypick flow. Fixes for release branches are usually backported by commenting <code>/cherrypick release-4.10</code> on the pull request that fixed the bug on the main branch, which clones the bug for this branch and references the clone. Please make sure that the backport process has been followed.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "QA contact without an email is resolved through the configured account ID mapping",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}, Unknowns: tcontainer.MarshalMap{helpers.QAContactField: &jira.User{AccountID: "557058:qa"}}}}},
+			options:        JiraBranchOptions{AccountIDLogins: map[string]string{"557058:qa": "qa-tester"}},
+			labels:         []string{},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+Requesting review from QA contact:
+/cc @qa-tester
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "QA contact whose email matches no GitHub user falls back to the configured account ID mapping",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}, Unknowns: tcontainer.MarshalMap{helpers.QAContactField: &jira.User{AccountID: "557058:qa", EmailAddress: "qa@example.com"}}}}},
+			options:        JiraBranchOptions{AccountIDLogins: map[string]string{"557058:qa": "qa-tester"}},
+			labels:         []string{},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+Requesting review from QA contact:
+/cc @qa-tester
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name: "valid bug with an incomplete subtask warns that the fix may be partial",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEW"},
+				Subtasks: []*jira.Subtasks{
+					{Key: "OCPBUGS-124", Fields: jira.IssueFields{Status: &jira.Status{Name: "Done", StatusCategory: jira.StatusCategory{Key: jira.StatusCategoryComplete}}}},
+					{Key: "OCPBUGS-125", Fields: jira.IssueFields{Status: &jira.Status{Name: "In Progress", StatusCategory: jira.StatusCategory{Key: jira.StatusCategoryInProgress}}}},
+					{Key: "OCPBUGS-126"},
+				},
+			}}},
+			options:        JiraBranchOptions{WarnOnIncompleteSubtasks: &yes},
+			labels:         []string{},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+Warning: The referenced bug has subtasks that are not done yet, so the fix may only be partial:
+ * [Jira Issue OCPBUGS-125](https://my-jira.com/browse/OCPBUGS-125) is in the In Progress state
+ * [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126) is in the unknown state
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:   "title change without changing the referenced bug updates the title of the existing remote link",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			remoteLinks: map[string][]jira.RemoteLink{"1": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it! (WIP)",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}}}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", titleChanged: true,
+			},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedNewRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}}},
+		},
+		{
+			name:           "refresh with severity change reporting records the severity of the bugs",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityModerate}}}},
+			refresh:        true,
+			body:           "/jira refresh",
+			options:        JiraBranchOptions{ReportSeverityChanges: &yes},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+<!-- jira-lifecycle-severities: OCPBUGS-123=Moderate -->
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "refresh with severity change reporting notes that the severity changed since the last refresh",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			refresh:        true,
+			body:           "/jira refresh",
+			options:        JiraBranchOptions{ReportSeverityChanges: &yes},
+			prComments:     map[int][]github.IssueComment{1: {{Body: "org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.\n\n<details><summary>No validations were run on this bug</summary></details>\n<!-- jira-lifecycle-severities: OCPBUGS-123=Moderate -->", User: github.User{Login: fakegithub.Bot}}, {Body: "/jira refresh", User: github.User{Login: "user"}}}},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityModerate},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+The severity of [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) changed from Moderate to Critical since it was last validated.
+<!-- jira-lifecycle-severities: OCPBUGS-123=Critical -->
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "valid bug with more validations than the maximum listed truncates the list",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}, Labels: []string{"triaged"}, Environment: "AWS"}}},
+			options:        JiraBranchOptions{IsOpen: &open, RequiredLabels: []string{"triaged"}, RequireEnvironmentField: &yes, MaxListedValidations: &one},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>3 validations were run on this bug</summary>
+
+* bug is open, matching expected state (open)
+* ...and 2 more</details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "invalid bug with more reasons than the maximum listed truncates the list",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "CLOSED"}}}},
+			options:        JiraBranchOptions{IsOpen: &open, RequiredLabels: []string{"triaged"}, RequireEnvironmentField: &yes, MaxListedValidations: &one},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:
+ - expected the bug to be open, but it isn't
+ - ...and 2 more
+
+Comment <code>/jira refresh</code> to re-evaluate validity if changes to the Jira bug are made, or edit the title of this pull request to link to a different bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "fix-version-from-branch sets the target version of the branch and re-validates the bug",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.TargetVersionField: &v2}}}},
+			options:        JiraBranchOptions{TargetVersion: &v1Str},
+			labels:         []string{labels.JiraValidRef, labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira fix-version-from-branch", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", fixVersion: true,
+			},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>1 validation was run on this bug</summary>
+
+* bug target version (v1) matches configured target version for branch (v1)</details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira fix-version-from-branch
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "fix-version-from-branch without a target version configured for the branch comments",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.TargetVersionField: &v2}}}},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira fix-version-from-branch", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", fixVersion: true,
+			},
+			expectedComment: `org/repo#1:@user: No target version is configured for the branch branch, so the target version of the referenced bugs cannot be derived from it.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira fix-version-from-branch
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:   "synchronize prunes stale links to commits of the pull request and links the pull request",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			remoteLinks: map[string][]jira.RemoteLink{"1": {
+				{ID: 1, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/1/commits/0123456789abcdef", Title: "org/repo#1: OCPBUGS-123: fixed it!"}},
+				{ID: 2, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/2/commits/fedcba9876543210", Title: "org/repo#2: OCPBUGS-123: fixed it too!"}},
+			}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", synchronized: true,
+			},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedRemovedRemoteLinks: []jira.RemoteLink{
+				{ID: 1, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/1/commits/0123456789abcdef", Title: "org/repo#1: OCPBUGS-123: fixed it!"}},
+			},
+			expectedNewRemoteLinks: []jira.RemoteLink{{Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+				Icon: &jira.RemoteLinkIcon{
+					Url16x16: "https://github.com/favicon.ico",
+					Title:    "GitHub",
+				},
+			}}},
+		},
+		{
+			name:   "synchronize without stale links does not link the pull request unless configured to",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", synchronized: true,
+			},
+			labels:         []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+		},
+		{
+			name:   "synchronize with a key of a bug that does not exist does not comment",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-404", IsBug: true}}, state: "open", body: "This PR fixes OCPBUGS-404", title: "OCPBUGS-404: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user", synchronized: true,
+			},
+			options:        JiraBranchOptions{AddExternalLink: &yes},
+			labels:         []string{labels.JiraValidRef, labels.JiraInvalidBug},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraInvalidBug},
+		},
+		{
+			name:   "merged revert moves the bug whose fix was reverted to the state after revert",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}}},
+			merged: true,
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "closed", body: "Reverts #2", title: `Revert "OCPBUGS-123: fixed it!"`, htmlUrl: "https://github.com/org/repo/pull/1", login: "user", revert: true,
+			},
+			options: JiraBranchOptions{StateAfterMerge: &modified, StateAfterRevert: &JiraBugState{Status: "NEW"}},
+			expectedComment: `org/repo#1:@user: This pull request reverts the fix for [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which has been moved to the NEW state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>Reverts #2
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:   &jira.Status{Name: "NEW"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{Body: "Bug status changed to NEW as its fix was reverted by https://github.com/org/repo/pull/1", Visibility: PrivateVisibility}}},
+			}},
+		},
+		{
+			name:   "opened revert does not move the bug whose fix it reverts to the state after validation",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}}},
+			opened: true,
+			overrideEvent: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "Reverts #2", title: `Revert "OCPBUGS-123: fixed it!"`, htmlUrl: "https://github.com/org/repo/pull/1", login: "user", revert: true,
+			},
+			options:        JiraBranchOptions{StateAfterValidation: &updated, StateAfterRevert: &JiraBugState{Status: "NEW"}},
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>Reverts #2
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+		},
+		{
+			name:   "closed PR removes link and comments on the bug when configured",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+			}}}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{AddExternalLink: &yes, CommentOnLinkRemoval: &yes},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123). The bug has been updated to no longer refer to the pull request using the external bug tracker.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "CLOSED"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body:       "Link to PR https://github.com/org/repo/pull/1 removed as the PR has been closed without merging",
+					Visibility: PrivateVisibility,
+				}}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.SeverityField: severityCritical,
+				},
+			}},
+			expectedRemovedRemoteLinks: []jira.RemoteLink{{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+			}}},
+		},
+		{
+			name:   "valid bug on merged PR is not migrated when it is not in one of the expected board columns",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{ID: "1", Name: "MODIFIED"},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+			}}}},
+			prs:          []github.PullRequest{{Number: base.number, Merged: true}},
+			boardColumns: map[int]map[string]string{board: {"1": "Backlog", "2": "In Progress", "3": "In Review"}},
+			options:      JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}, RequiredBoardID: &board, MergeBoardColumns: &[]string{"In Progress", "In Review"}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+The bug is in the Backlog column of board 42, but it is expected to be in one of the following columns: In Progress, In Review.
+
+The bug will not be moved to the CLOSED (MERGED) state until it is moved to one of the expected columns. Once it is, request a bug refresh with <code>/jira refresh</code>.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{ID: "1", Name: "MODIFIED"},
+			}},
+		},
+		{
+			name:   "valid bug on merged PR is migrated when it is in one of the expected board columns",
+			merged: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{ID: "3", Name: "MODIFIED"},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+			}}}},
+			prs:          []github.PullRequest{{Number: base.number, Merged: true}},
+			boardColumns: map[int]map[string]string{board: {"1": "Backlog", "2": "In Progress", "3": "In Review"}},
+			options:      JiraBranchOptions{StateAfterMerge: &JiraBugState{Status: "CLOSED", Resolution: "MERGED"}, RequiredBoardID: &board, MergeBoardColumns: &[]string{"In Progress", "In Review"}},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the CLOSED (MERGED) state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "MERGED"},
+				Unknowns:   tcontainer.MarshalMap{},
+			}},
+		},
+		{
+			name:   "PR closed without merging moves the bug to the state after an unmerged close",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "POST"},
+			}}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}},
+			options: JiraBranchOptions{StateAfterCloseUnmerged: &JiraBugState{Status: "NEW"}},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) and was closed without merging. The bug has been moved to the NEW state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "NEW"},
+				Comments: &jira.Comments{Comments: []*jira.Comment{{
+					Body:       "Bug status changed to NEW as linked PR https://github.com/org/repo/pull/1 has been closed without merging",
+					Visibility: PrivateVisibility,
+				}}},
+			}},
+		},
+		{
+			name:   "PR closed without merging that is superseded by another linked PR does not move the bug",
+			merged: false,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "POST"},
+			}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/2",
+				Title: "org/repo#2: OCPBUGS-123: fixed it again!",
+			}}}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: false}, {Number: 2, State: github.PullRequestStateOpen}},
+			options: JiraBranchOptions{StateAfterCloseUnmerged: &JiraBugState{Status: "NEW"}},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) and was closed without merging, but it is superseded by [org/repo#2](https://github.com/org/repo/pull/2), so the bug has not been moved to the NEW state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "POST"},
+			}},
+		},
+		{
+			name:   "merged PR that is closed does not move the bug to the state after an unmerged close",
+			merged: true,
+			closed: true,
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "POST"},
+			}}},
+			prs:     []github.PullRequest{{Number: base.number, Merged: true}},
+			options: JiraBranchOptions{StateAfterCloseUnmerged: &JiraBugState{Status: "NEW"}},
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status: &jira.Status{Name: "POST"},
+			}},
+		},
+		{
+			name: "Cherrypick PR for a bug that is not ready for backport does not clone the bug and comments",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:  &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{Name: "OCPBUGS"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v2,
+					backportReadyField:         map[string]interface{}{"value": "No"},
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str, RequireBackportReady: &yes, BackportReadyField: &backportReadyField},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is not marked as ready for backport, so it has not been cloned. Once it is, clone it with <code>/jira cherrypick OCPBUGS-123</code>.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:  &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{Name: "OCPBUGS"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v2,
+					backportReadyField:         map[string]interface{}{"value": "No"},
+				},
+			}},
+		},
+		{
+			name: "Cherrypick PR for a bug that is ready for backport results in cloned bug creation",
+			issues: []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Status:  &jira.Status{Name: "CLOSED"},
+				Project: jira.Project{Name: "OCPBUGS"},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &v2,
+					backportReadyField:         map[string]interface{}{"value": "Yes"},
+				},
+			}}},
+			prs:                 []github.PullRequest{{Number: base.number, Body: base.body, Title: base.title}, {Number: 2, Body: "This is an automated cherry-pick of #1.\n\n/assign user", Title: "[v1] " + base.title}},
+			title:               "[v1] " + base.title,
+			cherrypick:          true,
+			cherryPickFromPRNum: 1,
+			options:             JiraBranchOptions{TargetVersion: &v1Str, RequireBackportReady: &yes, BackportReadyField: &backportReadyField},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been cloned as [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124). Will retitle bug to link to clone.
+/retitle [v1] OCPBUGS-124: fixed it!
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "2", Key: "OCPBUGS-124", Fields: &jira.IssueFields{
+				Description: "This is a clone of issue OCPBUGS-123. The following is the description of the original issue: \n---\n",
+				Status:      &jira.Status{Name: "CLOSED"},
+				Project:     jira.Project{Name: "OCPBUGS"},
+				IssueLinks:  []*jira.IssueLink{&cloneLinkTo123JustID, &blocksLinkTo123JustID},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: []interface{}{map[string]interface{}{"name": v1Str}},
+					backportReadyField:         map[string]interface{}{"value": "Yes"},
+				},
+			}},
+		},
+		{
+			name:           "valid bug whose configured requirements do not apply notes that no validations applied",
+			issues:         []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.SeverityField: severityCritical}}}},
+			options:        JiraBranchOptions{RequireDependentsResolved: &yes}, // no dependents --> nothing to check
+			expectedLabels: []string{labels.JiraValidRef, labels.JiraValidBug, labels.SeverityCritical},
+			expectedComment: `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations applied to this bug</summary>
+
+The requirements configured for this branch did not apply to this bug, so none of them were checked.</details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:          "simulate-merge by org member moves the bug of an unmerged PR to the state after merge and labels the comment as a simulation",
+			simulateMerge: true,
+			orgMembers:    []string{"user"},
+			body:          "/jira simulate-merge",
+			issues:        []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+			}}}},
+			options: JiraBranchOptions{StateAfterMerge: &modified},
+			expectedComment: `org/repo#1:@user: This is a simulation of the merge of this pull request, which has not actually merged. The bugs were handled as they would have been on merge:
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira simulate-merge
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+		},
+		{
+			name:          "simulate-merge by a user who is not an org member is rejected",
+			simulateMerge: true,
+			body:          "/jira simulate-merge",
+			issues:        []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			options:       JiraBranchOptions{StateAfterMerge: &modified},
+			expectedComment: `org/repo#1:@user: Only members of the org organization can simulate merges with <code>/jira simulate-merge</code>.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira simulate-merge
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}},
+		},
+		{
+			name:          "simulate-merge without a state after merge explains that there is nothing to simulate",
+			simulateMerge: true,
+			orgMembers:    []string{"user"},
+			body:          "/jira simulate-merge",
+			issues:        []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			expectedComment: `org/repo#1:@user: No state after merge is configured for the branch branch, so a merge of this pull request would not move any bug.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>/jira simulate-merge
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:                  "merged PR referencing tracked and untracked issues moves the tracked bug",
+			merged:                true,
+			replaceReferencedBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "JIRA-123", IsBug: false}},
+			issues: []jira.Issue{
+				{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}},
+				{ID: "2", Key: "JIRA-123", Fields: &jira.IssueFields{}},
+			},
+			trackedProjects: []string{"OCPBUGS"},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+			}}}},
+			prs:     []github.PullRequest{{Number: 1, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &modified},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+		},
+		{
+			name:      "merged PR from an exempt bot moves the bug to the state after merge",
+			merged:    true,
+			exemptBot: true,
+			issues:    []jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "POST"}}}},
+			remoteLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": {{ID: 1, Object: &jira.RemoteLinkObject{
+				URL:   "https://github.com/org/repo/pull/1",
+				Title: "org/repo#1: OCPBUGS-123: fixed it!",
+			}}}},
+			prs:     []github.PullRequest{{Number: 1, Merged: true}},
+			options: JiraBranchOptions{StateAfterMerge: &modified},
+			expectedComment: `org/repo#1:@user: [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123): All pull requests linked via external trackers have merged:
+ * [org/repo#1](https://github.com/org/repo/pull/1)
+
+[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) has been moved to the MODIFIED state.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+			expectedIssue: &jira.Issue{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "MODIFIED"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ptrIssues []*jira.Issue
+			for index := range tc.issues {
+				ptrIssues = append(ptrIssues, &tc.issues[index])
+			}
+			transitions := jiraTransitions
+			if tc.transitions != nil {
+				transitions = tc.transitions
+			}
+			jiraClient := &fakejira.FakeClient{
+				Issues:           ptrIssues,
+				ExistingLinks:    tc.remoteLinks,
+				GetIssueError:    tc.issueGetErrors,
+				CreateIssueError: tc.issueCreateErrors,
+				UpdateIssueError: tc.issueUpdateErrors,
+				Transitions:      transitions,
+			}
+			var testEvent event // copy so parallel tests don't collide
+			if tc.overrideEvent != nil {
+				testEvent = *tc.overrideEvent
+			} else {
+				testEvent = *base // copy so parallel tests don't collide
+			}
+			testEvent.refresh = tc.refresh
+			testEvent.retry = tc.retry
+			testEvent.verify = tc.verify
+			testEvent.simulateMerge = tc.simulateMerge
+			testEvent.exemptBot = tc.exemptBot
+			testEvent.relabel = tc.relabel
+			testEvent.validationMarker = tc.validationMarker
+			testEvent.missing = tc.missing
+			testEvent.merged = tc.merged
+			testEvent.closed = tc.closed || tc.merged
+			testEvent.opened = tc.opened
+			testEvent.labelChangedBy = tc.labelChangedBy
+			if tc.replaceReferencedBugs != nil {
+				newEvent := testEvent
+				newEvent.bugs = []referencedBug{}
+				testEvent.bugs = tc.replaceReferencedBugs
+			}
+			if tc.noJira {
+				testEvent.noJira = true
+				testEvent.bugs = nil
+			}
+			testEvent.cherrypick = tc.cherrypick
+			testEvent.cherrypickFromPRNum = tc.cherryPickFromPRNum
+			if tc.body != "" {
+				testEvent.body = tc.body
+			}
+			if tc.title != "" {
+				testEvent.title = tc.title
+			}
+
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			gc.IssueComments = map[int][]github.IssueComment{}
+			for key, comments := range tc.prComments {
+				gc.IssueComments[key] = comments
+			}
+			gc.PullRequests = map[int]*github.PullRequest{}
+			gc.WasLabelAddedByHumanVal = tc.humanLabelled
+			gc.OrgMembers = map[string][]string{"org": tc.orgMembers}
+			for _, label := range tc.labels {
+				gc.IssueLabelsExisting = append(gc.IssueLabelsExisting, fmt.Sprintf("%s/%s#%d:%s", testEvent.org, testEvent.repo, testEvent.number, label))
+			}
+			for _, pr := range tc.prs {
+				pr := pr
+				gc.PullRequests[pr.Number] = &pr
+			}
+			// the test-infra fake github client does not implement a Query function; we don't test the query functionality here, so we can just wrap the test-infra
+			// client with a custom one that has an empty Query function
+			// TODO: implement a basic fake query function in test-infra fakegithub library and start unit testing the query path
+			fakeClient := fakeGHClient{gc}
+			if err := handle(jiraClient, fakeClient, &fakeAgileClient{boards: tc.boards, versions: tc.projectVersions, filters: tc.jqlFilters, columns: tc.boardColumns}, tc.options, logrus.WithField("testCase", tc.name), testEvent, sets.NewString("org/repo"), sets.NewString(tc.trackedProjects...)); err != nil {
+				t.Fatalf("handle failed: %v", err)
+			}
+
+			if diff := cmp.Diff(jiraClient.NewLinks, tc.expectedNewRemoteLinks); diff != "" {
+				t.Errorf("new links differs from expected new links: %s", diff)
+			}
+
+			if diff := cmp.Diff(jiraClient.RemovedLinks, tc.expectedRemovedRemoteLinks); diff != "" {
+				t.Errorf("removed links differs from expected removed links: %s", diff)
+			}
+
+			if diff := cmp.Diff(gc.IssueCommentsEdited, tc.expectedCommentUpdates); diff != "" {
+				t.Errorf("comment updates differ from expected: %s", diff)
+			}
+
+			checkComments(gc, tc.name, tc.expectedComment, t)
+
+			expected := sets.NewString()
+			for _, label := range tc.expectedLabels {
+				expected.Insert(fmt.Sprintf("%s/%s#%d:%s", testEvent.org, testEvent.repo, testEvent.number, label))
+			}
+
+			actual := sets.NewString(gc.IssueLabelsExisting...)
+			actual.Insert(gc.IssueLabelsAdded...)
+			actual.Delete(gc.IssueLabelsRemoved...)
 
 			if missing := expected.Difference(actual); missing.Len() > 0 {
 				t.Errorf("%s: missing expected labels: %v", tc.name, missing.List())
 			}
-			if extra := actual.Difference(expected); extra.Len() > 0 {
-				t.Errorf("%s: unexpected labels: %v", tc.name, extra.List())
+			if extra := actual.Difference(expected); extra.Len() > 0 {
+				t.Errorf("%s: unexpected labels: %v", tc.name, extra.List())
+			}
+
+			// reset errors on client for verification
+			jiraClient.CreateIssueError = nil
+			jiraClient.GetIssueError = nil
+			if tc.expectedIssue != nil {
+				actual, err := jiraClient.GetIssue(tc.expectedIssue.ID)
+				if err != nil {
+					t.Errorf("%s: failed to get expected bug during test: %v", tc.name, err)
+				}
+				if !reflect.DeepEqual(actual, tc.expectedIssue) {
+					t.Errorf("%s: got incorrect bug after update: %s", tc.name, cmp.Diff(actual, tc.expectedIssue, allowEventAndDate))
+				}
+			}
+			if tc.expectedIssue2 != nil {
+				actual, err := jiraClient.GetIssue(tc.expectedIssue2.ID)
+				if err != nil {
+					t.Errorf("%s: failed to get expected bug during test: %v", tc.name, err)
+				}
+				if !reflect.DeepEqual(actual, tc.expectedIssue2) {
+					t.Errorf("%s: got incorrect bug after update: %s", tc.name, cmp.Diff(actual, tc.expectedIssue2, allowEventAndDate))
+				}
+			}
+		})
+	}
+}
+
+func checkComments(client *fakegithub.FakeClient, name, expectedComment string, t *testing.T) {
+	wantedComments := 0
+	if expectedComment != "" {
+		wantedComments = 1
+	}
+	if len(client.IssueCommentsAdded) != wantedComments {
+		t.Errorf("%s: wanted %d comment, got %d: %v", name, wantedComments, len(client.IssueCommentsAdded), client.IssueCommentsAdded)
+	}
+
+	if expectedComment != "" && len(client.IssueCommentsAdded) == 1 {
+		if expectedComment != client.IssueCommentsAdded[0] {
+			t.Errorf("%s: got incorrect comment: %v", name, cmp.Diff(expectedComment, client.IssueCommentsAdded[0]))
+		}
+	}
+}
+
+func TestHandleReadyForReview(t *testing.T) {
+	pre := github.PullRequestEvent{
+		Action: github.PullRequestActionReadyForReview,
+		PullRequest: github.PullRequest{
+			Base: github.PullRequestBranch{
+				Repo: github.Repo{Owner: github.User{Login: "org"}, Name: "repo"},
+				Ref:  "branch",
+			},
+			Number:  1,
+			Title:   "OCPBUGS-123: fixed it!",
+			State:   "open",
+			HTMLURL: "https://github.com/org/repo/pull/1",
+			User:    github.User{Login: "user"},
+		},
+	}
+	e, err := digestPR(logrus.WithField("testCase", t.Name()), pre, nil)
+	if err != nil {
+		t.Fatalf("failed to digest event: %v", err)
+	}
+	if e == nil {
+		t.Fatal("expected the ready for review event to be handled")
+	}
+
+	gc := fakegithub.NewFakeClient()
+	gc.IssueLabelsExisting = []string{}
+	gc.IssueComments = map[int][]github.IssueComment{}
+	jiraClient := &fakejira.FakeClient{
+		Issues:      []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+		Transitions: []jira.Transition{{ID: "1", Name: "POST", To: jira.Status{Name: "POST"}}},
+	}
+	options := JiraBranchOptions{StateAfterValidation: &JiraBugState{Status: "POST"}}
+	if err := handle(jiraClient, fakeGHClient{gc}, &fakeAgileClient{}, options, logrus.WithField("testCase", t.Name()), *e, sets.NewString("org/repo"), nil); err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+	issue, err := jiraClient.GetIssue("OCPBUGS-123")
+	if err != nil {
+		t.Fatalf("failed to get issue: %v", err)
+	}
+	if issue.Fields.Status.Name != "POST" {
+		t.Errorf("expected the bug to be moved to POST once the pull request is ready for review, but it is %s", issue.Fields.Status.Name)
+	}
+}
+
+func TestHandleCheckRun(t *testing.T) {
+	yes := true
+	closed := &jira.Status{Name: "CLOSED"}
+	var testCases = []struct {
+		name              string
+		status            *jira.Status
+		options           JiraBranchOptions
+		expectedCheckRuns []github.CheckRun
+		expectedLabels    []string
+	}{
+		{
+			name:           "check-run is not published by default",
+			status:         &jira.Status{Name: "NEW"},
+			options:        JiraBranchOptions{IsOpen: &yes},
+			expectedLabels: []string{"jira/valid-reference", "jira/valid-bug"},
+		},
+		{
+			name:    "valid bug publishes successful check-run in addition to labels",
+			status:  &jira.Status{Name: "NEW"},
+			options: JiraBranchOptions{IsOpen: &yes, PublishCheckRun: &yes},
+			expectedCheckRuns: []github.CheckRun{{
+				Name:       "jira/valid-bug",
+				HeadSHA:    "abcdef",
+				Status:     "completed",
+				Conclusion: "success",
+				Output:     github.CheckRunOutput{Title: "The referenced Jira bug is valid"},
+			}},
+			expectedLabels: []string{"jira/valid-reference", "jira/valid-bug"},
+		},
+		{
+			name:    "invalid bug publishes failed check-run in addition to labels",
+			status:  closed,
+			options: JiraBranchOptions{IsOpen: &yes, PublishCheckRun: &yes},
+			expectedCheckRuns: []github.CheckRun{{
+				Name:       "jira/valid-bug",
+				HeadSHA:    "abcdef",
+				Status:     "completed",
+				Conclusion: "failure",
+				Output:     github.CheckRunOutput{Title: "The referenced Jira bug is invalid"},
+			}},
+			expectedLabels: []string{"jira/valid-reference", "jira/invalid-bug"},
+		},
+		{
+			name:    "invalid bug publishes failed check-run instead of labels",
+			status:  closed,
+			options: JiraBranchOptions{IsOpen: &yes, PublishCheckRun: &yes, SkipValidityLabels: &yes},
+			expectedCheckRuns: []github.CheckRun{{
+				Name:       "jira/valid-bug",
+				HeadSHA:    "abcdef",
+				Status:     "completed",
+				Conclusion: "failure",
+				Output:     github.CheckRunOutput{Title: "The referenced Jira bug is invalid"},
+			}},
+			expectedLabels: []string{"jira/valid-reference"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			gc.IssueComments = map[int][]github.IssueComment{}
+			gc.PullRequests = map[int]*github.PullRequest{1: {Number: 1, Head: github.PullRequestBranch{SHA: "abcdef"}}}
+			jiraClient := &fakejira.FakeClient{
+				Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: tc.status}}},
+			}
+			e := event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1,
+				bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+				body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+			}
+			client := &checkRunRecordingClient{fakeGHClient: fakeGHClient{gc}}
+			if err := handle(jiraClient, client, &fakeAgileClient{}, tc.options, logrus.WithField("testCase", tc.name), e, sets.NewString("org/repo"), nil); err != nil {
+				t.Fatalf("handle failed: %v", err)
+			}
+			// the summary mirrors the comment, which is already covered by TestHandle
+			for i := range client.checkRuns {
+				client.checkRuns[i].Output.Summary = ""
+			}
+			if diff := cmp.Diff(tc.expectedCheckRuns, client.checkRuns); diff != "" {
+				t.Errorf("check-runs differ from expected: %s", diff)
+			}
+			var expectedLabels []string
+			for _, label := range tc.expectedLabels {
+				expectedLabels = append(expectedLabels, fmt.Sprintf("org/repo#1:%s", label))
+			}
+			if diff := cmp.Diff(expectedLabels, gc.IssueLabelsAdded); diff != "" {
+				t.Errorf("labels differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleCheckRunAfterPush(t *testing.T) {
+	yes := true
+	summary := "The outcome of the bug validation of an earlier commit was carried over to this commit. Comment <code>/jira refresh</code> to validate the referenced bugs again."
+	var testCases = []struct {
+		name              string
+		labels            []string
+		options           JiraBranchOptions
+		expectedCheckRuns []github.CheckRun
+	}{
+		{
+			name:    "push after a valid validation publishes a successful check-run on the new head",
+			labels:  []string{"jira/valid-reference", "jira/valid-bug"},
+			options: JiraBranchOptions{IsOpen: &yes, PublishCheckRun: &yes},
+			expectedCheckRuns: []github.CheckRun{{
+				Name:       "jira/valid-bug",
+				HeadSHA:    "fedcba",
+				Status:     "completed",
+				Conclusion: "success",
+				Output:     github.CheckRunOutput{Title: "The referenced Jira bug is valid", Summary: summary},
+			}},
+		},
+		{
+			name:    "push after an invalid validation publishes a failed check-run on the new head",
+			labels:  []string{"jira/valid-reference", "jira/invalid-bug"},
+			options: JiraBranchOptions{IsOpen: &yes, PublishCheckRun: &yes},
+			expectedCheckRuns: []github.CheckRun{{
+				Name:       "jira/valid-bug",
+				HeadSHA:    "fedcba",
+				Status:     "completed",
+				Conclusion: "failure",
+				Output:     github.CheckRunOutput{Title: "The referenced Jira bug is invalid", Summary: summary},
+			}},
+		},
+		{
+			name:    "push before any validation publishes no check-run",
+			options: JiraBranchOptions{IsOpen: &yes, PublishCheckRun: &yes},
+		},
+		{
+			name:    "push without check-runs configured publishes no check-run",
+			labels:  []string{"jira/valid-reference", "jira/valid-bug"},
+			options: JiraBranchOptions{IsOpen: &yes},
+		},
+		{
+			name:    "push without validity labels validates the bug again",
+			labels:  []string{"jira/valid-reference"},
+			options: JiraBranchOptions{IsOpen: &yes, PublishCheckRun: &yes, SkipValidityLabels: &yes},
+			expectedCheckRuns: []github.CheckRun{{
+				Name:       "jira/valid-bug",
+				HeadSHA:    "fedcba",
+				Status:     "completed",
+				Conclusion: "success",
+				Output:     github.CheckRunOutput{Title: "The referenced Jira bug is valid"},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			for _, label := range tc.labels {
+				gc.IssueLabelsExisting = append(gc.IssueLabelsExisting, fmt.Sprintf("org/repo#1:%s", label))
+			}
+			gc.IssueComments = map[int][]github.IssueComment{}
+			gc.PullRequests = map[int]*github.PullRequest{1: {Number: 1, Head: github.PullRequestBranch{SHA: "fedcba"}}}
+			jiraClient := &fakejira.FakeClient{
+				Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+			}
+			e := event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, synchronized: true,
+				bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+				body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+			}
+			client := &checkRunRecordingClient{fakeGHClient: fakeGHClient{gc}}
+			if err := handle(jiraClient, client, &fakeAgileClient{}, tc.options, logrus.WithField("testCase", tc.name), e, sets.NewString("org/repo"), nil); err != nil {
+				t.Fatalf("handle failed: %v", err)
+			}
+			if tc.options.SkipValidityLabels != nil {
+				// the summary of a new validation mirrors the comment, which is already covered by TestHandle
+				for i := range client.checkRuns {
+					client.checkRuns[i].Output.Summary = ""
+				}
+			}
+			if diff := cmp.Diff(tc.expectedCheckRuns, client.checkRuns); diff != "" {
+				t.Errorf("check-runs differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+// racyRemoteLinksClient makes created remote links visible to later lookups and delays returning
+// looked up links, so that concurrent check-and-create sequences reliably interleave
+type racyRemoteLinksClient struct {
+	*fakejira.FakeClient
+	lock sync.Mutex
+}
+
+func (c *racyRemoteLinksClient) GetRemoteLinks(id string) ([]jira.RemoteLink, error) {
+	c.lock.Lock()
+	links, err := c.FakeClient.GetRemoteLinks(id)
+	c.lock.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	return links, err
+}
+
+func (c *racyRemoteLinksClient) AddRemoteLink(id string, link *jira.RemoteLink) (*jira.RemoteLink, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	created, err := c.FakeClient.AddRemoteLink(id, link)
+	if err != nil {
+		return nil, err
+	}
+	c.ExistingLinks[id] = append(c.ExistingLinks[id], *link)
+	return created, nil
+}
+
+func TestHandleConcurrentExternalLinks(t *testing.T) {
+	yes := true
+	jiraClient := &racyRemoteLinksClient{FakeClient: &fakejira.FakeClient{
+		Issues:        []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+		ExistingLinks: map[string][]jira.RemoteLink{},
+	}}
+	gc := fakegithub.NewFakeClient()
+	gc.IssueLabelsExisting = []string{}
+	gc.IssueComments = map[int][]github.IssueComment{}
+	e := event{
+		org: "org", repo: "repo", baseRef: "branch", number: 1,
+		bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+		body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := handle(jiraClient, fakeGHClient{gc}, &fakeAgileClient{}, JiraBranchOptions{AddExternalLink: &yes}, logrus.WithField("testCase", t.Name()), e, sets.NewString("org/repo"), nil); err != nil {
+				t.Errorf("handle failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(jiraClient.NewLinks) != 1 {
+		t.Errorf("expected exactly one remote link to be created, got %d: %v", len(jiraClient.NewLinks), jiraClient.NewLinks)
+	}
+}
+
+func TestHandleResync(t *testing.T) {
+	validComment := `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>No validations were run on this bug</summary></details>
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>This PR fixes OCPBUGS-123
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`
+	var testCases = []struct {
+		name             string
+		method           string
+		config           *Config
+		validationReview string
+		resyncToken      string
+		token            string
+		authorization    string
+		query            string
+		expectedCode     int
+		expectedResponse string
+		expectedComment  string
+		expectedReview   string
+	}{
+		{
+			name:             "valid request handles PR and returns comment",
+			method:           http.MethodPost,
+			token:            "secret",
+			query:            "org=org&repo=repo&number=1",
+			expectedCode:     http.StatusOK,
+			expectedResponse: strings.TrimPrefix(validComment, "org/repo#1:") + "\n",
+			expectedComment:  validComment,
+		},
+		{
+			name:             "validation results are posted as a review when configured",
+			method:           http.MethodPost,
+			validationReview: validationReviewComment,
+			token:            "secret",
+			query:            "org=org&repo=repo&number=1",
+			expectedCode:     http.StatusOK,
+			expectedResponse: strings.TrimPrefix(validComment, "org/repo#1:") + "\n",
+			expectedReview:   strings.TrimPrefix(validComment, "org/repo#1:"),
+		},
+		{
+			name:   "pull request of an exempt bot is not validated",
+			method: http.MethodPost,
+			config: &Config{Orgs: map[string]JiraOrgOptions{"org": {Repos: map[string]JiraRepoOptions{
+				"repo": {ExemptBotLogins: []string{"user"}},
+			}}}},
+			token:            "secret",
+			query:            "org=org&repo=repo&number=1",
+			expectedCode:     http.StatusOK,
+			expectedResponse: "No action was taken for org/repo#1.\n",
+		},
+		{
+			name:             "missing token is rejected",
+			method:           http.MethodPost,
+			query:            "org=org&repo=repo&number=1",
+			expectedCode:     http.StatusUnauthorized,
+			expectedResponse: "invalid resync token\n",
+		},
+		{
+			name:             "token without the bearer prefix is rejected",
+			method:           http.MethodPost,
+			authorization:    "secret",
+			query:            "org=org&repo=repo&number=1",
+			expectedCode:     http.StatusUnauthorized,
+			expectedResponse: "invalid resync token\n",
+		},
+		{
+			name:             "request without a token is rejected when the configured token is empty",
+			method:           http.MethodPost,
+			resyncToken:      " \n",
+			query:            "org=org&repo=repo&number=1",
+			expectedCode:     http.StatusForbidden,
+			expectedResponse: "resync endpoint is not configured\n",
+		},
+		{
+			name:             "wrong token is rejected",
+			method:           http.MethodPost,
+			token:            "wrong",
+			query:            "org=org&repo=repo&number=1",
+			expectedCode:     http.StatusUnauthorized,
+			expectedResponse: "invalid resync token\n",
+		},
+		{
+			name:             "GET is rejected",
+			method:           http.MethodGet,
+			token:            "secret",
+			query:            "org=org&repo=repo&number=1",
+			expectedCode:     http.StatusMethodNotAllowed,
+			expectedResponse: "only POST requests are supported\n",
+		},
+		{
+			name:             "invalid number is rejected",
+			method:           http.MethodPost,
+			token:            "secret",
+			query:            "org=org&repo=repo&number=one",
+			expectedCode:     http.StatusBadRequest,
+			expectedResponse: "invalid pull request number \"one\": strconv.Atoi: parsing \"one\": invalid syntax\n",
+		},
+		{
+			name:             "missing PR returns error",
+			method:           http.MethodPost,
+			token:            "secret",
+			query:            "org=org&repo=repo&number=2",
+			expectedCode:     http.StatusInternalServerError,
+			expectedResponse: "failed to resync org/repo#2: failed to get pull request: pull request number 2 does not exist\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			gc.PullRequests = map[int]*github.PullRequest{1: {
+				Number:  1,
+				Title:   "OCPBUGS-123: fixed it!",
+				Body:    "This PR fixes OCPBUGS-123",
+				HTMLURL: "https://github.com/org/repo/pull/1",
+				State:   github.PullRequestStateOpen,
+				User:    github.User{Login: "user"},
+				Base: github.PullRequestBranch{
+					Ref:  "branch",
+					Repo: github.Repo{Owner: github.User{Login: "org"}, Name: "repo"},
+				},
+			}}
+			jiraClient := &fakejira.FakeClient{
+				Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}},
+			}
+			agent := &prowconfig.Agent{}
+			agent.Set(&prowconfig.Config{JobConfig: prowconfig.JobConfig{AllRepos: sets.NewString("org/repo")}})
+			cfg := tc.config
+			if cfg == nil {
+				cfg = &Config{}
+			}
+			s := &server{
+				config:           func() *Config { return cfg },
+				prowConfigAgent:  agent,
+				ghc:              fakeGHClient{gc},
+				jc:               jiraClient,
+				resyncToken:      func() []byte { return []byte("secret\n") },
+				validationReview: tc.validationReview,
+			}
+			if tc.resyncToken != "" {
+				s.resyncToken = func() []byte { return []byte(tc.resyncToken) }
+			}
+
+			req := httptest.NewRequest(tc.method, "/resync?"+tc.query, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rr := httptest.NewRecorder()
+			s.handleResync(rr, req)
+			if rr.Code != tc.expectedCode {
+				t.Errorf("expected status code %d, got %d", tc.expectedCode, rr.Code)
+			}
+			if diff := cmp.Diff(tc.expectedResponse, rr.Body.String()); diff != "" {
+				t.Errorf("response differs from expected: %s", diff)
+			}
+			checkComments(gc, tc.name, tc.expectedComment, t)
+			var reviews []string
+			for _, review := range gc.Reviews[1] {
+				reviews = append(reviews, review.Body)
+			}
+			var expectedReviews []string
+			if tc.expectedReview != "" {
+				expectedReviews = []string{tc.expectedReview}
+			}
+			if diff := cmp.Diff(expectedReviews, reviews); diff != "" {
+				t.Errorf("reviews differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestJiraClientForOrg(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		config       Config
+		expectedLink string
+	}{
+		{
+			name:         "no override uses the server URL",
+			config:       Config{},
+			expectedLink: "[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123)",
+		},
+		{
+			name:         "override for other org uses the server URL",
+			config:       Config{Orgs: map[string]JiraOrgOptions{"other": {JiraURL: "https://jira.example.org"}}},
+			expectedLink: "[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123)",
+		},
+		{
+			name:         "org override produces links to the org's Jira",
+			config:       Config{Orgs: map[string]JiraOrgOptions{"org": {JiraURL: "https://jira.example.org/"}}},
+			expectedLink: "[Jira Issue OCPBUGS-123](https://jira.example.org/browse/OCPBUGS-123)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			gc.PullRequests = map[int]*github.PullRequest{1: {
+				Number: 1,
+				Title:  "OCPBUGS-123: fixed it!",
+				State:  github.PullRequestStateOpen,
+				Base: github.PullRequestBranch{
+					Ref:  "branch",
+					Repo: github.Repo{Owner: github.User{Login: "org"}, Name: "repo"},
+				},
+			}}
+			agent := &prowconfig.Agent{}
+			agent.Set(&prowconfig.Config{})
+			s := &server{
+				config:          func() *Config { return &tc.config },
+				prowConfigAgent: agent,
+				ghc:             fakeGHClient{gc},
+				jc:              &fakejira.FakeClient{Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{}}}},
+			}
+			comments, err := s.resync(logrus.WithField("testCase", tc.name), "org", "repo", 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(comments) != 1 {
+				t.Fatalf("expected one comment, got %d: %v", len(comments), comments)
+			}
+			if !strings.Contains(comments[0], tc.expectedLink) {
+				t.Errorf("expected comment to contain %q, got: %s", tc.expectedLink, comments[0])
+			}
+		})
+	}
+}
+
+func TestHandlePullRequestLogsNoAction(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("log-no-action=%t", enabled), func(t *testing.T) {
+			var out bytes.Buffer
+			logger := logrus.New()
+			logger.SetOutput(&out)
+			logger.SetFormatter(&logrus.JSONFormatter{})
+			s := &server{
+				config:      func() *Config { return &Config{} },
+				ghc:         fakeGHClient{fakegithub.NewFakeClient()},
+				logNoAction: enabled,
+			}
+			pre := github.PullRequestEvent{
+				Action: github.PullRequestActionAssigned,
+				PullRequest: github.PullRequest{
+					Base:   github.PullRequestBranch{Repo: github.Repo{Owner: github.User{Login: "org"}, Name: "repo"}, Ref: "branch"},
+					Number: 1,
+					Title:  "OCPBUGS-123: fixed it!",
+				},
 			}
+			s.handlePullRequest(logrus.NewEntry(logger), pre)
 
-			// reset errors on client for verification
-			jiraClient.CreateIssueError = nil
-			jiraClient.GetIssueError = nil
-			if tc.expectedIssue != nil {
-				actual, err := jiraClient.GetIssue(tc.expectedIssue.ID)
-				if err != nil {
-					t.Errorf("%s: failed to get expected bug during test: %v", tc.name, err)
-				}
-				if !reflect.DeepEqual(actual, tc.expectedIssue) {
-					t.Errorf("%s: got incorrect bug after update: %s", tc.name, cmp.Diff(actual, tc.expectedIssue, allowEventAndDate))
+			var reasons []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				if line == "" {
+					continue
 				}
-			}
-			if tc.expectedIssue2 != nil {
-				actual, err := jiraClient.GetIssue(tc.expectedIssue2.ID)
-				if err != nil {
-					t.Errorf("%s: failed to get expected bug during test: %v", tc.name, err)
+				var entry map[string]interface{}
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("failed to parse log line %q: %v", line, err)
 				}
-				if !reflect.DeepEqual(actual, tc.expectedIssue2) {
-					t.Errorf("%s: got incorrect bug after update: %s", tc.name, cmp.Diff(actual, tc.expectedIssue2, allowEventAndDate))
+				if reason, ok := entry[noActionReasonField]; ok {
+					reasons = append(reasons, fmt.Sprint(reason))
 				}
 			}
+			var expected []string
+			if enabled {
+				expected = []string{"unrelated event"}
+			}
+			if diff := cmp.Diff(expected, reasons); diff != "" {
+				t.Errorf("unexpected no action reasons logged: %s", diff)
+			}
+		})
+	}
+}
+
+func TestHandlePullRequestExemptBot(t *testing.T) {
+	post := JiraBugState{Status: "POST"}
+	config := &Config{
+		Default: map[string]JiraBranchOptions{"*": {ValidStates: &[]JiraBugState{post}}},
+		Orgs: map[string]JiraOrgOptions{"org": {Repos: map[string]JiraRepoOptions{
+			"repo": {ExemptBotLogins: []string{"Dependency-Bot"}},
+		}}},
+	}
+	var testCases = []struct {
+		name            string
+		author          string
+		expectedLabels  []string
+		expectedComment string
+	}{
+		{
+			name:   "PR from a configured bot login is not validated",
+			author: "dependency-bot",
+			expectedComment: `org/repo#1:@dependency-bot: This pull request was opened by dependency-bot, whose pull requests are exempt from the Jira requirements of this repository, so the referenced bugs have not been validated and no Jira labels have been applied. Comment <code>/jira refresh</code> to validate them anyway.
+
+<details>
+
+In response to [this](https://github.com/org/repo/pull/1):
+
+>Bump dependencies
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name:           "PR from another user is validated",
+			author:         "user",
+			expectedLabels: []string{"org/repo#1:" + labels.JiraValidRef, "org/repo#1:" + labels.JiraInvalidBug},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			gc.IssueComments = map[int][]github.IssueComment{}
+			agent := &prowconfig.Agent{}
+			agent.Set(&prowconfig.Config{JobConfig: prowconfig.JobConfig{AllRepos: sets.NewString("org/repo")}})
+			s := &server{
+				config:          func() *Config { return config },
+				prowConfigAgent: agent,
+				ghc:             fakeGHClient{gc},
+				jc:              &fakejira.FakeClient{Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}}},
+			}
+			pre := github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base:    github.PullRequestBranch{Repo: github.Repo{Owner: github.User{Login: "org"}, Name: "repo"}, Ref: "branch"},
+					Number:  1,
+					Title:   "OCPBUGS-123: bump dependencies",
+					Body:    "Bump dependencies",
+					HTMLURL: "https://github.com/org/repo/pull/1",
+					State:   github.PullRequestStateOpen,
+					User:    github.User{Login: tc.author},
+				},
+			}
+			s.handlePullRequest(logrus.WithField("testCase", tc.name), pre)
+
+			if diff := cmp.Diff(tc.expectedLabels, gc.IssueLabelsAdded); diff != "" {
+				t.Errorf("unexpected labels added: %s", diff)
+			}
+			if tc.expectedComment != "" {
+				checkComments(gc, tc.name, tc.expectedComment, t)
+			}
+		})
+	}
+}
+
+// reviewRecordingClient records the reviews created through it, including their action, and the
+// IDs of the reviews dismissed through it
+type reviewRecordingClient struct {
+	fakeGHClient
+	reviews   []github.DraftReview
+	dismissed []int
+}
+
+func (c *reviewRecordingClient) DismissReview(org, repo string, number int, review github.Review, message string) error {
+	c.dismissed = append(c.dismissed, review.ID)
+	return c.fakeGHClient.DismissReview(org, repo, number, review, message)
+}
+
+func (c *reviewRecordingClient) CreateReview(org, repo string, number int, r github.DraftReview) error {
+	c.reviews = append(c.reviews, r)
+	return c.fakeGHClient.CreateReview(org, repo, number, r)
+}
+
+func TestHandleValidationReview(t *testing.T) {
+	post := JiraBugState{Status: "POST"}
+	validBody := `org/repo#1:@user: This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid.
+
+<details><summary>1 validation was run on this bug</summary>
+
+* bug is in the state POST, which is one of the valid states (POST)</details>`
+	var testCases = []struct {
+		name              string
+		status            string
+		validationReview  string
+		previousReviews   []github.Review
+		expectedAction    github.ReviewAction
+		expectedComments  int
+		expectedReviews   int
+		expectedDismissed []int
+	}{
+		{
+			name:             "invalid bug requests changes",
+			status:           "NEW",
+			validationReview: validationReviewComment,
+			expectedAction:   github.RequestChanges,
+			expectedReviews:  1,
+		},
+		{
+			name:             "valid bug gets a commenting review",
+			status:           "POST",
+			validationReview: validationReviewComment,
+			expectedAction:   github.Comment,
+			expectedReviews:  1,
+		},
+		{
+			name:             "valid bug gets an approving review if configured",
+			status:           "POST",
+			validationReview: validationReviewApprove,
+			expectedAction:   github.Approve,
+			expectedReviews:  1,
+		},
+		{
+			name:             "results are commented without review mode",
+			status:           "POST",
+			expectedComments: 1,
+		},
+		{
+			name:             "same results as the last review of the bot are not posted again",
+			status:           "POST",
+			validationReview: validationReviewComment,
+			previousReviews:  []github.Review{{ID: 1, Body: validBody, User: github.User{Login: fakegithub.Bot}}},
+		},
+		{
+			name:             "bug that became valid dismisses the earlier review requesting changes",
+			status:           "POST",
+			validationReview: validationReviewComment,
+			previousReviews: []github.Review{
+				{ID: 1, Body: "This pull request references OCPBUGS-123, which is invalid", State: github.ReviewStateChangesRequested, User: github.User{Login: fakegithub.Bot}},
+				{ID: 2, Body: "Please fix the tests", State: github.ReviewStateChangesRequested, User: github.User{Login: "reviewer"}},
+			},
+			expectedAction:    github.Comment,
+			expectedReviews:   1,
+			expectedDismissed: []int{1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			gc.IssueComments = map[int][]github.IssueComment{}
+			gc.Reviews[1] = tc.previousReviews
+			client := &reviewRecordingClient{fakeGHClient: fakeGHClient{gc}}
+			jc := &fakejira.FakeClient{Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: tc.status}}}}}
+			e := event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+				validationReview: tc.validationReview,
+			}
+			if tc.previousReviews == nil {
+				e.opened = true
+			} else {
+				// the labels are already correct, so the results are only posted if they differ
+				gc.IssueLabelsExisting = []string{"org/repo#1:" + labels.JiraValidRef, "org/repo#1:" + labels.JiraValidBug}
+			}
+			options := JiraBranchOptions{ValidStates: &[]JiraBugState{post}}
+			if err := handle(jc, client, &fakeAgileClient{}, options, logrus.WithField("testCase", tc.name), e, sets.NewString("org/repo"), sets.NewString()); err != nil {
+				t.Fatalf("handle failed: %v", err)
+			}
+			if len(gc.IssueCommentsAdded) != tc.expectedComments {
+				t.Errorf("expected %d comments, got %d: %v", tc.expectedComments, len(gc.IssueCommentsAdded), gc.IssueCommentsAdded)
+			}
+			if diff := cmp.Diff(tc.expectedDismissed, client.dismissed); diff != "" {
+				t.Errorf("unexpected dismissed reviews: %s", diff)
+			}
+			if len(client.reviews) != tc.expectedReviews {
+				t.Fatalf("expected %d reviews, got %d: %v", tc.expectedReviews, len(client.reviews), client.reviews)
+			}
+			if tc.expectedReviews == 0 {
+				return
+			}
+			if client.reviews[0].Action != tc.expectedAction {
+				t.Errorf("expected the review to %s, got %s", tc.expectedAction, client.reviews[0].Action)
+			}
+			if !strings.Contains(client.reviews[0].Body, "This PR fixes OCPBUGS-123") {
+				t.Errorf("expected the review to respond to the pull request, got %s", client.reviews[0].Body)
+			}
+		})
+	}
+}
+
+func TestInsertLinksIntoComment(t *testing.T) {
+	t.Parallel()
+	const issueName = "ABC-123"
+	testCases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name: "Multiline body starting with issue name",
+			body: `ABC-123: Fix problems:
+* First problem
+* Second problem`,
+			expected: `[ABC-123](https://my-jira.com/browse/ABC-123): Fix problems:
+* First problem
+* Second problem`,
+		},
+		{
+			name: "Multiline body starting with already replaced issue name",
+			body: `[ABC-123](https://my-jira.com/browse/ABC-123): Fix problems:
+* First problem
+* Second problem`,
+			expected: `[ABC-123](https://my-jira.com/browse/ABC-123): Fix problems:
+* First problem
+* Second problem`,
+		},
+		{
+			name: "Multiline body with multiple occurrence in the middle",
+			body: `This change:
+* Does stuff related to ABC-123
+* And even more stuff related to ABC-123
+* But also something else`,
+			expected: `This change:
+* Does stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
+* And even more stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
+* But also something else`,
+		},
+		{
+			name: "Multiline body with multiple occurrence in the middle, some already replaced",
+			body: `This change:
+* Does stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
+* And even more stuff related to ABC-123
+* But also something else`,
+			expected: `This change:
+* Does stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
+* And even more stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
+* But also something else`,
+		},
+		{
+			name: "Multiline body with issue name at the end",
+			body: `This change:
+is very important
+because of ABC-123`,
+			expected: `This change:
+is very important
+because of [ABC-123](https://my-jira.com/browse/ABC-123)`,
+		},
+		{
+			name: "Multiline body with already replaced issue name at the end",
+			body: `This change:
+is very important
+because of [ABC-123](https://my-jira.com/browse/ABC-123)`,
+			expected: `This change:
+is very important
+because of [ABC-123](https://my-jira.com/browse/ABC-123)`,
+		},
+		{
+			name:     "Pasted links are not replaced, as they are already clickable",
+			body:     "https://my-jira.com/browse/ABC-123",
+			expected: "https://my-jira.com/browse/ABC-123",
+		},
+		{
+			name: "code section is not replaced",
+			body: `This change:
+is very important` + "\n```bash\n" +
+				`ABC-123` +
+				"\n```\n" + `ABC-123
+`,
+			expected: `This change:
+is very important` + "\n```bash\n" +
+				`ABC-123` +
+				"\n```\n" + `[ABC-123](https://my-jira.com/browse/ABC-123)
+`,
+		},
+		{
+			name: "inline code is not replaced",
+			body: `This change:
+is very important` + "\n``ABC-123`` and `ABC-123` shouldn't be replaced, as well as ``ABC-123: text text``. " +
+				`ABC-123 should be replaced.
+`,
+			expected: `This change:
+is very important` + "\n``ABC-123`` and `ABC-123` shouldn't be replaced, as well as ``ABC-123: text text``. " +
+				`[ABC-123](https://my-jira.com/browse/ABC-123) should be replaced.
+`,
+		},
+		{
+			name:     "Multiline codeblock that is denoted through four leading spaces",
+			body:     "I meant to do this test:\r\n\r\n    operator_test.go:1914: failed to read output from pod unique-id-header-test-1: container \"curl\" in pod \"unique-id-header-ABC-123\" is waiting to start: ContainerCreating\r\n\r\n",
+			expected: "I meant to do this test:\r\n\r\n    operator_test.go:1914: failed to read output from pod unique-id-header-test-1: container \"curl\" in pod \"unique-id-header-ABC-123\" is waiting to start: ContainerCreating\r\n\r\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(insertLinksIntoComment(tc.body, []string{issueName}, fakejira.FakeJiraUrl), tc.expected); diff != "" {
+				t.Errorf("actual result differs from expected result: %s", diff)
+			}
 		})
 	}
 }
 
-func checkComments(client *fakegithub.FakeClient, name, expectedComment string, t *testing.T) {
-	wantedComments := 0
-	if expectedComment != "" {
-		wantedComments = 1
+func TestHelpProvider(t *testing.T) {
+	rawConfig := `disabled_jira_projects:
+- "private-project"
+default:
+  "*":
+    target_version: global-default
+  "global-branch":
+    is_open: false
+    target_version: global-branch-default
+orgs:
+  my-org:
+    default:
+      "*":
+        is_open: true
+        target_version: my-org-default
+        state_after_validation:
+          status: "PRE"
+      "my-org-branch":
+        target_version: my-org-branch-default
+        state_after_validation:
+          status: "POST"
+        add_external_link: true
+    repos:
+      my-repo:
+        branches:
+          "*":
+            is_open: false
+            target_version: my-repo-default
+            valid_states:
+            - status: VALIDATED
+          "my-repo-branch":
+            target_version: my-repo-branch
+            valid_states:
+            - status: MODIFIED
+            add_external_link: true
+            state_after_merge:
+              status: MODIFIED
+            allowed_security_levels:
+            - default
+          "branch-that-likes-closed-bugs":
+            valid_states:
+            - status: VERIFIED
+            - status: CLOSED
+              resolution: ERRATA
+            dependent_bug_states:
+            - status: CLOSED
+              resolution: ERRATA
+            state_after_merge:
+              status: CLOSED
+              resolution: FIXED
+            state_after_validation:
+              status: CLOSED
+              resolution: VALIDATED
+      strict-repo:
+        branches:
+          "*":
+            allowed_reporters:
+            - alice
+            - bob
+            reject_resolutions:
+            - "Won't Do"
+            require_verification_field: true
+            require_release_note_type: true
+            require_story_points: true
+            reject_flagged_blocked: true
+            issue_type_branches:
+              Epic:
+              - main|master
+          "release-4.14":
+            require_known_target_version: true
+            known_target_versions:
+            - 4.14.0
+            - 4.14.z
+            validate_branch_target_consistency: true
+            branch_target_versions:
+              release-4.14: 4.14.z`
+
+	var config Config
+	if err := yaml.Unmarshal([]byte(rawConfig), &config); err != nil {
+		t.Fatalf("couldn't unmarshal config: %v", err)
 	}
-	if len(client.IssueCommentsAdded) != wantedComments {
-		t.Errorf("%s: wanted %d comment, got %d: %v", name, wantedComments, len(client.IssueCommentsAdded), client.IssueCommentsAdded)
+	enabledRepos := []prowconfig.OrgRepo{
+		{Org: "some-org", Repo: "some-repo"},
+		{Org: "my-org", Repo: "some-repo"},
+		{Org: "my-org", Repo: "my-repo"},
+		{Org: "my-org", Repo: "strict-repo"},
+	}
+	serv := &server{
+		config: func() *Config {
+			return &config
+		},
+	}
+	help, err := serv.helpProvider(enabledRepos)
+	if err != nil {
+		t.Fatalf("unexpected error creating help provider: %v", err)
 	}
+	// don't check snippet
+	help.Snippet = ""
 
-	if expectedComment != "" && len(client.IssueCommentsAdded) == 1 {
-		if expectedComment != client.IssueCommentsAdded[0] {
-			t.Errorf("%s: got incorrect comment: %v", name, cmp.Diff(expectedComment, client.IssueCommentsAdded[0]))
-		}
+	expected := &pluginhelp.PluginHelp{
+		Description: "The jira plugin ensures that pull requests reference a valid Jira bug in their title.",
+		Config: map[string]string{
+			"some-org/some-repo": `The plugin has the following configuration:<ul>
+<li>by default, valid bugs must target the "global-default" version.</li>
+<li>on the "global-branch" branch, valid bugs must be closed and target the "global-branch-default" version.</li>
+</ul>`,
+			"my-org/some-repo": `The plugin has the following configuration:<ul>
+<li>by default, valid bugs must be open and target the "my-org-default" version. After being linked to a pull request, bugs will be moved to the PRE state.</li>
+<li>on the "my-org-branch" branch, valid bugs must be open and target the "my-org-branch-default" version. After being linked to a pull request, bugs will be moved to the POST state and updated to refer to the pull request using the external bug tracker.</li>
+</ul>`,
+			"my-org/my-repo": `The plugin has the following configuration:<ul>
+<li>by default, valid bugs must be closed, target the "my-repo-default" version, and be in one of the following states: VALIDATED. After being linked to a pull request, bugs will be moved to the PRE state.</li>
+<li>on the "branch-that-likes-closed-bugs" branch, valid bugs must be closed, target the "my-repo-default" version, be in one of the following states: VERIFIED, CLOSED (ERRATA), depend on at least one other bug, and have all dependent bugs in one of the following states: CLOSED (ERRATA). After being linked to a pull request, bugs will be moved to the CLOSED (VALIDATED) state and moved to the CLOSED (FIXED) state when all linked pull requests are merged.</li>
+<li>on the "my-org-branch" branch, valid bugs must be closed, target the "my-repo-default" version, and be in one of the following states: VALIDATED. After being linked to a pull request, bugs will be moved to the POST state and updated to refer to the pull request using the external bug tracker.</li>
+<li>on the "my-repo-branch" branch, valid bugs must be closed, target the "my-repo-branch" version, and be in one of the following states: MODIFIED. After being linked to a pull request, bugs will be moved to the PRE state, updated to refer to the pull request using the external bug tracker, and moved to the MODIFIED state when all linked pull requests are merged.</li>
+</ul>`,
+			"my-org/strict-repo": `The plugin has the following configuration:<ul>
+<li>by default, valid bugs must be open, target the "my-org-default" version, only be of type Epic on branches matching main|master, be reported by one of alice, bob, not be resolved as any of Won't Do, have the verification field set, have the release note type set, have story points, and not be flagged as blocked. After being linked to a pull request, bugs will be moved to the PRE state.</li>
+<li>on the "my-org-branch" branch, valid bugs must be open, target the "my-org-branch-default" version, only be of type Epic on branches matching main|master, be reported by one of alice, bob, not be resolved as any of Won't Do, have the verification field set, have the release note type set, have story points, and not be flagged as blocked. After being linked to a pull request, bugs will be moved to the POST state and updated to refer to the pull request using the external bug tracker.</li>
+<li>on the "release-4.14" branch, valid bugs must be open, target the "my-org-default" version, target one of the following known versions: 4.14.0, 4.14.z, target the "4.14.z" version expected for the branch, only be of type Epic on branches matching main|master, be reported by one of alice, bob, not be resolved as any of Won't Do, have the verification field set, have the release note type set, have story points, and not be flagged as blocked. After being linked to a pull request, bugs will be moved to the PRE state.</li>
+</ul>`,
+		},
+		Commands: []pluginhelp.Command{
+			{
+				Usage:       "/jira refresh",
+				Description: "Check Jira for a valid bug referenced in the PR title",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira refresh"},
+			}, {
+				Usage:       "/jira cc-qa",
+				Description: "Request PR review from QA contact specified in Jira",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira cc-qa"},
+			}, {
+				Usage:       "/jira cherrypick jiraBugKey [--branches branch,...]",
+				Description: "Cherrypick a jira bug and link it to the current PR. With --branches, the bug is cloned for the target version of each of the given branches instead",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira cherrypick OCPBUGS-1234", "/jira cherrypick OCPBUGS-1234 --branches release-4.14,release-4.13"},
+			}, {
+				Usage:       "/jira retry",
+				Description: "Retry the Jira state transition for the bug referenced in the PR title without re-running the full validation",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira retry"},
+			}, {
+				Usage:       "/jira prs",
+				Description: "List all PRs linked to the Jira bug referenced in the PR title, along with their merge status",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira prs"},
+			}, {
+				Usage:       "/jira deps",
+				Description: "List the bugs that the Jira bug referenced in the PR title depends on, with their states and target versions as seen by the dependent bug validations",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira deps"},
+			},
+			{
+				Usage:       "/jira verify",
+				Description: "Move the Jira bug referenced in the title of a merged PR to the VERIFIED state, attributing the verification to the commenter",
+				Featured:    false,
+				WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+				Examples:    []string{"/jira verify"},
+			}, {
+				Usage:       "/jira add-link",
+				Description: "Link the PR on the Jira bug referenced in the PR title using the external bug tracker, even if the repo is not configured to add these links",
+				Featured:    false,
+				WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+				Examples:    []string{"/jira add-link"},
+			}, {
+				Usage:       "/jira relabel",
+				Description: "Reconcile the Jira labels of the PR (valid/invalid bug, valid reference and severity) with the current configuration without changing the state of the bug",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira relabel"},
+			}, {
+				Usage:       "/jira fix-labels",
+				Description: "Like /jira relabel, but also remove the Jira labels of the PR that were added manually or are not managed with the current configuration, e.g. contradictory valid and invalid bug labels",
+				Featured:    false,
+				WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+				Examples:    []string{"/jira fix-labels"},
+			}, {
+				Usage:       "/jira severity-map",
+				Description: "List the severities of Jira bugs and the labels they are mapped to on the PR",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira severity-map"},
+			}, {
+				Usage:       "/jira history",
+				Description: "Summarize the recent actions of the plugin on the PR, e.g. bug validations and state changes, as a timeline built from its prior comments",
+				Featured:    false,
+				WhoCanUse:   "Anyone",
+				Examples:    []string{"/jira history"},
+			}, {
+				Usage:       "/jira simulate-merge",
+				Description: "Handle the PR as if it had merged to verify the configuration of the branch, e.g. on a staging instance: the bug referenced in the PR title is moved to the state after merge if all of its linked PRs have merged, treating this PR as merged. The response is labeled as a simulation",
+				Featured:    false,
+				WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+				Examples:    []string{"/jira simulate-merge"},
+			}, {
+				Usage:       "/jira reclone jiraBugKey",
+				Description: "Replace the clone referenced in the PR title, which was cloned from the wrong bug, with a clone of the given bug and retitle the PR. The existing clone is unlinked from its parent, but not deleted",
+				Featured:    false,
+				WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+				Examples:    []string{"/jira reclone OCPBUGS-1234"},
+			}, {
+				Usage:       "/jira fix-version-from-branch",
+				Description: "Set the target version of the Jira bug referenced in the PR title, e.g. a clone created with the wrong target version, to the target version configured for the branch of the PR and re-validate the bug",
+				Featured:    false,
+				WhoCanUse:   "Members of the organization that are on the repo's allowlist for privileged commands, if one is configured",
+				Examples:    []string{"/jira fix-version-from-branch"},
+			},
+		},
+	}
+
+	if actual := help; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("resolved incorrect plugin help: %v", cmp.Diff(actual, expected, allowEventAndDate))
 	}
 }
 
-func TestInsertLinksIntoComment(t *testing.T) {
-	t.Parallel()
-	const issueName = "ABC-123"
-	testCases := []struct {
-		name     string
-		body     string
-		expected string
+func TestDigestPR(t *testing.T) {
+	yes := true
+	var testCases = []struct {
+		name              string
+		pre               github.PullRequestEvent
+		validateByDefault *bool
+		expected          *event
+		expectedErr       bool
 	}{
 		{
-			name: "Multiline body starting with issue name",
-			body: `ABC-123: Fix problems:
-* First problem
-* Second problem`,
-			expected: `[ABC-123](https://my-jira.com/browse/ABC-123): Fix problems:
-* First problem
-* Second problem`,
+			name: "unrelated event gets ignored",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestFileAdded,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number: 1,
+					Title:  "OCPBUGS-123: fixed it!",
+					State:  "open",
+				},
+			},
+		},
+		{
+			name: "draft marked as ready for review gets handled",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionReadyForReview,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number: 1,
+					Title:  "OCPBUGS-123: fixed it!",
+					State:  "open",
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", title: "OCPBUGS-123: fixed it!",
+			},
+		},
+		{
+			name: "pull request converted to draft gets handled",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionConvertedToDraft,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number: 1,
+					Title:  "OCPBUGS-123: fixed it!",
+					State:  "open",
+					Draft:  true,
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", title: "OCPBUGS-123: fixed it!", draft: true, convertedToDraft: true,
+			},
+		},
+		{
+			name: "draft with unrelated title marked as ready for review gets ignored",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionReadyForReview,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number: 1,
+					Title:  "fixing a typo",
+					State:  "open",
+				},
+			},
+		},
+		{
+			name: "unrelated title gets ignored",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number: 1,
+					Title:  "fixing a typo",
+					State:  "open",
+				},
+			},
+		},
+		{
+			name: "unrelated title gets handled when validating by default",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "fixing a typo",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			validateByDefault: &yes,
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", missing: true, opened: true, bugs: nil, title: "fixing a typo", htmlUrl: "http.com", login: "user",
+			},
+		},
+		{
+			name: "title referencing bug gets an event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+			},
 		},
 		{
-			name: "Multiline body starting with already replaced issue name",
-			body: `[ABC-123](https://my-jira.com/browse/ABC-123): Fix problems:
-* First problem
-* Second problem`,
-			expected: `[ABC-123](https://my-jira.com/browse/ABC-123): Fix problems:
-* First problem
-* Second problem`,
+			name: "title referencing multiple bugs gets an event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123,OCPBUGS-124: fixed it!",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}}, title: "OCPBUGS-123,OCPBUGS-124: fixed it!", htmlUrl: "http.com", login: "user",
+			},
 		},
 		{
-			name: "Multiline body with multiple occurrence in the middle",
-			body: `This change:
-* Does stuff related to ABC-123
-* And even more stuff related to ABC-123
-* But also something else`,
-			expected: `This change:
-* Does stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
-* And even more stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
-* But also something else`,
+			name: "title referencing bug and jira issue gets an event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123,JIRA-123: fixed it!",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "JIRA-123", IsBug: false}}, title: "OCPBUGS-123,JIRA-123: fixed it!", htmlUrl: "http.com", login: "user",
+			},
 		},
 		{
-			name: "Multiline body with multiple occurrence in the middle, some already replaced",
-			body: `This change:
-* Does stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
-* And even more stuff related to ABC-123
-* But also something else`,
-			expected: `This change:
-* Does stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
-* And even more stuff related to [ABC-123](https://my-jira.com/browse/ABC-123)
-* But also something else`,
+			name: "title referencing non-bug jira gets an event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "SOMEJIRA-123: implement feature!",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: []referencedBug{{Key: "SOMEJIRA-123", IsBug: false}}, title: "SOMEJIRA-123: implement feature!", htmlUrl: "http.com", login: "user",
+			},
 		},
 		{
-			name: "Multiline body with issue name at the end",
-			body: `This change:
-is very important
-because of ABC-123`,
-			expected: `This change:
-is very important
-because of [ABC-123](https://my-jira.com/browse/ABC-123)`,
+			name: "title explicitly referencing no-issue gets an event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "NO-ISSUE: typo fixup",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: nil, noJira: true, title: "NO-ISSUE: typo fixup", htmlUrl: "http.com", login: "user",
+			},
+		},
+		{
+			name: "title referencing no-jira gets an event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "NO-JIRA: typo fixup",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: nil, noJira: true, title: "NO-JIRA: typo fixup", htmlUrl: "http.com", login: "user",
+			},
+		},
+		{
+			name: "title referencing bug gets an event on PR merge",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionClosed,
+				PullRequest: github.PullRequest{
+					Merged: true,
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, merged: true, closed: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+			},
+		},
+		{
+			name: "title referencing bug gets an event on PR close",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionClosed,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, merged: false, closed: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+			},
+		},
+		{
+			name: "non-jira cherrypick PR sets e.missing to true",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "release-4.4",
+					},
+					Number:  3,
+					Title:   "[release-4.4] fixing a typo",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+					Body: `This is an automated cherry-pick of #2
+
+/assign user`,
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "release-4.4", number: 3, opened: true, body: "This is an automated cherry-pick of #2\n\n/assign user", title: "[release-4.4] fixing a typo", htmlUrl: "http.com", login: "user", cherrypick: true, cherrypickFromPRNum: 2, missing: true,
+			},
 		},
 		{
-			name: "Multiline body with already replaced issue name at the end",
-			body: `This change:
-is very important
-because of [ABC-123](https://my-jira.com/browse/ABC-123)`,
-			expected: `This change:
-is very important
-because of [ABC-123](https://my-jira.com/browse/ABC-123)`,
+			name: "cherrypicked PR gets cherrypick event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "release-4.4",
+					},
+					Number:  3,
+					Title:   "[release-4.4] OCPBUGS-123: fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+					Body: `This is an automated cherry-pick of #2
+
+/assign user`,
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "release-4.4", number: 3, opened: true, body: "This is an automated cherry-pick of #2\n\n/assign user", title: "[release-4.4] OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user", cherrypick: true, cherrypickFromPRNum: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+			},
 		},
 		{
-			name:     "Pasted links are not replaced, as they are already clickable",
-			body:     "https://my-jira.com/browse/ABC-123",
-			expected: "https://my-jira.com/browse/ABC-123",
+			name: "edited cherrypicked PR gets normal event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionEdited,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "release-4.4",
+					},
+					Number:  3,
+					Title:   "[release-4.4] OCPBUGS-123: fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+					Body: `This is an automated cherry-pick of #2
+
+/assign user`,
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "release-4.4", number: 3, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "This is an automated cherry-pick of #2\n\n/assign user", title: "[release-4.4] OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+			},
 		},
 		{
-			name: "code section is not replaced",
-			body: `This change:
-is very important` + "\n```bash\n" +
-				`ABC-123` +
-				"\n```\n" + `ABC-123
-`,
-			expected: `This change:
-is very important` + "\n```bash\n" +
-				`ABC-123` +
-				"\n```\n" + `[ABC-123](https://my-jira.com/browse/ABC-123)
-`,
+			name: "title change referencing same bug gets no event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+				Changes: []byte(`{"title":{"from":"OCPBUGS-123: fixed it! (WIP)"}}`),
+			},
 		},
 		{
-			name: "inline code is not replaced",
-			body: `This change:
-is very important` + "\n``ABC-123`` and `ABC-123` shouldn't be replaced, as well as ``ABC-123: text text``. " +
-				`ABC-123 should be replaced.
-`,
-			expected: `This change:
-is very important` + "\n``ABC-123`` and `ABC-123` shouldn't be replaced, as well as ``ABC-123: text text``. " +
-				`[ABC-123](https://my-jira.com/browse/ABC-123) should be replaced.
-`,
+			name: "title edit referencing same bug gets event to update the remote links",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionEdited,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+				Changes: []byte(`{"title":{"from":"OCPBUGS-123: fixed it! (WIP)"}}`),
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user", titleChanged: true,
+			},
 		},
 		{
-			name:     "Multiline codeblock that is denoted through four leading spaces",
-			body:     "I meant to do this test:\r\n\r\n    operator_test.go:1914: failed to read output from pod unique-id-header-test-1: container \"curl\" in pod \"unique-id-header-ABC-123\" is waiting to start: ContainerCreating\r\n\r\n",
-			expected: "I meant to do this test:\r\n\r\n    operator_test.go:1914: failed to read output from pod unique-id-header-test-1: container \"curl\" in pod \"unique-id-header-ABC-123\" is waiting to start: ContainerCreating\r\n\r\n",
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			if diff := cmp.Diff(insertLinksIntoComment(tc.body, []string{issueName}, fakejira.FakeJiraUrl), tc.expected); diff != "" {
-				t.Errorf("actual result differs from expected result: %s", diff)
-			}
-		})
-	}
-}
-
-func TestHelpProvider(t *testing.T) {
-	rawConfig := `disabled_jira_projects:
-- "private-project"
-default:
-  "*":
-    target_version: global-default
-  "global-branch":
-    is_open: false
-    target_version: global-branch-default
-orgs:
-  my-org:
-    default:
-      "*":
-        is_open: true
-        target_version: my-org-default
-        state_after_validation:
-          status: "PRE"
-      "my-org-branch":
-        target_version: my-org-branch-default
-        state_after_validation:
-          status: "POST"
-        add_external_link: true
-    repos:
-      my-repo:
-        branches:
-          "*":
-            is_open: false
-            target_version: my-repo-default
-            valid_states:
-            - status: VALIDATED
-          "my-repo-branch":
-            target_version: my-repo-branch
-            valid_states:
-            - status: MODIFIED
-            add_external_link: true
-            state_after_merge:
-              status: MODIFIED
-            allowed_security_levels:
-            - default
-          "branch-that-likes-closed-bugs":
-            valid_states:
-            - status: VERIFIED
-            - status: CLOSED
-              resolution: ERRATA
-            dependent_bug_states:
-            - status: CLOSED
-              resolution: ERRATA
-            state_after_merge:
-              status: CLOSED
-              resolution: FIXED
-            state_after_validation:
-              status: CLOSED
-              resolution: VALIDATED`
-
-	var config Config
-	if err := yaml.Unmarshal([]byte(rawConfig), &config); err != nil {
-		t.Fatalf("couldn't unmarshal config: %v", err)
-	}
-	enabledRepos := []prowconfig.OrgRepo{
-		{Org: "some-org", Repo: "some-repo"},
-		{Org: "my-org", Repo: "some-repo"},
-		{Org: "my-org", Repo: "my-repo"},
-	}
-	serv := &server{
-		config: func() *Config {
-			return &config
-		},
-	}
-	help, err := serv.helpProvider(enabledRepos)
-	if err != nil {
-		t.Fatalf("unexpected error creating help provider: %v", err)
-	}
-	// don't check snippet
-	help.Snippet = ""
-
-	expected := &pluginhelp.PluginHelp{
-		Description: "The jira plugin ensures that pull requests reference a valid Jira bug in their title.",
-		Config: map[string]string{
-			"some-org/some-repo": `The plugin has the following configuration:<ul>
-<li>by default, valid bugs must target the "global-default" version.</li>
-<li>on the "global-branch" branch, valid bugs must be closed and target the "global-branch-default" version.</li>
-</ul>`,
-			"my-org/some-repo": `The plugin has the following configuration:<ul>
-<li>by default, valid bugs must be open and target the "my-org-default" version. After being linked to a pull request, bugs will be moved to the PRE state.</li>
-<li>on the "my-org-branch" branch, valid bugs must be open and target the "my-org-branch-default" version. After being linked to a pull request, bugs will be moved to the POST state and updated to refer to the pull request using the external bug tracker.</li>
-</ul>`,
-			"my-org/my-repo": `The plugin has the following configuration:<ul>
-<li>by default, valid bugs must be closed, target the "my-repo-default" version, and be in one of the following states: VALIDATED. After being linked to a pull request, bugs will be moved to the PRE state.</li>
-<li>on the "branch-that-likes-closed-bugs" branch, valid bugs must be closed, target the "my-repo-default" version, be in one of the following states: VERIFIED, CLOSED (ERRATA), depend on at least one other bug, and have all dependent bugs in one of the following states: CLOSED (ERRATA). After being linked to a pull request, bugs will be moved to the CLOSED (VALIDATED) state and moved to the CLOSED (FIXED) state when all linked pull requests are merged.</li>
-<li>on the "my-org-branch" branch, valid bugs must be closed, target the "my-repo-default" version, and be in one of the following states: VALIDATED. After being linked to a pull request, bugs will be moved to the POST state and updated to refer to the pull request using the external bug tracker.</li>
-<li>on the "my-repo-branch" branch, valid bugs must be closed, target the "my-repo-branch" version, and be in one of the following states: MODIFIED. After being linked to a pull request, bugs will be moved to the PRE state, updated to refer to the pull request using the external bug tracker, and moved to the MODIFIED state when all linked pull requests are merged.</li>
-</ul>`,
+			name: "revert of a fix gets event for a revert",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   `Revert "OCPBUGS-123: fixed it!"`,
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: `Revert "OCPBUGS-123: fixed it!"`, htmlUrl: "http.com", login: "user", opened: true, revert: true,
+			},
 		},
-		Commands: []pluginhelp.Command{
-			{
-				Usage:       "/jira refresh",
-				Description: "Check Jira for a valid bug referenced in the PR title",
-				Featured:    false,
-				WhoCanUse:   "Anyone",
-				Examples:    []string{"/jira refresh"},
-			}, {
-				Usage:       "/jira cc-qa",
-				Description: "Request PR review from QA contact specified in Jira",
-				Featured:    false,
-				WhoCanUse:   "Anyone",
-				Examples:    []string{"/jira cc-qa"},
-			}, {
-				Usage:       "/jira cherrypick jiraBugKey",
-				Description: "Cherrypick a jira bug and link it to the current PR",
-				Featured:    false,
-				WhoCanUse:   "Anyone",
-				Examples:    []string{"/jira cherrypick OCPBUGS-1234"},
+		{
+			name: "synchronize referencing a bug gets event to prune stale remote links",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionSynchronize,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user", synchronized: true,
 			},
 		},
-	}
-
-	if actual := help; !reflect.DeepEqual(actual, expected) {
-		t.Errorf("resolved incorrect plugin help: %v", cmp.Diff(actual, expected, allowEventAndDate))
-	}
-}
-
-func TestDigestPR(t *testing.T) {
-	yes := true
-	var testCases = []struct {
-		name              string
-		pre               github.PullRequestEvent
-		validateByDefault *bool
-		expected          *event
-		expectedErr       bool
-	}{
 		{
-			name: "unrelated event gets ignored",
+			name: "synchronize without a referenced bug gets no event",
 			pre: github.PullRequestEvent{
-				Action: github.PullRequestFileAdded,
+				Action: github.PullRequestActionSynchronize,
 				PullRequest: github.PullRequest{
 					Base: github.PullRequestBranch{
 						Repo: github.Repo{
@@ -3063,14 +7305,17 @@ func TestDigestPR(t *testing.T) {
 						},
 						Ref: "branch",
 					},
-					Number: 1,
-					Title:  "OCPBUGS-123: fixed it!",
-					State:  "open",
+					Number:  1,
+					Title:   "fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
 				},
 			},
 		},
 		{
-			name: "unrelated title gets ignored",
+			name: "title change referencing new bug gets event",
 			pre: github.PullRequestEvent{
 				Action: github.PullRequestActionOpened,
 				PullRequest: github.PullRequest{
@@ -3083,14 +7328,21 @@ func TestDigestPR(t *testing.T) {
 						},
 						Ref: "branch",
 					},
-					Number: 1,
-					Title:  "fixing a typo",
-					State:  "open",
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
 				},
+				Changes: []byte(`{"title":{"from":"fixed it! (WIP)"}}`),
+			},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, opened: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
 			},
 		},
 		{
-			name: "unrelated title gets handled when validating by default",
+			name: "title change dereferencing bug gets event",
 			pre: github.PullRequestEvent{
 				Action: github.PullRequestActionOpened,
 				PullRequest: github.PullRequest{
@@ -3104,23 +7356,46 @@ func TestDigestPR(t *testing.T) {
 						Ref: "branch",
 					},
 					Number:  1,
-					Title:   "fixing a typo",
-					State:   "open",
+					Title:   "fixed it!",
 					HTMLURL: "http.com",
 					User: github.User{
 						Login: "user",
 					},
 				},
+				Changes: []byte(`{"title":{"from":"OCPBUGS-123: fixed it! (WIP)"}}`),
 			},
-			validateByDefault: &yes,
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", missing: true, opened: true, bugs: nil, title: "fixing a typo", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, opened: true, missing: true, title: "fixed it!", htmlUrl: "http.com", login: "user",
 			},
 		},
 		{
-			name: "title referencing bug gets an event",
+			name: "title change to no bug with unrelated changes gets no event",
 			pre: github.PullRequestEvent{
 				Action: github.PullRequestActionOpened,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "fixed it!",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+				Changes: []byte(`{"oops":{"doops":"payload"}}`),
+			},
+		},
+		{
+			name: "qe-approved labeling gets event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionLabeled,
 				PullRequest: github.PullRequest{
 					Base: github.PullRequestBranch{
 						Repo: github.Repo{
@@ -3139,15 +7414,18 @@ func TestDigestPR(t *testing.T) {
 						Login: "user",
 					},
 				},
+				Label: github.Label{
+					Name: labels.QEApproved,
+				},
 			},
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: false, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
 			},
 		},
 		{
-			name: "title referencing multiple bugs gets an event",
+			name: "qe-approved unlabeling gets event",
 			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
+				Action: github.PullRequestActionUnlabeled,
 				PullRequest: github.PullRequest{
 					Base: github.PullRequestBranch{
 						Repo: github.Repo{
@@ -3159,22 +7437,25 @@ func TestDigestPR(t *testing.T) {
 						Ref: "branch",
 					},
 					Number:  1,
-					Title:   "OCPBUGS-123,OCPBUGS-124: fixed it!",
+					Title:   "OCPBUGS-123: fixed it!",
 					State:   "open",
 					HTMLURL: "http.com",
 					User: github.User{
 						Login: "user",
 					},
 				},
+				Label: github.Label{
+					Name: labels.QEApproved,
+				},
 			},
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}}, title: "OCPBUGS-123,OCPBUGS-124: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: false, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
 			},
 		},
 		{
-			name: "title referencing bug and jira issue gets an event",
+			name: "valid bug labeling by a human gets event",
 			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
+				Action: github.PullRequestActionLabeled,
 				PullRequest: github.PullRequest{
 					Base: github.PullRequestBranch{
 						Repo: github.Repo{
@@ -3186,494 +7467,587 @@ func TestDigestPR(t *testing.T) {
 						Ref: "branch",
 					},
 					Number:  1,
-					Title:   "OCPBUGS-123,JIRA-123: fixed it!",
+					Title:   "OCPBUGS-123: fixed it!",
 					State:   "open",
 					HTMLURL: "http.com",
 					User: github.User{
 						Login: "user",
 					},
 				},
+				Label: github.Label{
+					Name: labels.JiraValidBug,
+				},
+				Sender: github.User{
+					Login: "someone",
+				},
 			},
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "JIRA-123", IsBug: false}}, title: "OCPBUGS-123,JIRA-123: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: false, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user", labelChangedBy: "someone",
+			},
+		},
+		{
+			name: "unrelated labeling does not get event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionLabeled,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+				Label: github.Label{
+					Name: "lgtm",
+				},
+			},
+		},
+		{
+			name: "unrelated unlabeling does not get event",
+			pre: github.PullRequestEvent{
+				Action: github.PullRequestActionUnlabeled,
+				PullRequest: github.PullRequest{
+					Base: github.PullRequestBranch{
+						Repo: github.Repo{
+							Owner: github.User{
+								Login: "org",
+							},
+							Name: "repo",
+						},
+						Ref: "branch",
+					},
+					Number:  1,
+					Title:   "OCPBUGS-123: fixed it!",
+					State:   "open",
+					HTMLURL: "http.com",
+					User: github.User{
+						Login: "user",
+					},
+				},
+				Label: github.Label{
+					Name: "lgtm",
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			event, err := digestPR(logrus.WithField("testCase", testCase.name), testCase.pre, testCase.validateByDefault)
+			if err == nil && testCase.expectedErr {
+				t.Errorf("%s: expected an error but got none", testCase.name)
+			}
+			if err != nil && !testCase.expectedErr {
+				t.Errorf("%s: expected no error but got one: %v", testCase.name, err)
+			}
+
+			if actual, expected := event, testCase.expected; !reflect.DeepEqual(actual, expected) {
+				t.Errorf("%s: did not get correct event: %v", testCase.name, cmp.Diff(actual, expected, allowEventAndDate))
+			}
+		})
+	}
+}
+
+func TestDigestComment(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		e               github.IssueCommentEvent
+		title           string
+		merged          bool
+		state           string
+		config          *Config
+		teams           map[string]fakegithub.TeamWithMembers
+		comments        []github.IssueComment
+		expected        *event
+		expectedComment string
+		expectedErr     bool
+	}{
+		{
+			name: "unrelated event gets ignored",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionDeleted,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
+				},
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
 		},
 		{
-			name: "title referencing non-bug jira gets an event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "SOMEJIRA-123: implement feature!",
-					State:   "open",
-					HTMLURL: "http.com",
+			name: "unrelated title gets an event saying so",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "cole, please review this typo fix",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: []referencedBug{{Key: "SOMEJIRA-123", IsBug: false}}, title: "SOMEJIRA-123: implement feature!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, missing: true, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
 			},
 		},
 		{
-			name: "title explicitly referencing no-issue gets an event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "NO-ISSUE: typo fixup",
-					State:   "open",
-					HTMLURL: "http.com",
-					User: github.User{
-						Login: "user",
+			name: "comment on issue gets no event but a comment",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number: 1,
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
 					},
+					Name: "repo",
 				},
 			},
-			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: nil, noJira: true, title: "NO-ISSUE: typo fixup", htmlUrl: "http.com", login: "user",
-			},
+			title: "someone misspelled words in this repo",
+			expectedComment: `org/repo#1:@: Jira bug referencing is only supported for Pull Requests, not issues.
+
+<details>
+
+In response to [this]():
+
+>/jira refresh
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
 		},
 		{
-			name: "title referencing no-jira gets an event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "NO-JIRA: typo fixup",
-					State:   "open",
-					HTMLURL: "http.com",
+			name: "title referencing bug gets an event",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: true, bugs: nil, noJira: true, title: "NO-JIRA: typo fixup", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
 			},
 		},
 		{
-			name: "title referencing bug gets an event on PR merge",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionClosed,
-				PullRequest: github.PullRequest{
-					Merged: true,
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "OCPBUGS-123: fixed it!",
-					HTMLURL: "http.com",
+			name: "title referencing multiple bugs gets an event",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "OCPBUGS-123,OCPBUGS-124: oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, merged: true, closed: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}}, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
 			},
 		},
 		{
-			name: "title referencing bug gets an event on PR close",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionClosed,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "OCPBUGS-123: fixed it!",
-					HTMLURL: "http.com",
+			name: "title referencing bug and jira issue gets an event",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "OCPBUGS-123,JIRA-123: oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, merged: false, closed: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "JIRA-123", IsBug: false}}, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
 			},
 		},
 		{
-			name: "non-jira cherrypick PR sets e.missing to true",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "release-4.4",
-					},
-					Number:  3,
-					Title:   "[release-4.4] fixing a typo",
-					HTMLURL: "http.com",
+			name: "title referencing jira gets an event",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
 					User: github.User{
 						Login: "user",
 					},
-					Body: `This is an automated cherry-pick of #2
-
-/assign user`,
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "SOMEJIRA-123: oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "release-4.4", number: 3, opened: true, body: "This is an automated cherry-pick of #2\n\n/assign user", title: "[release-4.4] fixing a typo", htmlUrl: "http.com", login: "user", cherrypick: true, cherrypickFromPRNum: 2, missing: true,
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "SOMEJIRA-123", IsBug: false}}, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
 			},
 		},
 		{
-			name: "cherrypicked PR gets cherrypick event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "release-4.4",
-					},
-					Number:  3,
-					Title:   "[release-4.4] OCPBUGS-123: fixed it!",
-					HTMLURL: "http.com",
+			name: "title referencing no-jira gets an event",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
 					User: github.User{
 						Login: "user",
 					},
-					Body: `This is an automated cherry-pick of #2
-
-/assign user`,
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "NO-JIRA: oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "release-4.4", number: 3, opened: true, body: "This is an automated cherry-pick of #2\n\n/assign user", title: "[release-4.4] OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user", cherrypick: true, cherrypickFromPRNum: 2, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: nil, noJira: true, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
 			},
 		},
 		{
-			name: "edited cherrypicked PR gets normal event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionEdited,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "release-4.4",
-					},
-					Number:  3,
-					Title:   "[release-4.4] OCPBUGS-123: fixed it!",
-					HTMLURL: "http.com",
+			name: "title referencing no-issue gets an event",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
 					User: github.User{
 						Login: "user",
 					},
-					Body: `This is an automated cherry-pick of #2
-
-/assign user`,
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "NO-ISSUE: oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "release-4.4", number: 3, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "This is an automated cherry-pick of #2\n\n/assign user", title: "[release-4.4] OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: nil, noJira: true, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
 			},
 		},
 		{
-			name: "title change referencing same bug gets no event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "OCPBUGS-123: fixed it!",
-					HTMLURL: "http.com",
+			name: "title referencing bug in a merged PR gets an event",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira refresh",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
 				},
-				Changes: []byte(`{"title":{"from":"OCPBUGS-123: fixed it! (WIP)"}}`),
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
+				},
+			},
+			title:  "OCPBUGS-123: oopsie doopsie",
+			merged: true,
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, merged: true, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
 			},
 		},
 		{
-			name: "title change referencing new bug gets event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "OCPBUGS-123: fixed it!",
-					HTMLURL: "http.com",
+			name: "cc-qa comment event has cc bool set to true",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira cc-qa",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
-				Changes: []byte(`{"title":{"from":"fixed it! (WIP)"}}`),
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, opened: true, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira cc-qa", htmlUrl: "www.com", login: "user", cc: true,
 			},
 		},
 		{
-			name: "title change dereferencing bug gets event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "fixed it!",
-					HTMLURL: "http.com",
+			name: "retry comment event has retry bool set to true",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira retry",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
-				Changes: []byte(`{"title":{"from":"OCPBUGS-123: fixed it! (WIP)"}}`),
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, opened: true, missing: true, title: "fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira retry", htmlUrl: "www.com", login: "user", retry: true,
 			},
 		},
 		{
-			name: "title change to no bug with unrelated changes gets no event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionOpened,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "fixed it!",
-					HTMLURL: "http.com",
+			name: "retry comment event on closed PR is marked closed",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira retry",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
 				},
-				Changes: []byte(`{"oops":{"doops":"payload"}}`),
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
+				},
+			},
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "closed",
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "closed", closed: true, body: "/jira retry", htmlUrl: "www.com", login: "user", retry: true,
 			},
 		},
 		{
-			name: "qe-approved labeling gets event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionLabeled,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "OCPBUGS-123: fixed it!",
-					State:   "open",
-					HTMLURL: "http.com",
+			name: "prs comment event has listPRs bool set to true",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira prs",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
 				},
-				Label: github.Label{
-					Name: labels.QEApproved,
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: false, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira prs", htmlUrl: "www.com", login: "user", listPRs: true,
 			},
 		},
 		{
-			name: "qe-approved unlabeling gets event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionUnlabeled,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "OCPBUGS-123: fixed it!",
-					State:   "open",
-					HTMLURL: "http.com",
+			name: "fix-labels comment event has fixLabels bool set to true",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira fix-labels",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
 				},
-				Label: github.Label{
-					Name: labels.QEApproved,
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, state: "open", opened: false, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, title: "OCPBUGS-123: fixed it!", htmlUrl: "http.com", login: "user",
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira fix-labels", htmlUrl: "www.com", login: "user", fixLabels: true,
 			},
 		},
 		{
-			name: "non qe-approved labeling does not get event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionLabeled,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "OCPBUGS-123: fixed it!",
-					State:   "open",
-					HTMLURL: "http.com",
+			name: "fix-version-from-branch comment event has fixVersion bool set to true",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira fix-version-from-branch",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
 				},
-				Label: github.Label{
-					Name: labels.JiraValidRef,
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira fix-version-from-branch", htmlUrl: "www.com", login: "user", fixVersion: true,
+			},
 		},
 		{
-			name: "qe-approved unlabeling does not get event",
-			pre: github.PullRequestEvent{
-				Action: github.PullRequestActionUnlabeled,
-				PullRequest: github.PullRequest{
-					Base: github.PullRequestBranch{
-						Repo: github.Repo{
-							Owner: github.User{
-								Login: "org",
-							},
-							Name: "repo",
-						},
-						Ref: "branch",
-					},
-					Number:  1,
-					Title:   "OCPBUGS-123: fixed it!",
-					State:   "open",
-					HTMLURL: "http.com",
+			name: "add-link comment event has addLink bool set to true",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira add-link",
 					User: github.User{
 						Login: "user",
 					},
+					HTMLURL: "www.com",
 				},
-				Label: github.Label{
-					Name: labels.JiraValidRef,
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
 				},
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira add-link", htmlUrl: "www.com", login: "user", addLink: true,
+			},
 		},
-	}
-
-	for _, testCase := range testCases {
-		t.Run(testCase.name, func(t *testing.T) {
-			event, err := digestPR(logrus.WithField("testCase", testCase.name), testCase.pre, testCase.validateByDefault)
-			if err == nil && testCase.expectedErr {
-				t.Errorf("%s: expected an error but got none", testCase.name)
-			}
-			if err != nil && !testCase.expectedErr {
-				t.Errorf("%s: expected no error but got one: %v", testCase.name, err)
-			}
-
-			if actual, expected := event, testCase.expected; !reflect.DeepEqual(actual, expected) {
-				t.Errorf("%s: did not get correct event: %v", testCase.name, cmp.Diff(actual, expected, allowEventAndDate))
-			}
-		})
-	}
-}
-
-func TestDigestComment(t *testing.T) {
-	var testCases = []struct {
-		name            string
-		e               github.IssueCommentEvent
-		title           string
-		merged          bool
-		expected        *event
-		expectedComment string
-		expectedErr     bool
-	}{
 		{
-			name: "unrelated event gets ignored",
+			name: "simulate-merge comment event has simulateMerge bool set to true",
 			e: github.IssueCommentEvent{
-				Action: github.IssueCommentActionDeleted,
+				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
 					Number:      1,
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira simulate-merge",
+					User: github.User{
+						Login: "user",
+					},
+					HTMLURL: "www.com",
 				},
 				Repo: github.Repo{
 					Owner: github.User{
@@ -3683,9 +8057,13 @@ func TestDigestComment(t *testing.T) {
 				},
 			},
 			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira simulate-merge", htmlUrl: "www.com", login: "user", simulateMerge: true,
+			},
 		},
 		{
-			name: "unrelated title gets an event saying so",
+			name: "verify comment event has verify bool set to true",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3693,7 +8071,7 @@ func TestDigestComment(t *testing.T) {
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira verify",
 					User: github.User{
 						Login: "user",
 					},
@@ -3706,20 +8084,27 @@ func TestDigestComment(t *testing.T) {
 					Name: "repo",
 				},
 			},
-			title: "cole, please review this typo fix",
+			title:  "OCPBUGS-123: oopsie doopsie",
+			state:  "closed",
+			merged: true,
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, missing: true, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, merged: true, state: "closed", body: "/jira verify", htmlUrl: "www.com", login: "user", verify: true,
 			},
 		},
 		{
-			name: "comment on issue gets no event but a comment",
+			name: "verify comment event by a user on the allowlist has verify bool set to true",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
-					Number: 1,
+					Number:      1,
+					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira verify",
+					User: github.User{
+						Login: "user",
+					},
+					HTMLURL: "www.com",
 				},
 				Repo: github.Repo{
 					Owner: github.User{
@@ -3728,21 +8113,16 @@ func TestDigestComment(t *testing.T) {
 					Name: "repo",
 				},
 			},
-			title: "someone misspelled words in this repo",
-			expectedComment: `org/repo#1:@: Jira bug referencing is only supported for Pull Requests, not issues.
-
-<details>
-
-In response to [this]():
-
->/jira refresh
-
-
-Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
-</details>`,
+			title:  "OCPBUGS-123: oopsie doopsie",
+			state:  "closed",
+			merged: true,
+			config: &Config{Orgs: map[string]JiraOrgOptions{"org": {Repos: map[string]JiraRepoOptions{"repo": {PrivilegedCommandUsers: []string{"User"}}}}}},
+			expected: &event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, merged: true, state: "closed", body: "/jira verify", htmlUrl: "www.com", login: "user", verify: true,
+			},
 		},
 		{
-			name: "title referencing bug gets an event",
+			name: "verify comment event by a member of an allowlisted team has verify bool set to true",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3750,7 +8130,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira verify",
 					User: github.User{
 						Login: "user",
 					},
@@ -3763,13 +8143,17 @@ Instructions for interacting with me using PR comments are available [here](http
 					Name: "repo",
 				},
 			},
-			title: "OCPBUGS-123: oopsie doopsie",
+			title:  "OCPBUGS-123: oopsie doopsie",
+			state:  "closed",
+			merged: true,
+			config: &Config{Orgs: map[string]JiraOrgOptions{"org": {Repos: map[string]JiraRepoOptions{"*": {PrivilegedCommandTeams: []string{"maintainers"}}}}}},
+			teams:  map[string]fakegithub.TeamWithMembers{"maintainers": {Members: sets.NewString("user")}},
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, merged: true, state: "closed", body: "/jira verify", htmlUrl: "www.com", login: "user", verify: true,
 			},
 		},
 		{
-			name: "title referencing multiple bugs gets an event",
+			name: "verify comment event by a user not on the allowlist is rejected",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3777,7 +8161,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira verify",
 					User: github.User{
 						Login: "user",
 					},
@@ -3790,13 +8174,24 @@ Instructions for interacting with me using PR comments are available [here](http
 					Name: "repo",
 				},
 			},
-			title: "OCPBUGS-123,OCPBUGS-124: oopsie doopsie",
-			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "OCPBUGS-124", IsBug: true}}, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
-			},
+			title:  "OCPBUGS-123: oopsie doopsie",
+			state:  "closed",
+			merged: true,
+			config: &Config{Orgs: map[string]JiraOrgOptions{"org": {Repos: map[string]JiraRepoOptions{"repo": {PrivilegedCommandUsers: []string{"maintainer"}, PrivilegedCommandTeams: []string{"maintainers"}}}}}},
+			expectedComment: `org/repo#1:@user: You are not allowed to run privileged <code>/jira</code> commands in org/repo. Ask one of the users allowed to run them to do it for you, or ask a maintainer to add you to the allowlist in the plugin configuration.
+
+<details>
+
+In response to [this](www.com):
+
+>/jira verify
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
 		},
 		{
-			name: "title referencing bug and jira issue gets an event",
+			name: "add-link comment event by a user not on the allowlist is rejected",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3804,7 +8199,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira add-link",
 					User: github.User{
 						Login: "user",
 					},
@@ -3817,13 +8212,23 @@ Instructions for interacting with me using PR comments are available [here](http
 					Name: "repo",
 				},
 			},
-			title: "OCPBUGS-123,JIRA-123: oopsie doopsie",
-			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}, {Key: "JIRA-123", IsBug: false}}, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
-			},
+			title:  "OCPBUGS-123: oopsie doopsie",
+			state:  "open",
+			config: &Config{Orgs: map[string]JiraOrgOptions{"org": {Repos: map[string]JiraRepoOptions{"repo": {PrivilegedCommandUsers: []string{"maintainer"}, PrivilegedCommandTeams: []string{"maintainers"}}}}}},
+			expectedComment: `org/repo#1:@user: You are not allowed to run privileged <code>/jira</code> commands in org/repo. Ask one of the users allowed to run them to do it for you, or ask a maintainer to add you to the allowlist in the plugin configuration.
+
+<details>
+
+In response to [this](www.com):
+
+>/jira add-link
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
 		},
 		{
-			name: "title referencing jira gets an event",
+			name: "reclone comment event by a user on the allowlist has the parent to reclone from set",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3831,7 +8236,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira reclone ocpbugs-456",
 					User: github.User{
 						Login: "user",
 					},
@@ -3844,13 +8249,14 @@ Instructions for interacting with me using PR comments are available [here](http
 					Name: "repo",
 				},
 			},
-			title: "SOMEJIRA-123: oopsie doopsie",
+			title:  "OCPBUGS-123: oopsie doopsie",
+			config: &Config{Orgs: map[string]JiraOrgOptions{"org": {Repos: map[string]JiraRepoOptions{"repo": {PrivilegedCommandUsers: []string{"user"}}}}}},
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "SOMEJIRA-123", IsBug: false}}, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira reclone ocpbugs-456", htmlUrl: "www.com", login: "user", recloneParent: "OCPBUGS-456",
 			},
 		},
 		{
-			name: "title referencing no-jira gets an event",
+			name: "history comment event gets a timeline of the validations and transitions of the bot as a comment",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3858,7 +8264,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira history",
 					User: github.User{
 						Login: "user",
 					},
@@ -3871,13 +8277,45 @@ Instructions for interacting with me using PR comments are available [here](http
 					Name: "repo",
 				},
 			},
-			title: "NO-JIRA: oopsie doopsie",
-			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: nil, noJira: true, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
-			},
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
+			comments: []github.IssueComment{{
+				Body:      "This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is invalid:\n - expected the bug to target the \"v1\" version, but no target version was set",
+				User:      github.User{Login: fakegithub.Bot},
+				HTMLURL:   "https://github.com/org/repo/pull/1#issuecomment-1",
+				CreatedAt: time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC),
+			}, {
+				Body:      "/jira refresh",
+				User:      github.User{Login: "user"},
+				HTMLURL:   "https://github.com/org/repo/pull/1#issuecomment-2",
+				CreatedAt: time.Date(2023, 1, 2, 10, 30, 0, 0, time.UTC),
+			}, {
+				Body:      "This pull request references [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which is valid. The bug has been moved to the POST state.\n<!-- jira-lifecycle: {\"key\":\"OCPBUGS-123\",\"valid\":true,\"reasons\":[]} -->",
+				User:      github.User{Login: fakegithub.Bot},
+				HTMLURL:   "https://github.com/org/repo/pull/1#issuecomment-3",
+				CreatedAt: time.Date(2023, 1, 2, 11, 0, 0, 0, time.UTC),
+			}, {
+				Body:      "/retitle OCPBUGS-124: oopsie doopsie",
+				User:      github.User{Login: fakegithub.Bot},
+				HTMLURL:   "https://github.com/org/repo/pull/1#issuecomment-4",
+				CreatedAt: time.Date(2023, 1, 2, 11, 30, 0, 0, time.UTC),
+			}},
+			expectedComment: `org/repo#1:@user: The plugin took the following actions on this pull request, from the oldest to the most recent:
+ * [2023-01-02 10:00 UTC](https://github.com/org/repo/pull/1#issuecomment-1): OCPBUGS-123 was found invalid
+ * [2023-01-02 11:00 UTC](https://github.com/org/repo/pull/1#issuecomment-3): OCPBUGS-123 was found valid, OCPBUGS-123 was moved to the POST state
+
+<details>
+
+In response to [this](www.com):
+
+>/jira history
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
 		},
 		{
-			name: "title referencing no-issue gets an event",
+			name: "history comment event without prior actions of the bot says so",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3885,7 +8323,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira history",
 					User: github.User{
 						Login: "user",
 					},
@@ -3898,13 +8336,68 @@ Instructions for interacting with me using PR comments are available [here](http
 					Name: "repo",
 				},
 			},
-			title: "NO-ISSUE: oopsie doopsie",
-			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: nil, noJira: true, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
+			expectedComment: `org/repo#1:@user: The plugin has not validated or moved any bugs on this pull request yet.
+
+<details>
+
+In response to [this](www.com):
+
+>/jira history
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
+		},
+		{
+			name: "severity-map comment event gets the severity mapping as a comment",
+			e: github.IssueCommentEvent{
+				Action: github.IssueCommentActionCreated,
+				Issue: github.Issue{
+					Number:      1,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/jira severity-map",
+					User: github.User{
+						Login: "user",
+					},
+					HTMLURL: "www.com",
+				},
+				Repo: github.Repo{
+					Owner: github.User{
+						Login: "org",
+					},
+					Name: "repo",
+				},
 			},
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
+			expectedComment: `org/repo#1:@user: The severities of Jira bugs are mapped to the following labels, from the most to the least severe:
+
+| Severity | Label |
+| --- | --- |
+| Critical | ` + "`jira/severity-critical`" + ` |
+| Important | ` + "`jira/severity-important`" + ` |
+| Moderate | ` + "`jira/severity-moderate`" + ` |
+| Low | ` + "`jira/severity-low`" + ` |
+| Informational | ` + "`jira/severity-informational`" + ` |
+
+If the pull request references several bugs, only the label of the most severe one is added. Bugs with any other severity do not get a severity label.
+
+<details>
+
+In response to [this](www.com):
+
+>/jira severity-map
+
+
+Instructions for interacting with me using PR comments are available [here](https://git.k8s.io/community/contributors/guide/pull-requests.md).  If you have questions or suggestions related to my behavior, please file an issue against the [kubernetes/test-infra](https://github.com/kubernetes/test-infra/issues/new?title=Prow%20issue:) repository.
+</details>`,
 		},
 		{
-			name: "title referencing bug in a merged PR gets an event",
+			name: "relabel comment event has relabel bool set to true",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3912,7 +8405,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira refresh",
+					Body: "/jira relabel",
 					User: github.User{
 						Login: "user",
 					},
@@ -3925,14 +8418,14 @@ Instructions for interacting with me using PR comments are available [here](http
 					Name: "repo",
 				},
 			},
-			title:  "OCPBUGS-123: oopsie doopsie",
-			merged: true,
+			title: "OCPBUGS-123: oopsie doopsie",
+			state: "open",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, merged: true, body: "/jira refresh", htmlUrl: "www.com", login: "user", refresh: true, cc: false,
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, state: "open", body: "/jira relabel", htmlUrl: "www.com", login: "user", relabel: true,
 			},
 		},
 		{
-			name: "cc-qa comment event has cc bool set to true",
+			name: "cherrypick comment event has cherrypick bools set to true and correct bug key set",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3940,7 +8433,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira cc-qa",
+					Body: "/jira cherrypick OCPBUGS-1234",
 					User: github.User{
 						Login: "user",
 					},
@@ -3953,13 +8446,13 @@ Instructions for interacting with me using PR comments are available [here](http
 					Name: "repo",
 				},
 			},
-			title: "OCPBUGS-123: oopsie doopsie",
+			title: "oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}}, body: "/jira cc-qa", htmlUrl: "www.com", login: "user", cc: true,
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-1234", IsBug: true}}, body: "/jira cherrypick OCPBUGS-1234", htmlUrl: "www.com", login: "user", cherrypickCmd: true, missing: true, cherrypick: true,
 			},
 		},
 		{
-			name: "cherrypick comment event has cherrypick bools set to true and correct bug key set",
+			name: "cherrypick comment event with branches has the branches set",
 			e: github.IssueCommentEvent{
 				Action: github.IssueCommentActionCreated,
 				Issue: github.Issue{
@@ -3967,7 +8460,7 @@ Instructions for interacting with me using PR comments are available [here](http
 					PullRequest: &struct{}{},
 				},
 				Comment: github.IssueComment{
-					Body: "/jira cherrypick OCPBUGS-1234",
+					Body: "/jira cherrypick OCPBUGS-1234 --branches release-4.14,release-4.13",
 					User: github.User{
 						Login: "user",
 					},
@@ -3982,7 +8475,8 @@ Instructions for interacting with me using PR comments are available [here](http
 			},
 			title: "oopsie doopsie",
 			expected: &event{
-				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-1234", IsBug: true}}, body: "/jira cherrypick OCPBUGS-1234", htmlUrl: "www.com", login: "user", cherrypickCmd: true, missing: true, cherrypick: true,
+				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-1234", IsBug: true}}, body: "/jira cherrypick OCPBUGS-1234 --branches release-4.14,release-4.13", htmlUrl: "www.com", login: "user", cherrypickCmd: true, missing: true, cherrypick: true,
+				cherrypickBranches: []string{"release-4.14", "release-4.13"},
 			},
 		},
 		{
@@ -4037,6 +8531,7 @@ Instructions for interacting with me using PR comments are available [here](http
 			title: "OCPBUGS-123: oopsie doopsie",
 			expected: &event{
 				org: "org", repo: "repo", baseRef: "branch", number: 1, bugs: []referencedBug{{Key: "OCPBUGS-1234", IsBug: true}}, body: "/jira cherrypick OCPBUGS-1234\r\nThis is part of a\r\nmultiline comment", htmlUrl: "www.com", login: "user", cherrypickCmd: true, missing: false, cherrypick: true,
+				titleBugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
 			},
 		},
 	}
@@ -4045,10 +8540,16 @@ Instructions for interacting with me using PR comments are available [here](http
 		t.Run(testCase.name, func(t *testing.T) {
 			client := fakegithub.NewFakeClient()
 			client.PullRequests = map[int]*github.PullRequest{
-				1: {Base: github.PullRequestBranch{Ref: "branch"}, Title: testCase.title, Merged: testCase.merged},
+				1: {Base: github.PullRequestBranch{Ref: "branch"}, Title: testCase.title, Merged: testCase.merged, State: testCase.state},
 			}
+			client.Teams = map[string]map[string]fakegithub.TeamWithMembers{"org": testCase.teams}
+			client.IssueComments = map[int][]github.IssueComment{1: testCase.comments}
 			fakeClient := fakeGHClient{client}
-			event, err := digestComment(fakeClient, logrus.WithField("testCase", testCase.name), testCase.e)
+			config := testCase.config
+			if config == nil {
+				config = &Config{}
+			}
+			event, err := digestComment(fakeClient, config, logrus.WithField("testCase", testCase.name), testCase.e)
 			if err == nil && testCase.expectedErr {
 				t.Errorf("%s: expected an error but got none", testCase.name)
 			}
@@ -4085,6 +8586,18 @@ func TestBugKeyFromTitle(t *testing.T) {
 			title:           "OCPBUGS-12,OCPBUGS-13: Multiple Canonical",
 			expectedRefBugs: []referencedBug{{Key: "OCPBUGS-12", IsBug: true}, {Key: "OCPBUGS-13", IsBug: true}},
 		},
+		{
+			title:           "OCPBUGS-12:No space after colon",
+			expectedRefBugs: []referencedBug{{Key: "OCPBUGS-12", IsBug: true}},
+		},
+		{
+			title:           "OCPBUGS-12,OCPBUGS-13:Multiple without space after colon",
+			expectedRefBugs: []referencedBug{{Key: "OCPBUGS-12", IsBug: true}, {Key: "OCPBUGS-13", IsBug: true}},
+		},
+		{
+			title:           "[rebase release-1.0] OCPBUGS-12:Prefix without space after colon",
+			expectedRefBugs: []referencedBug{{Key: "OCPBUGS-12", IsBug: true}},
+		},
 		{
 			title:            "OCPBUGS-12 : Space before colon",
 			expectedRefBugs:  nil,
@@ -4148,6 +8661,19 @@ func TestBugKeyFromTitle(t *testing.T) {
 			expectedRefBugs: nil,
 			expectedNoJira:  true,
 		},
+		{
+			title:           "OCPBUGS-12: Bug 34: Jira key before Bugzilla ID",
+			expectedRefBugs: []referencedBug{{Key: "OCPBUGS-12", IsBug: true}},
+		},
+		{
+			title:           "Bug 34: OCPBUGS-12: Bugzilla ID before Jira key",
+			expectedRefBugs: []referencedBug{{Key: "OCPBUGS-12", IsBug: true}},
+		},
+		{
+			title:            "Bug 34: Bugzilla ID only",
+			expectedRefBugs:  nil,
+			expectedNotFound: true,
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.title, func(t *testing.T) {
@@ -4172,6 +8698,48 @@ func TestBugKeyFromTitle(t *testing.T) {
 	}
 }
 
+func TestIsRevertTitle(t *testing.T) {
+	var testCases = []struct {
+		title    string
+		expected bool
+	}{
+		{title: `Revert "OCPBUGS-12: fixed it"`, expected: true},
+		{title: `Revert: "OCPBUGS-12: fixed it"`, expected: true},
+		{title: `revert "OCPBUGS-12,OCPBUGS-13: fixed them"`, expected: true},
+		{title: `OCPBUGS-34: Revert: "OCPBUGS-12: fixed it"`, expected: false},
+		{title: "OCPBUGS-12: Revert the default", expected: false},
+		{title: "Reverting OCPBUGS-12: fixed it", expected: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.title, func(t *testing.T) {
+			if actual := isRevertTitle(testCase.title); actual != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	var testCases = []struct {
+		title    string
+		expected string
+	}{
+		{title: "no match", expected: "no match"},
+		{title: "OCPBUGS-12: Canonical", expected: "OCPBUGS-12: Canonical"},
+		{title: "OCPBUGS-12:No space", expected: "OCPBUGS-12: No space"},
+		{title: "OCPBUGS-12,OCPBUGS-13:Multiple", expected: "OCPBUGS-12,OCPBUGS-13: Multiple"},
+		{title: "[rebase release-1.0] OCPBUGS-12:Prefix", expected: "[rebase release-1.0] OCPBUGS-12: Prefix"},
+		{title: "OCPBUGS-12:", expected: "OCPBUGS-12:"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.title, func(t *testing.T) {
+			if actual := normalizeTitle(testCase.title); actual != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+}
+
 func TestValidateBug(t *testing.T) {
 	open, closed := true, false
 	oneStr, twoStr, threeStr := "v1", "v2", "v3"
@@ -4181,11 +8749,20 @@ func TestValidateBug(t *testing.T) {
 	verified := JiraBugState{Status: "VERIFIED"}
 	modified := JiraBugState{Status: "MODIFIED"}
 	updated := JiraBugState{Status: "UPDATED"}
+	verificationField := "customfield_1"
+	storyPointsField := "customfield_2"
+	flaggedField := "customfield_3"
+	releaseNoteTypeField := "customfield_4"
+	fieldEditURLTemplate := "https://my-jira.com/edit?key={{.Key}}#{{.Field}}"
+	board := 7
+	ocpbugsProject := "OCPBUGS"
 	var testCases = []struct {
 		name        string
 		issue       *jira.Issue
 		dependents  []dependent
+		onBoard     *bool
 		options     JiraBranchOptions
+		branch      string
 		valid       bool
 		validations []string
 		why         []string
@@ -4225,40 +8802,399 @@ func TestValidateBug(t *testing.T) {
 			why:     []string{"expected the bug to not be open, but it is"},
 		},
 		{
-			name: "matching target version requirement means a valid bug",
+			name: "matching target version requirement means a valid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &one,
+				},
+			}},
+			options:     JiraBranchOptions{TargetVersion: &oneStr},
+			valid:       true,
+			validations: []string{"bug target version (v1) matches configured target version for branch (v1)"},
+		},
+		{
+			name: "matching any of the target versions requirement means a valid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &two,
+				},
+			}},
+			options:     JiraBranchOptions{TargetVersions: &[]string{oneStr, twoStr}},
+			valid:       true,
+			validations: []string{"bug target version matches one of the configured target versions for branch (v1, v2)"},
+		},
+		{
+			name: "target version is treated as one more of the target versions",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &one,
+				},
+			}},
+			options:     JiraBranchOptions{TargetVersion: &oneStr, TargetVersions: &[]string{twoStr}},
+			valid:       true,
+			validations: []string{"bug target version matches one of the configured target versions for branch (v1, v2)"},
+		},
+		{
+			name: "not matching any of the target versions requirement means an invalid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Type: jira.IssueType{
+					Name: "Bug",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &[]*jira.Version{{Name: threeStr}},
+				},
+			}},
+			options: JiraBranchOptions{TargetVersions: &[]string{oneStr, twoStr}},
+			valid:   false,
+			why:     []string{`expected the bug to target one of the following versions: v1, v2, but it targets "v3" instead`},
+		},
+		{
+			name: "not setting target version with target versions requirement means an invalid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Type: jira.IssueType{
+					Name: "Bug",
+				},
+			}},
+			options: JiraBranchOptions{TargetVersions: &[]string{oneStr, twoStr}},
+			valid:   false,
+			why:     []string{"expected the bug to target one of the following versions: v1, v2, but no target version was set"},
+		},
+		{
+			name: "matching prefixed target version requirement means a valid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &three,
+				},
+			}},
+			options:     JiraBranchOptions{TargetVersion: &threeStr},
+			valid:       true,
+			validations: []string{"bug target version (v3) matches configured target version for branch (v3)"},
+		},
+		{
+			name: "not matching target version requirement means an invalid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Type: jira.IssueType{
+					Name: "Bug",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &two,
+				},
+			}},
+			options: JiraBranchOptions{TargetVersion: &oneStr},
+			valid:   false,
+			why:     []string{"expected the bug to target either version \"v1.*\" or \"openshift-v1.*\", but it targets \"v2\" instead"},
+		},
+		{
+			name: "multiple target versions with one matching requirement means a valid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &[]*jira.Version{{Name: "v2"}, {Name: "v1"}},
+				},
+			}},
+			options:     JiraBranchOptions{TargetVersion: &oneStr},
+			valid:       true,
+			validations: []string{"bug target version (v1) matches configured target version for branch (v1)"},
+		},
+		{
+			name: "multiple target versions with none matching requirement means an invalid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Type: jira.IssueType{
+					Name: "Bug",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &[]*jira.Version{{Name: "v2"}, {Name: "v3"}},
+				},
+			}},
+			options: JiraBranchOptions{TargetVersion: &oneStr},
+			valid:   false,
+			why:     []string{"expected the bug to target either version \"v1.*\" or \"openshift-v1.*\", but it targets \"v2\", \"v3\" instead"},
+		},
+		{
+			name: "target version consistent with branch means a valid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &[]*jira.Version{{Name: "4.14.0"}},
+				},
+			}},
+			options:     JiraBranchOptions{ValidateBranchTargetConsistency: &open, BranchTargetVersions: map[string]string{"master": "4.14", "release-4.13": "4.13"}},
+			branch:      "master",
+			valid:       true,
+			validations: []string{"bug target version is consistent with the \"master\" branch, which expects bugs targeting \"4.14\""},
+		},
+		{
+			name: "target version inconsistent with branch means an invalid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Type: jira.IssueType{
+					Name: "Bug",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &[]*jira.Version{{Name: "4.13.z"}},
+				},
+			}},
+			options: JiraBranchOptions{ValidateBranchTargetConsistency: &open, BranchTargetVersions: map[string]string{"master": "4.14", "release-4.13": "4.13"}},
+			branch:  "master",
+			valid:   false,
+			why:     []string{"the bug's target version is not consistent with the \"master\" branch, which expects bugs targeting \"4.14\": expected the bug to target either version \"4.14.*\" or \"openshift-4.14.*\", but it targets \"4.13.z\" instead"},
+		},
+		{
+			name: "target version inconsistent with branch is ignored when consistency check is disabled",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &[]*jira.Version{{Name: "4.13.z"}},
+				},
+			}},
+			options: JiraBranchOptions{ValidateBranchTargetConsistency: &closed, BranchTargetVersions: map[string]string{"master": "4.14"}},
+			branch:  "master",
+			valid:   true,
+		},
+		{
+			name:        "reporter matching an allowed name means a valid bug",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Reporter: &jira.User{Name: "alice", AccountID: "1234"}}},
+			options:     JiraBranchOptions{AllowedReporters: []string{"alice", "bob"}},
+			valid:       true,
+			validations: []string{"bug was reported by one of the allowed reporters"},
+		},
+		{
+			name:        "reporter matching an allowed account ID means a valid bug",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Reporter: &jira.User{AccountID: "1234"}}},
+			options:     JiraBranchOptions{AllowedReporters: []string{"1234"}},
+			valid:       true,
+			validations: []string{"bug was reported by one of the allowed reporters"},
+		},
+		{
+			name:    "reporter not in allowed reporters means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{Reporter: &jira.User{Name: "mallory", AccountID: "5678"}}},
+			options: JiraBranchOptions{AllowedReporters: []string{"alice", "bob"}},
+			valid:   false,
+			why:     []string{"expected the bug to be reported by one of the allowed reporters (alice, bob), but it was reported by mallory"},
+		},
+		{
+			name:    "missing reporter with allowed reporters means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{}},
+			options: JiraBranchOptions{AllowedReporters: []string{"alice"}},
+			valid:   false,
+			why:     []string{"expected the bug to be reported by one of the allowed reporters (alice), but it has no reporter"},
+		},
+		{
+			name: "populated verification field means a valid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{"customfield_1": "https://polarion.example.com/testcase/1"},
+			}},
+			options:     JiraBranchOptions{RequireVerificationField: &open, VerificationField: &verificationField},
+			valid:       true,
+			validations: []string{"bug has its verification field set"},
+		},
+		{
+			name:    "empty verification field means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_1": " "}}},
+			options: JiraBranchOptions{RequireVerificationField: &open, VerificationField: &verificationField},
+			valid:   false,
+			why:     []string{"expected the bug to have its verification field set, but it does not"},
+		},
+		{
+			name:    "missing verification field means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{}},
+			options: JiraBranchOptions{RequireVerificationField: &open, VerificationField: &verificationField},
+			valid:   false,
+			why:     []string{"expected the bug to have its verification field set, but it does not"},
+		},
+		{
+			name:    "required verification field without configured field ID means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_1": "tested"}}},
+			options: JiraBranchOptions{RequireVerificationField: &open},
+			valid:   false,
+			why:     []string{"the bug's verification field must be set, but no verification field is configured for this repository"},
+		},
+		{
+			name:    "bug targeting a version that is not one of the configured known versions means an invalid bug",
+			issue:   &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.TargetVersionField: &one}}},
+			options: JiraBranchOptions{RequireKnownTargetVersion: &open, KnownTargetVersions: &[]string{twoStr}},
+			valid:   false,
+			why:     []string{"target version v1 is not a known version for project OCPBUGS"},
+		},
+		{
+			name:        "bug with a release note type means a valid bug",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_4": map[string]interface{}{"value": "Bug Fix"}}}},
+			options:     JiraBranchOptions{RequireReleaseNoteType: &open, ReleaseNoteTypeField: &releaseNoteTypeField},
+			valid:       true,
+			validations: []string{"bug has its release note type set (Bug Fix)"},
+		},
+		{
+			name:    "bug without a release note type means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_4": nil}}},
+			options: JiraBranchOptions{RequireReleaseNoteType: &open, ReleaseNoteTypeField: &releaseNoteTypeField},
+			valid:   false,
+			why:     []string{"expected the bug to have its release note type set, but it does not"},
+		},
+		{
+			name:        "estimated bug means a valid bug",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_2": 3}}},
+			options:     JiraBranchOptions{RequireStoryPoints: &open, StoryPointsField: &storyPointsField},
+			valid:       true,
+			validations: []string{"bug has its story points set (3)"},
+		},
+		{
+			name:    "bug without story points means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_2": nil}}},
+			options: JiraBranchOptions{RequireStoryPoints: &open, StoryPointsField: &storyPointsField},
+			valid:   false,
+			why:     []string{"expected the bug to have its story points set, but it does not"},
+		},
+		{
+			name:    "required story points without configured field ID means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_2": 3}}},
+			options: JiraBranchOptions{RequireStoryPoints: &open},
+			valid:   false,
+			why:     []string{"the bug's story points must be set, but no story points field is configured for this repository"},
+		},
+		{
+			name:        "bug that is not flagged means a valid bug",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_3": nil}}},
+			options:     JiraBranchOptions{RejectFlaggedBlocked: &open, FlaggedField: &flaggedField},
+			valid:       true,
+			validations: []string{"bug is not flagged as blocked"},
+		},
+		{
+			name:    "flagged bug means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_3": []interface{}{map[string]interface{}{"value": "Impediment"}}}}},
+			options: JiraBranchOptions{RejectFlaggedBlocked: &open, FlaggedField: &flaggedField},
+			valid:   false,
+			why:     []string{"the referenced bug is flagged as blocked"},
+		},
+		{
+			name:    "flagged bug is valid when flagged bugs are not rejected",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_3": []interface{}{map[string]interface{}{"value": "Impediment"}}}}},
+			options: JiraBranchOptions{FlaggedField: &flaggedField},
+			valid:   true,
+		},
+		{
+			name:    "field edit URL template links status failures to the status field",
+			issue:   &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "CLOSED"}}},
+			options: JiraBranchOptions{IsOpen: &open, FieldEditURLTemplate: &fieldEditURLTemplate},
+			valid:   false,
+			why:     []string{"expected the bug to be open, but it isn't ([edit](https://my-jira.com/edit?key=OCPBUGS-123#status))"},
+		},
+		{
+			name: "field edit URL template links target version failures to the target version field",
+			issue: &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{
+				Type: jira.IssueType{
+					Name: "Bug",
+				},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &two,
+				},
+			}},
+			options: JiraBranchOptions{TargetVersion: &oneStr, FieldEditURLTemplate: &fieldEditURLTemplate},
+			valid:   false,
+			why:     []string{`expected the bug to target either version "v1.*" or "openshift-v1.*", but it targets "v2" instead ([edit](https://my-jira.com/edit?key=OCPBUGS-123#customfield_12323140))`},
+		},
+		{
+			name:    "unconfigured field edit URL template leaves failures unchanged",
+			issue:   &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "CLOSED"}}},
+			options: JiraBranchOptions{IsOpen: &open},
+			valid:   false,
+			why:     []string{"expected the bug to be open, but it isn't"},
+		},
+		{
+			name: "bug with a rejected resolution means an invalid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "Won't Do"},
+			}},
+			options: JiraBranchOptions{RejectResolutions: []string{"Not a Bug", "Won't Do"}},
+			valid:   false,
+			why:     []string{"expected the bug not to be resolved as one of the following: Not a Bug, Won't Do, but it is resolved as Won't Do; reopen the bug or link this pull request to a different bug"},
+		},
+		{
+			name: "bug with an accepted resolution means a valid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Status:     &jira.Status{Name: "CLOSED"},
+				Resolution: &jira.Resolution{Name: "ERRATA"},
+			}},
+			options:     JiraBranchOptions{RejectResolutions: []string{"Not a Bug", "Won't Do"}},
+			valid:       true,
+			validations: []string{"bug is not resolved as one of the rejected resolutions (Not a Bug, Won't Do)"},
+		},
+		{
+			name: "bug already fixed in the target version means an invalid bug",
+			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Status:      &jira.Status{Name: "CLOSED"},
+				Resolution:  &jira.Resolution{Name: "Done"},
+				FixVersions: []*jira.FixVersion{{Name: "v1"}},
+				Unknowns: tcontainer.MarshalMap{
+					helpers.TargetVersionField: &one,
+				},
+			}},
+			options:     JiraBranchOptions{TargetVersion: &oneStr, RejectIfAlreadyFixedInTarget: &open},
+			valid:       false,
+			validations: []string{"bug target version (v1) matches configured target version for branch (v1)"},
+			why:         []string{"expected the bug not to already be fixed in the target version, but it is resolved as Done with fix version v1; a backport is not needed, so link this pull request to a different bug or reopen the bug if the fix is incomplete"},
+		},
+		{
+			name: "bug fixed in a different version than the target version means a valid bug",
 			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Status:      &jira.Status{Name: "CLOSED"},
+				Resolution:  &jira.Resolution{Name: "Done"},
+				FixVersions: []*jira.FixVersion{{Name: "v2"}},
 				Unknowns: tcontainer.MarshalMap{
 					helpers.TargetVersionField: &one,
 				},
 			}},
-			options:     JiraBranchOptions{TargetVersion: &oneStr},
-			valid:       true,
-			validations: []string{"bug target version (v1) matches configured target version for branch (v1)"},
+			options: JiraBranchOptions{TargetVersion: &oneStr, RejectIfAlreadyFixedInTarget: &open},
+			valid:   true,
+			validations: []string{
+				"bug target version (v1) matches configured target version for branch (v1)",
+				"bug is not already fixed in the target version (v1)",
+			},
 		},
 		{
-			name: "matching prefixed target version requirement means a valid bug",
+			name: "unresolved bug with a fix version for the target version means a valid bug",
 			issue: &jira.Issue{Fields: &jira.IssueFields{
+				Status:      &jira.Status{Name: "POST"},
+				FixVersions: []*jira.FixVersion{{Name: "v1"}},
 				Unknowns: tcontainer.MarshalMap{
-					helpers.TargetVersionField: &three,
+					helpers.TargetVersionField: &one,
 				},
 			}},
-			options:     JiraBranchOptions{TargetVersion: &threeStr},
+			options: JiraBranchOptions{TargetVersion: &oneStr, RejectIfAlreadyFixedInTarget: &open},
+			valid:   true,
+			validations: []string{
+				"bug target version (v1) matches configured target version for branch (v1)",
+				"bug is not already fixed in the target version (v1)",
+			},
+		},
+		{
+			name:        "bug on the required board means a valid bug",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{}},
+			onBoard:     &open,
+			options:     JiraBranchOptions{RequiredBoardID: &board},
 			valid:       true,
-			validations: []string{"bug target version (v3) matches configured target version for branch (v3)"},
+			validations: []string{"bug is on board 7"},
 		},
 		{
-			name: "not matching target version requirement means an invalid bug",
+			name:    "bug not on the required board means an invalid bug",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{}},
+			onBoard: &closed,
+			options: JiraBranchOptions{RequiredBoardID: &board},
+			valid:   false,
+			why:     []string{"expected the bug to be on board 7, but it is not"},
+		},
+		{
+			name:    "verification field is not checked when not required",
+			issue:   &jira.Issue{Fields: &jira.IssueFields{}},
+			options: JiraBranchOptions{RequireVerificationField: &closed, VerificationField: &verificationField},
+			valid:   true,
+		},
+		{
+			name: "branch without expected version is not checked for consistency",
 			issue: &jira.Issue{Fields: &jira.IssueFields{
-				Type: jira.IssueType{
-					Name: "Bug",
-				},
 				Unknowns: tcontainer.MarshalMap{
-					helpers.TargetVersionField: &two,
+					helpers.TargetVersionField: &[]*jira.Version{{Name: "4.13.z"}},
 				},
 			}},
-			options: JiraBranchOptions{TargetVersion: &oneStr},
-			valid:   false,
-			why:     []string{"expected the bug to target either version \"v1.*\" or \"openshift-v1.*\", but it targets \"v2\" instead"},
+			options: JiraBranchOptions{ValidateBranchTargetConsistency: &open, BranchTargetVersions: map[string]string{"master": "4.14"}},
+			branch:  "release-4.12",
+			valid:   true,
 		},
 		{
 			name: "not setting target version requirement means an invalid bug",
@@ -4315,6 +9251,23 @@ func TestValidateBug(t *testing.T) {
 			validations: []string{"bug has dependents"},
 			why:         []string{"expected dependent [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) to be in one of the following states: VERIFIED, but it is MODIFIED instead"},
 		},
+		{
+			name:        "dependent bug filed against the same component means a valid bug",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Components: []*jira.Component{{Name: "Networking"}}}},
+			dependents:  []dependent{{key: "OCPBUGS-124", bugState: JiraBugState{Status: "MODIFIED"}, components: []string{"Networking"}}},
+			options:     JiraBranchOptions{RequireDependentSameComponent: &open},
+			valid:       true,
+			validations: []string{"dependent [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) is filed against the same components as the bug (Networking)", "bug has dependents"},
+		},
+		{
+			name:        "dependent bug filed against a different component means an invalid bug",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Components: []*jira.Component{{Name: "Networking"}}}},
+			dependents:  []dependent{{key: "OCPBUGS-124", bugState: JiraBugState{Status: "MODIFIED"}, components: []string{"Storage"}}},
+			options:     JiraBranchOptions{RequireDependentSameComponent: &open},
+			valid:       false,
+			validations: []string{"bug has dependents"},
+			why:         []string{"expected dependent [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) to be filed against the same components as the bug (Networking), but it is filed against Storage instead"},
+		},
 		{
 			name:        "not matching dependent bug target version requirement means an invalid bug",
 			issue:       &jira.Issue{Fields: &jira.IssueFields{}},
@@ -4501,11 +9454,168 @@ func TestValidateBug(t *testing.T) {
 				"dependent bug OCPBUGSM-38676 is not in the required `OCPBUGS` project",
 			},
 		},
+		{
+			name:        "original bug when the original bug is required means a valid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{IssueLinks: []*jira.IssueLink{{Type: jira.IssueLinkType{Name: "Cloners", Inward: "is cloned by", Outward: "clones"}, InwardIssue: &jira.Issue{Key: "OCPBUGS-124"}}}}},
+			options:     JiraBranchOptions{RequireOriginalBug: &open},
+			valid:       true,
+			validations: []string{"bug is the original bug rather than a clone"},
+		},
+		{
+			name:    "clone when the original bug is required means an invalid bug",
+			issue:   &jira.Issue{Key: "OCPBUGS-124", Fields: &jira.IssueFields{IssueLinks: []*jira.IssueLink{{Type: jira.IssueLinkType{Name: "Cloners", Inward: "is cloned by", Outward: "clones"}, OutwardIssue: &jira.Issue{Key: "OCPBUGS-123"}}}}},
+			options: JiraBranchOptions{RequireOriginalBug: &open},
+			valid:   false,
+			why:     []string{"expected the bug to be the original bug, but it is a clone of [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123); reference the original bug instead"},
+		},
+		{
+			name:        "environment field set when it is required means a valid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Environment: "s390x"}},
+			options:     JiraBranchOptions{RequireEnvironmentField: &open},
+			valid:       true,
+			validations: []string{"bug has the environment field set (s390x)"},
+		},
+		{
+			name:    "empty environment field when it is required means an invalid bug",
+			issue:   &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Environment: "  "}},
+			options: JiraBranchOptions{RequireEnvironmentField: &open},
+			valid:   false,
+			why:     []string{"expected the bug to have the environment field set to the affected platform, but it is empty"},
+		},
+		{
+			name:        "QA contact set when it is required means a valid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{helpers.QAContactField: &jira.User{DisplayName: "Quality Assurance", EmailAddress: "qa@example.com"}}}},
+			options:     JiraBranchOptions{RequireQaContact: &open},
+			valid:       true,
+			validations: []string{"bug has a QA contact (Quality Assurance)"},
+		},
+		{
+			name:    "no QA contact when it is required means an invalid bug",
+			issue:   &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
+			options: JiraBranchOptions{RequireQaContact: &open},
+			valid:   false,
+			why:     []string{"expected the bug to have a QA contact, but none is set"},
+		},
+		{
+			name:        "QA contact in a custom field when it is required means a valid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_5": &jira.User{EmailAddress: "qa@example.com"}}}},
+			options:     JiraBranchOptions{RequireQaContact: &open, CustomFields: &helpers.FieldMap{QAContact: "customfield_5"}},
+			valid:       true,
+			validations: []string{"bug has a QA contact (qa@example.com)"},
+		},
+		{
+			name:        "bug in the expected project means a valid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
+			options:     JiraBranchOptions{ExpectedProject: &ocpbugsProject},
+			valid:       true,
+			validations: []string{"bug is in the expected project (OCPBUGS)"},
+		},
+		{
+			name:    "bug in another project means an invalid bug",
+			issue:   &jira.Issue{Key: "RHEL-123", Fields: &jira.IssueFields{}},
+			options: JiraBranchOptions{ExpectedProject: &ocpbugsProject},
+			valid:   false,
+			why:     []string{"expected the bug to be in the OCPBUGS project, but it is in the RHEL project; reference a bug from the OCPBUGS project or clone this bug into it"},
+		},
+		{
+			name:  "resolved dependents when they are required to be resolved means a valid bug",
+			issue: &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
+			dependents: []dependent{
+				{key: "OCPBUGS-124", bugState: JiraBugState{Status: "VERIFIED", Resolution: "Done"}},
+				{key: "OCPBUGS-125", bugState: JiraBugState{Status: "Closed"}},
+			},
+			options:     JiraBranchOptions{RequireDependentsResolved: &open},
+			valid:       true,
+			validations: []string{"all dependents of the bug are resolved", "bug has dependents"},
+		},
+		{
+			name:  "unresolved dependents when they are required to be resolved means an invalid bug",
+			issue: &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
+			dependents: []dependent{
+				{key: "OCPBUGS-124", bugState: JiraBugState{Status: "POST"}},
+				{key: "OCPBUGS-125", bugState: JiraBugState{Status: "CLOSED", Resolution: "ERRATA"}},
+				{key: "OCPBUGS-126", bugState: JiraBugState{Status: "NEW"}},
+			},
+			options:     JiraBranchOptions{RequireDependentsResolved: &open},
+			valid:       false,
+			validations: []string{"bug has dependents"},
+			why:         []string{"expected all dependents of the bug to be resolved, but the following are not: [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) (POST), [Jira Issue OCPBUGS-126](https://my-jira.com/browse/OCPBUGS-126) (NEW)"},
+		},
+		{
+			name:        "dependents in the configured resolved states when they are required to be resolved means a valid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
+			dependents:  []dependent{{key: "OCPBUGS-124", bugState: JiraBugState{Status: "VERIFIED"}}},
+			options:     JiraBranchOptions{RequireDependentsResolved: &open, ResolvedStates: &[]JiraBugState{verified}},
+			valid:       true,
+			validations: []string{"all dependents of the bug are resolved", "bug has dependents"},
+		},
+		{
+			name:        "dependents outside of the configured resolved states when they are required to be resolved means an invalid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{}},
+			dependents:  []dependent{{key: "OCPBUGS-124", bugState: JiraBugState{Status: "CLOSED", Resolution: "Won't Fix"}}},
+			options:     JiraBranchOptions{RequireDependentsResolved: &open, ResolvedStates: &[]JiraBugState{verified}},
+			valid:       false,
+			validations: []string{"bug has dependents"},
+			why:         []string{"expected all dependents of the bug to be resolved, but the following are not: [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) (CLOSED (Won't Fix))"},
+		},
+		{
+			name:       "bug with dependents does not list them when disabled and no dependent validations are configured",
+			issue:      &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
+			dependents: []dependent{{key: "OCPBUGS-124", bugState: JiraBugState{Status: "MODIFIED"}}},
+			options:    JiraBranchOptions{ShowHasDependents: &closed},
+			valid:      true,
+		},
+		{
+			name:        "bug with dependents lists them when disabled but dependent validations are configured",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}},
+			dependents:  []dependent{{key: "OCPBUGS-124", bugState: JiraBugState{Status: "MODIFIED"}}},
+			options:     JiraBranchOptions{ShowHasDependents: &closed, DependentBugStates: &[]JiraBugState{modified}},
+			valid:       true,
+			validations: []string{"dependent bug [Jira Issue OCPBUGS-124](https://my-jira.com/browse/OCPBUGS-124) is in the state MODIFIED, which is one of the valid states (MODIFIED)", "bug has dependents"},
+		},
+		{
+			name:        "epic on a branch allowed for epics means a valid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Type: jira.IssueType{Name: "Epic"}}},
+			options:     JiraBranchOptions{IssueTypeBranches: map[string][]string{"epic": {"main|master"}}},
+			branch:      "master",
+			valid:       true,
+			validations: []string{`issues of type Epic are allowed on the "master" branch`},
+		},
+		{
+			name:    "epic on a z-stream branch means an invalid bug",
+			issue:   &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Type: jira.IssueType{Name: "Epic"}}},
+			options: JiraBranchOptions{IssueTypeBranches: map[string][]string{"Epic": {"main|master"}, "Bug": {".*"}}},
+			branch:  "release-4.13",
+			valid:   false,
+			why:     []string{`issues of type Epic are not allowed on the "release-4.13" branch, they are only allowed on branches matching main|master`},
+		},
+		{
+			name:        "bug with all required labels and no forbidden labels means a valid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Labels: []string{"triaged", "regression"}}},
+			options:     JiraBranchOptions{RequiredLabels: []string{"regression", "triaged"}, ForbiddenLabels: []string{"needs-info"}},
+			valid:       true,
+			validations: []string{"bug has the required labels (regression, triaged)", "bug has none of the forbidden labels (needs-info)"},
+		},
+		{
+			name:    "bug missing a required label means an invalid bug",
+			issue:   &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Labels: []string{"regression"}}},
+			options: JiraBranchOptions{RequiredLabels: []string{"regression", "triaged"}},
+			valid:   false,
+			why:     []string{"expected the bug to have the labels regression, triaged, but it is missing triaged"},
+		},
+		{
+			name:        "bug with a forbidden label means an invalid bug",
+			issue:       &jira.Issue{Key: "OCPBUGS-123", Fields: &jira.IssueFields{Labels: []string{"needs-info", "triaged"}}},
+			options:     JiraBranchOptions{RequiredLabels: []string{"triaged"}, ForbiddenLabels: []string{"needs-info", "wontfix"}},
+			valid:       false,
+			validations: []string{"bug has the required labels (triaged)"},
+			why:         []string{"expected the bug not to have any of the labels needs-info, wontfix, but it has needs-info"},
+		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			valid, validations, why := validateBug(testCase.issue, testCase.dependents, testCase.options, "https://my-jira.com")
+			valid, validations, why := validateBug(testCase.issue, testCase.dependents, testCase.onBoard, testCase.options, testCase.branch, "https://my-jira.com")
 			if valid != testCase.valid {
 				t.Errorf("%s: didn't validate bug correctly, expected %t got %t", testCase.name, testCase.valid, valid)
 			}
@@ -4631,6 +9741,7 @@ func TestIsBugAllowed(t *testing.T) {
 		name           string
 		bug            *jira.Issue
 		securityLevels []string
+		deniedLevels   []string
 		expected       bool
 	}{
 		{
@@ -4670,9 +9781,57 @@ func TestIsBugAllowed(t *testing.T) {
 			securityLevels: []string{"internal"},
 			expected:       false,
 		},
+		{
+			name: "denied level is not allowed when no allowed levels are configured",
+			bug: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					"security": jiraclient.SecurityLevel{Name: "embargoed"},
+				},
+			}},
+			deniedLevels: []string{"embargoed"},
+			expected:     false,
+		},
+		{
+			name: "level not in denied levels is allowed when no allowed levels are configured",
+			bug: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					"security": jiraclient.SecurityLevel{Name: "whoa"},
+				},
+			}},
+			deniedLevels: []string{"embargoed"},
+			expected:     true,
+		},
+		{
+			name: "denied level takes precedence over allowed level",
+			bug: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					"security": jiraclient.SecurityLevel{Name: "embargoed"},
+				},
+			}},
+			securityLevels: []string{"whoa", "embargoed"},
+			deniedLevels:   []string{"embargoed"},
+			expected:       false,
+		},
+		{
+			name: "allowed level not in denied levels is allowed",
+			bug: &jira.Issue{Fields: &jira.IssueFields{
+				Unknowns: tcontainer.MarshalMap{
+					"security": jiraclient.SecurityLevel{Name: "whoa"},
+				},
+			}},
+			securityLevels: []string{"whoa", "embargoed"},
+			deniedLevels:   []string{"embargoed"},
+			expected:       true,
+		},
+		{
+			name:         "default level can be denied",
+			bug:          &jira.Issue{Fields: &jira.IssueFields{}},
+			deniedLevels: []string{"default"},
+			expected:     false,
+		},
 	}
 	for _, testCase := range testCases {
-		actual, err := isBugAllowed(testCase.bug, testCase.securityLevels)
+		actual, err := isBugAllowed(testCase.bug, testCase.securityLevels, testCase.deniedLevels)
 		if err != nil {
 			// this error should never occur when run against a real jira server, so no need to test error handling
 			t.Fatalf("%s: unexpected error: %v", testCase.name, err)
@@ -4790,3 +9949,308 @@ func TestCheckTargetVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleSlackNotification(t *testing.T) {
+	yes := true
+	messages := make(chan slackMessage, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("failed to decode Slack message: %v", err)
+		}
+		messages <- message
+	}))
+	defer server.Close()
+	webhookURL := server.URL
+
+	gc := fakegithub.NewFakeClient()
+	gc.IssueLabelsExisting = []string{}
+	gc.IssueComments = map[int][]github.IssueComment{}
+	jiraClient := &fakejira.FakeClient{
+		Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "CLOSED"}}}},
+	}
+	e := event{
+		org: "org", repo: "repo", baseRef: "branch", number: 1,
+		bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+		body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+	}
+	options := JiraBranchOptions{IsOpen: &yes, SlackWebhookURL: &webhookURL}
+	if err := handle(jiraClient, fakeGHClient{gc}, &fakeAgileClient{}, options, logrus.WithField("testCase", t.Name()), e, sets.NewString("org/repo"), nil); err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+	if len(gc.IssueCommentsAdded) != 1 {
+		t.Errorf("expected the bug to be commented on in addition to the Slack notification, got comments: %v", gc.IssueCommentsAdded)
+	}
+
+	select {
+	case message := <-messages:
+		expected := slackMessage{Text: "<https://github.com/org/repo/pull/1|org/repo#1> references <https://my-jira.com/browse/OCPBUGS-123|OCPBUGS-123>, which is invalid:\n- expected the bug to be open, but it isn't"}
+		if diff := cmp.Diff(expected, message); diff != "" {
+			t.Errorf("got unexpected Slack message: %s", diff)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the Slack notification")
+	}
+}
+
+// emailQueryClient resolves public emails to the given GitHub logins, as the fake
+// github client does not implement graphql queries
+type emailQueryClient struct {
+	fakeGHClient
+	logins map[string]string
+}
+
+func (c emailQueryClient) QueryWithGitHubAppsSupport(ctx context.Context, q interface{}, vars map[string]interface{}, org string) error {
+	query, ok := q.(*emailToLoginQuery)
+	if !ok {
+		return nil
+	}
+	if login, ok := c.logins[string(vars["email"].(githubql.String))]; ok {
+		query.Search.Edges = append(query.Search.Edges, queryEdge{Node: queryNode{User: queryUser{Login: githubql.String(login)}}})
+	}
+	return nil
+}
+
+func TestHandleContributors(t *testing.T) {
+	yes := true
+	field := "customfield_1"
+	contributors := []interface{}{
+		map[string]interface{}{"name": "alice", "emailAddress": "alice@example.com"},
+		map[string]interface{}{"name": "bob", "emailAddress": "bob@example.com"},
+	}
+	var testCases = []struct {
+		name            string
+		author          string
+		require         bool
+		expectedLabel   string
+		expectedComment string
+	}{
+		{
+			name:            "author among the contributors is valid",
+			author:          "Bob",
+			expectedLabel:   labels.JiraValidBug,
+			expectedComment: "* bug's contributors include the author of this pull request (@Bob)",
+		},
+		{
+			name:            "author missing from the contributors is warned about",
+			author:          "carol",
+			expectedLabel:   labels.JiraValidBug,
+			expectedComment: "WARNING: None of the contributors of OCPBUGS-123 has a public email matching the author of this pull request (@carol). Please add the author to the contributors of the bug.",
+		},
+		{
+			name:            "author missing from the contributors is invalid when required",
+			author:          "carol",
+			require:         true,
+			expectedLabel:   labels.JiraInvalidBug,
+			expectedComment: " - expected the bug's contributors to include the author of this pull request (@carol), but none of them has a public email matching that GitHub user",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			gc.IssueComments = map[int][]github.IssueComment{}
+			gc.PullRequests = map[int]*github.PullRequest{1: {Number: 1, User: github.User{Login: tc.author}}}
+			client := emailQueryClient{fakeGHClient: fakeGHClient{gc}, logins: map[string]string{"alice@example.com": "alice", "bob@example.com": "bob"}}
+			jiraClient := &fakejira.FakeClient{
+				Issues: []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}, Unknowns: tcontainer.MarshalMap{field: contributors}}}},
+			}
+			e := event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1,
+				bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+				body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+			}
+			options := JiraBranchOptions{IsOpen: &yes, ContributorsField: &field, RequireAuthorInContributors: &tc.require}
+			if err := handle(jiraClient, client, &fakeAgileClient{}, options, logrus.WithField("testCase", t.Name()), e, sets.NewString("org/repo"), nil); err != nil {
+				t.Fatalf("handle failed: %v", err)
+			}
+			if !sets.NewString(gc.IssueLabelsAdded...).Has("org/repo#1:" + tc.expectedLabel) {
+				t.Errorf("expected label %s to be added, got labels: %v", tc.expectedLabel, gc.IssueLabelsAdded)
+			}
+			if len(gc.IssueCommentsAdded) != 1 || !strings.Contains(gc.IssueCommentsAdded[0], tc.expectedComment) {
+				t.Errorf("expected a comment containing %q, got comments: %v", tc.expectedComment, gc.IssueCommentsAdded)
+			}
+		})
+	}
+}
+
+func TestHandleTestRepos(t *testing.T) {
+	yes := true
+	testRepos := []string{"openshift/origin", "openshift/.*-tests"}
+	var testCases = []struct {
+		name            string
+		links           []string
+		require         bool
+		expectedLabel   string
+		expectedComment string
+	}{
+		{
+			name:            "bug linking a pull request in a test repo is valid",
+			links:           []string{"https://github.com/org/repo/pull/1", "https://github.com/openshift/api-tests/pull/12"},
+			expectedLabel:   labels.JiraValidBug,
+			expectedComment: "* bug links the pull request [openshift/api-tests#12](https://github.com/openshift/api-tests/pull/12) in a test repo",
+		},
+		{
+			name:            "bug without a pull request in a test repo is warned about",
+			links:           []string{"https://github.com/org/repo/pull/1", "https://github.com/openshift/origin-tools/pull/3"},
+			expectedLabel:   labels.JiraValidBug,
+			expectedComment: "WARNING: OCPBUGS-123 does not link a pull request in a repo matching one of openshift/origin, openshift/.*-tests. Please link the pull request adding tests for the fix to the bug.",
+		},
+		{
+			name:            "bug without a pull request in a test repo is invalid when required",
+			links:           []string{"https://github.com/org/repo/pull/1"},
+			require:         true,
+			expectedLabel:   labels.JiraInvalidBug,
+			expectedComment: " - expected the bug to link a pull request in a repo matching one of openshift/origin, openshift/.*-tests, but it does not",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueLabelsExisting = []string{}
+			gc.IssueComments = map[int][]github.IssueComment{}
+			var links []jira.RemoteLink
+			for i, link := range tc.links {
+				links = append(links, jira.RemoteLink{ID: i + 1, Object: &jira.RemoteLinkObject{URL: link}})
+			}
+			jiraClient := &fakejira.FakeClient{
+				Issues:        []*jira.Issue{{ID: "1", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Status: &jira.Status{Name: "NEW"}}}},
+				ExistingLinks: map[string][]jira.RemoteLink{"OCPBUGS-123": links},
+			}
+			e := event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1,
+				bugs: []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+				body: "This PR fixes OCPBUGS-123", title: "OCPBUGS-123: fixed it!", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+			}
+			options := JiraBranchOptions{IsOpen: &yes, TestRepos: &testRepos, RequireTestPR: &tc.require}
+			if err := handle(jiraClient, fakeGHClient{gc}, &fakeAgileClient{}, options, logrus.WithField("testCase", t.Name()), e, sets.NewString("org/repo"), nil); err != nil {
+				t.Fatalf("handle failed: %v", err)
+			}
+			if !sets.NewString(gc.IssueLabelsAdded...).Has("org/repo#1:" + tc.expectedLabel) {
+				t.Errorf("expected label %s to be added, got labels: %v", tc.expectedLabel, gc.IssueLabelsAdded)
+			}
+			if len(gc.IssueCommentsAdded) != 1 || !strings.Contains(gc.IssueCommentsAdded[0], tc.expectedComment) {
+				t.Errorf("expected a comment containing %q, got comments: %v", tc.expectedComment, gc.IssueCommentsAdded)
+			}
+		})
+	}
+}
+
+func TestHandleReclone(t *testing.T) {
+	targetVersion := "v2"
+	var testCases = []struct {
+		name             string
+		parent           string
+		clone            bool
+		expectedComments []string
+		expectedUnlinked bool
+	}{
+		{
+			name:   "clone of the wrong bug is replaced by a clone of the given bug",
+			parent: "OCPBUGS-200",
+			clone:  true,
+			expectedComments: []string{
+				"[Jira Issue OCPBUGS-200](https://my-jira.com/browse/OCPBUGS-200) has been cloned as [Jira Issue OCPBUGS-201](https://my-jira.com/browse/OCPBUGS-201) to replace [Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123), which was cloned from [Jira Issue OCPBUGS-100](https://my-jira.com/browse/OCPBUGS-100).",
+				"/retitle OCPBUGS-201: oopsie doopsie",
+			},
+			expectedUnlinked: true,
+		},
+		{
+			name:             "clone of the given bug is left alone",
+			parent:           "OCPBUGS-100",
+			clone:            true,
+			expectedComments: []string{"[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is already a clone of [Jira Issue OCPBUGS-100](https://my-jira.com/browse/OCPBUGS-100), so it has not been recloned."},
+		},
+		{
+			name:             "bug that is not a clone is not recloned",
+			parent:           "OCPBUGS-200",
+			expectedComments: []string{"[Jira Issue OCPBUGS-123](https://my-jira.com/browse/OCPBUGS-123) is not a clone of another bug, so it cannot be recloned. Use <code>/jira cherrypick</code> to clone a bug for this pull request instead."},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gc := fakegithub.NewFakeClient()
+			gc.IssueComments = map[int][]github.IssueComment{}
+			jiraClient := &fakejira.FakeClient{
+				Issues: []*jira.Issue{
+					{ID: "1", Key: "OCPBUGS-100", Fields: &jira.IssueFields{Project: jira.Project{Name: "OCPBUGS"}, Status: &jira.Status{Name: "NEW"}}},
+					{ID: "2", Key: "OCPBUGS-200", Fields: &jira.IssueFields{Project: jira.Project{Name: "OCPBUGS"}, Status: &jira.Status{Name: "NEW"}}},
+					{ID: "3", Key: "OCPBUGS-123", Fields: &jira.IssueFields{Project: jira.Project{Name: "OCPBUGS"}, Status: &jira.Status{Name: "NEW"}}},
+				},
+			}
+			if tc.clone {
+				for _, link := range []*jira.IssueLink{
+					{ID: "10", OutwardIssue: &jira.Issue{ID: "1"}, InwardIssue: &jira.Issue{ID: "3"}, Type: jira.IssueLinkType{Name: "Cloners", Inward: "is cloned by", Outward: "clones"}},
+					{ID: "11", OutwardIssue: &jira.Issue{ID: "3"}, InwardIssue: &jira.Issue{ID: "1"}, Type: jira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}},
+				} {
+					if err := jiraClient.CreateIssueLink(link); err != nil {
+						t.Fatalf("failed to create issue link: %v", err)
+					}
+				}
+			}
+			e := event{
+				org: "org", repo: "repo", baseRef: "branch", number: 1,
+				bugs:  []referencedBug{{Key: "OCPBUGS-123", IsBug: true}},
+				title: "OCPBUGS-123: oopsie doopsie", htmlUrl: "https://github.com/org/repo/pull/1", login: "user",
+				recloneParent: tc.parent,
+			}
+			options := JiraBranchOptions{TargetVersion: &targetVersion}
+			if err := handle(jiraClient, fakeGHClient{gc}, &fakeAgileClient{}, options, logrus.WithField("testCase", t.Name()), e, sets.NewString("org/repo"), nil); err != nil {
+				t.Fatalf("handle failed: %v", err)
+			}
+			if len(gc.IssueCommentsAdded) != 1 {
+				t.Fatalf("expected one comment, got comments: %v", gc.IssueCommentsAdded)
+			}
+			for _, expected := range tc.expectedComments {
+				if !strings.Contains(gc.IssueCommentsAdded[0], expected) {
+					t.Errorf("expected a comment containing %q, got: %s", expected, gc.IssueCommentsAdded[0])
+				}
+			}
+			oldClone, err := jiraClient.GetIssue("OCPBUGS-123")
+			if err != nil {
+				t.Fatalf("failed to get the old clone: %v", err)
+			}
+			if tc.clone && tc.expectedUnlinked == (len(oldClone.Fields.IssueLinks) != 0) {
+				t.Errorf("expected the old clone to be unlinked from its parent: %t, got links: %v", tc.expectedUnlinked, oldClone.Fields.IssueLinks)
+			}
+			if tc.expectedUnlinked {
+				if oldClone.Fields.Comments == nil || len(oldClone.Fields.Comments.Comments) != 1 || !strings.Contains(oldClone.Fields.Comments.Comments[0].Body, "has been replaced by OCPBUGS-201, a clone of OCPBUGS-200") {
+					t.Errorf("expected the old clone to point to its replacement, got comments: %v", oldClone.Fields.Comments)
+				}
+				newClone, err := jiraClient.GetIssue("OCPBUGS-201")
+				if err != nil {
+					t.Fatalf("failed to get the new clone: %v", err)
+				}
+				if parent := clonedFrom(newClone); parent == nil || parent.ID != "2" {
+					t.Errorf("expected the new clone to be cloned from OCPBUGS-200, got: %v", parent)
+				}
+			}
+		})
+	}
+}
+
+func TestSlackNotifierRateLimit(t *testing.T) {
+	posted := make(chan struct{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		posted <- struct{}{}
+	}))
+	defer server.Close()
+
+	notifier := newSlackNotifier(rate.Every(time.Hour), 1)
+	log := logrus.WithField("testCase", t.Name())
+	// neither the failing webhook nor the exceeded rate limit may block the caller
+	notifier.notify(log, server.URL, "first")
+	notifier.notify(log, server.URL, "second")
+
+	select {
+	case <-posted:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the Slack notification")
+	}
+	select {
+	case <-posted:
+		t.Error("expected the second notification to be dropped by the rate limit")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// invalidBugNotifier posts notifications about invalid bugs to the Slack webhooks configured
+// with SlackWebhookURL.
+var invalidBugNotifier = newSlackNotifier(rate.Every(time.Second), 10)
+
+// slackNotifier posts messages to Slack incoming webhooks. Messages are posted in the background
+// and are dropped once the rate limit is exceeded, so a slow or failing Slack never blocks or
+// fails the handling of an event.
+type slackNotifier struct {
+	client  *http.Client
+	limiter *rate.Limiter
+}
+
+func newSlackNotifier(limit rate.Limit, burst int) *slackNotifier {
+	return &slackNotifier{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		limiter: rate.NewLimiter(limit, burst),
+	}
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// notify posts the message to the webhook in the background; errors are only logged
+func (n *slackNotifier) notify(log *logrus.Entry, webhookURL, text string) {
+	if !n.limiter.Allow() {
+		log.Warn("Slack notification rate limit exceeded, dropping notification.")
+		return
+	}
+	go func() {
+		if err := n.post(webhookURL, text); err != nil {
+			log.WithError(err).Warn("Failed to post Slack notification.")
+		}
+	}()
+}
+
+func (n *slackNotifier) post(webhookURL, text string) error {
+	body, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack message: %w", err)
+	}
+	resp, err := n.client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post Slack message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code posting Slack message: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// invalidBugSlackMessage summarizes the pull request, the invalid bug and why it is invalid
+func invalidBugSlackMessage(e event, bugKey, jiraURL string, why []string) string {
+	message := fmt.Sprintf("<%s|%s/%s#%d> references <%s/browse/%s|%s>, which is invalid:", e.htmlUrl, e.org, e.repo, e.number, jiraURL, bugKey, bugKey)
+	var reasons []string
+	for _, reason := range why {
+		reasons = append(reasons, fmt.Sprintf("\n- %s", reason))
+	}
+	return message + strings.Join(reasons, "")
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
 
 	"github.com/openshift-eng/jira-lifecycle-plugin/pkg/status"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -16,9 +19,125 @@ func validateConfig(rawConfig []byte) error {
 	}
 	errors := []error{}
 	errors = append(errors, validateStatuses(&config)...)
+	errors = append(errors, validateRetitleCommands(&config)...)
+	errors = append(errors, validateBranchOptions(&config)...)
 	return utilerrors.NewAggregate(errors)
 }
 
+// Validate checks the options of all branches for mistakes that would otherwise only surface
+// when an event for an affected branch is handled, e.g. templates that cannot be parsed or
+// options that contradict each other. All problems found are returned together.
+func (c *Config) Validate() error {
+	errors := validateRetitleCommands(c)
+	errors = append(errors, validateBranchOptions(c)...)
+	return utilerrors.NewAggregate(errors)
+}
+
+// forEachBranchOptions calls fn for the options of each branch in the config, along with the
+// location of the options, e.g. `default`, `org/default` or `org/repo`
+func forEachBranchOptions(c *Config, fn func(location, branchName string, options JiraBranchOptions)) {
+	for branchName, options := range c.Default {
+		fn("default", branchName, options)
+	}
+	for orgName, orgOptions := range c.Orgs {
+		for orgBranchName, orgBranchOptions := range orgOptions.Default {
+			fn(orgName+"/default", orgBranchName, orgBranchOptions)
+		}
+		for repoName, repoOptions := range orgOptions.Repos {
+			for branchName, branchOptions := range repoOptions.Branches {
+				fn(orgName+"/"+repoName, branchName, branchOptions)
+			}
+		}
+	}
+}
+
+// validateRetitleCommands ensures that no branch configures an empty retitle command,
+// which would cause the plugin to comment a bare title instead of retitling the PR
+func validateRetitleCommands(c *Config) []error {
+	errors := []error{}
+	forEachBranchOptions(c, func(location, branchName string, options JiraBranchOptions) {
+		if options.RetitleCommand != nil && strings.TrimSpace(*options.RetitleCommand) == "" {
+			errors = append(errors, fmt.Errorf("%s has an empty `retitle_command` in `%s`", branchName, location))
+		}
+	})
+	return errors
+}
+
+// validateBranchOptions ensures that the templates configured for each branch can be parsed
+// and that no branch sets options that contradict each other
+func validateBranchOptions(c *Config) []error {
+	errors := []error{}
+	forEachBranchOptions(c, func(location, branchName string, options JiraBranchOptions) {
+		errors = append(errors, checkBranchOptions(location, branchName, options)...)
+	})
+	return errors
+}
+
+func checkBranchOptions(location, name string, options JiraBranchOptions) []error {
+	errors := []error{}
+	templates := []struct {
+		field string
+		text  *string
+	}{
+		{field: "validation_transition_comment", text: options.ValidationTransitionComment},
+		{field: "field_edit_url_template", text: options.FieldEditURLTemplate},
+	}
+	for _, tmpl := range templates {
+		if tmpl.text == nil {
+			continue
+		}
+		if _, err := template.New(tmpl.field).Parse(*tmpl.text); err != nil {
+			errors = append(errors, fmt.Errorf("%s has an invalid `%s` in `%s`: %w", name, tmpl.field, location, err))
+		}
+	}
+	// the fields checked by these options may be inherited, but clearing the field in the
+	// same options that enable the check means the check can never pass
+	requiredFields := []struct {
+		option, field string
+		enabled       *bool
+		value         *string
+	}{
+		{option: "require_verification_field", field: "verification_field", enabled: options.RequireVerificationField, value: options.VerificationField},
+		{option: "require_release_note_type", field: "release_note_type_field", enabled: options.RequireReleaseNoteType, value: options.ReleaseNoteTypeField},
+		{option: "require_story_points", field: "story_points_field", enabled: options.RequireStoryPoints, value: options.StoryPointsField},
+		{option: "reject_flagged_blocked", field: "flagged_field", enabled: options.RejectFlaggedBlocked, value: options.FlaggedField},
+		{option: "require_author_in_contributors", field: "contributors_field", enabled: options.RequireAuthorInContributors, value: options.ContributorsField},
+		{option: "require_backport_ready", field: "backport_ready_field", enabled: options.RequireBackportReady, value: options.BackportReadyField},
+	}
+	for _, required := range requiredFields {
+		if required.enabled != nil && *required.enabled && required.value != nil && strings.TrimSpace(*required.value) == "" {
+			errors = append(errors, fmt.Errorf("%s sets `%s` with an empty `%s` in `%s`", name, required.option, required.field, location))
+		}
+	}
+	if options.RequireKnownTargetVersion != nil && *options.RequireKnownTargetVersion && options.KnownTargetVersions != nil && len(*options.KnownTargetVersions) == 0 {
+		errors = append(errors, fmt.Errorf("%s sets `require_known_target_version` with empty `known_target_versions` in `%s`", name, location))
+	}
+	if options.MaxListedValidations != nil && *options.MaxListedValidations < 1 {
+		errors = append(errors, fmt.Errorf("%s has a `max_listed_validations` of %d in `%s`, but at least one validation must be listed", name, *options.MaxListedValidations, location))
+	}
+	for issueType, patterns := range options.IssueTypeBranches {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				errors = append(errors, fmt.Errorf("%s has an invalid branch pattern for the %s issue type in `issue_type_branches` in `%s`: %w", name, issueType, location, err))
+			}
+		}
+	}
+	if options.TestRepos != nil {
+		for _, pattern := range *options.TestRepos {
+			if _, err := regexp.Compile(pattern); err != nil {
+				errors = append(errors, fmt.Errorf("%s has an invalid repo pattern in `test_repos` in `%s`: %w", name, location, err))
+			}
+		}
+	}
+	if options.RequireTestPR != nil && *options.RequireTestPR && (options.TestRepos == nil || len(*options.TestRepos) == 0) {
+		errors = append(errors, fmt.Errorf("%s sets `require_test_pr` without any `test_repos` in `%s`", name, location))
+	}
+	if options.ValidationJQL != nil && strings.TrimSpace(*options.ValidationJQL) == "" && options.ValidationJQLMessage != nil {
+		errors = append(errors, fmt.Errorf("%s sets a `validation_jql_message` for an empty `validation_jql` in `%s`", name, location))
+	}
+	return errors
+}
+
 func validateStatuses(c *Config) []error {
 	errors := []error{}
 	for branchName, options := range c.Default {
@@ -54,9 +173,15 @@ func checkBranchStatuses(name string, options JiraBranchOptions) []error {
 	if options.StateAfterClose != nil && !validStatusSet.Has(options.StateAfterClose.Status) {
 		errors = append(errors, fmt.Errorf("%s has invalid status for `state_after_close`: `%s`", name, options.StateAfterClose.Status))
 	}
+	if options.StateAfterCloseUnmerged != nil && !validStatusSet.Has(options.StateAfterCloseUnmerged.Status) {
+		errors = append(errors, fmt.Errorf("%s has invalid status for `state_after_close_unmerged`: `%s`", name, options.StateAfterCloseUnmerged.Status))
+	}
 	if options.StateAfterMerge != nil && !validStatusSet.Has(options.StateAfterMerge.Status) {
 		errors = append(errors, fmt.Errorf("%s has invalid status for `state_after_merge`: `%s`", name, options.StateAfterMerge.Status))
 	}
+	if options.StateAfterRevert != nil && !validStatusSet.Has(options.StateAfterRevert.Status) {
+		errors = append(errors, fmt.Errorf("%s has invalid status for `state_after_revert`: `%s`", name, options.StateAfterRevert.Status))
+	}
 	if options.StateAfterValidation != nil && !validStatusSet.Has(options.StateAfterValidation.Status) {
 		errors = append(errors, fmt.Errorf("%s has invalid status for `state_after_validation`: `%s`", name, options.StateAfterValidation.Status))
 	}
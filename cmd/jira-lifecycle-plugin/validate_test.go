@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/openshift-eng/jira-lifecycle-plugin/pkg/status"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	jc "k8s.io/test-infra/prow/jira"
 )
 
@@ -462,3 +463,192 @@ func TestCheckBranchStatuses(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRetitleCommands(t *testing.T) {
+	t.Parallel()
+	custom := "/bot retitle"
+	empty := " "
+	testCases := []struct {
+		name        string
+		config      Config
+		expectedErr []string
+	}{{
+		name: "Unset retitle command is valid",
+		config: Config{
+			Default: map[string]JiraBranchOptions{"my-branch": {}},
+		},
+	}, {
+		name: "Custom retitle command is valid",
+		config: Config{
+			Orgs: map[string]JiraOrgOptions{
+				"org1": {
+					Repos: map[string]JiraRepoOptions{
+						"my-repo": {Branches: map[string]JiraBranchOptions{"my-branch": {RetitleCommand: &custom}}},
+					},
+				},
+			},
+		},
+	}, {
+		name: "Empty retitle commands are reported",
+		config: Config{
+			Default: map[string]JiraBranchOptions{"my-branch": {RetitleCommand: &empty}},
+			Orgs: map[string]JiraOrgOptions{
+				"org1": {
+					Default: map[string]JiraBranchOptions{"my-branch": {RetitleCommand: &empty}},
+					Repos: map[string]JiraRepoOptions{
+						"my-repo": {Branches: map[string]JiraBranchOptions{"my-branch": {RetitleCommand: &empty}}},
+					},
+				},
+			},
+		},
+		expectedErr: []string{
+			"my-branch has an empty `retitle_command` in `default`",
+			"my-branch has an empty `retitle_command` in `org1/default`",
+			"my-branch has an empty `retitle_command` in `org1/my-repo`",
+		},
+	}}
+	for _, tc := range testCases {
+		errs := validateRetitleCommands(&tc.config)
+		if len(errs) != len(tc.expectedErr) {
+			t.Errorf("%s: Got different number of errors (%d) than expected (%d): %+v", tc.name, len(errs), len(tc.expectedErr), errs)
+			continue
+		}
+		stringErrs := []string{}
+		for _, err := range errs {
+			stringErrs = append(stringErrs, err.Error())
+		}
+		sort.Strings(stringErrs)
+		for index, err := range stringErrs {
+			if err != tc.expectedErr[index] {
+				t.Errorf("%s: Got different error at index %d than expected: %v", tc.name, index, err)
+			}
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+	yes := true
+	empty := ""
+	field := "customfield_12345"
+	comment := "{{.Key}} moved to {{.Status}}"
+	unclosed := "{{.Key"
+	jql := "labels = triaged"
+	message := "expected the bug to be triaged"
+	zero := 0
+	testCases := []struct {
+		name        string
+		config      Config
+		expectedErr []string
+	}{{
+		name: "Valid options are valid",
+		config: Config{
+			Default: map[string]JiraBranchOptions{"*": {
+				ValidationTransitionComment: &comment,
+				RequireStoryPoints:          &yes,
+				StoryPointsField:            &field,
+				ValidationJQL:               &jql,
+				ValidationJQLMessage:        &message,
+			}},
+		},
+	}, {
+		name: "Required fields may be inherited",
+		config: Config{
+			Default: map[string]JiraBranchOptions{"*": {StoryPointsField: &field}},
+			Orgs: map[string]JiraOrgOptions{
+				"org1": {Default: map[string]JiraBranchOptions{"my-branch": {RequireStoryPoints: &yes}}},
+			},
+		},
+	}, {
+		name: "Invalid templates are reported",
+		config: Config{
+			Default: map[string]JiraBranchOptions{"*": {ValidationTransitionComment: &unclosed}},
+			Orgs: map[string]JiraOrgOptions{
+				"org1": {
+					Repos: map[string]JiraRepoOptions{
+						"my-repo": {Branches: map[string]JiraBranchOptions{"my-branch": {FieldEditURLTemplate: &unclosed}}},
+					},
+				},
+			},
+		},
+		expectedErr: []string{
+			"* has an invalid `validation_transition_comment` in `default`: template: validation_transition_comment:1: unclosed action",
+			"my-branch has an invalid `field_edit_url_template` in `org1/my-repo`: template: field_edit_url_template:1: unclosed action",
+		},
+	}, {
+		name: "Invalid branch patterns are reported",
+		config: Config{
+			Default: map[string]JiraBranchOptions{"*": {IssueTypeBranches: map[string][]string{"Epic": {"main|master", "(main"}}}},
+		},
+		expectedErr: []string{
+			"* has an invalid branch pattern for the Epic issue type in `issue_type_branches` in `default`: error parsing regexp: missing closing ): `(main`",
+		},
+	}, {
+		name: "Invalid test repo patterns are reported",
+		config: Config{
+			Default: map[string]JiraBranchOptions{
+				"*":         {TestRepos: &[]string{"openshift/origin", "openshift/(tests"}},
+				"my-branch": {TestRepos: &[]string{}, RequireTestPR: &yes},
+			},
+		},
+		expectedErr: []string{
+			"* has an invalid repo pattern in `test_repos` in `default`: error parsing regexp: missing closing ): `openshift/(tests`",
+			"my-branch sets `require_test_pr` without any `test_repos` in `default`",
+		},
+	}, {
+		name: "Non-positive maximum listed validations are reported",
+		config: Config{
+			Default: map[string]JiraBranchOptions{"*": {MaxListedValidations: &zero}},
+		},
+		expectedErr: []string{
+			"* has a `max_listed_validations` of 0 in `default`, but at least one validation must be listed",
+		},
+	}, {
+		name: "Required fields that are cleared are reported",
+		config: Config{
+			Orgs: map[string]JiraOrgOptions{
+				"org1": {
+					Default: map[string]JiraBranchOptions{"my-branch": {RequireVerificationField: &yes, VerificationField: &empty}},
+					Repos: map[string]JiraRepoOptions{
+						"my-repo": {Branches: map[string]JiraBranchOptions{"my-branch": {RequireAuthorInContributors: &yes, ContributorsField: &empty}}},
+					},
+				},
+			},
+		},
+		expectedErr: []string{
+			"my-branch sets `require_author_in_contributors` with an empty `contributors_field` in `org1/my-repo`",
+			"my-branch sets `require_verification_field` with an empty `verification_field` in `org1/default`",
+		},
+	}, {
+		name: "Contradicting options are reported",
+		config: Config{
+			Default: map[string]JiraBranchOptions{
+				"my-branch":    {RequireKnownTargetVersion: &yes, KnownTargetVersions: &[]string{}},
+				"other-branch": {ValidationJQL: &empty, ValidationJQLMessage: &message, RetitleCommand: &empty},
+			},
+		},
+		expectedErr: []string{
+			"my-branch sets `require_known_target_version` with empty `known_target_versions` in `default`",
+			"other-branch has an empty `retitle_command` in `default`",
+			"other-branch sets a `validation_jql_message` for an empty `validation_jql` in `default`",
+		},
+	}}
+	for _, tc := range testCases {
+		var errs []error
+		if err := tc.config.Validate(); err != nil {
+			errs = err.(utilerrors.Aggregate).Errors()
+		}
+		if len(errs) != len(tc.expectedErr) {
+			t.Errorf("%s: Got different number of errors (%d) than expected (%d): %+v", tc.name, len(errs), len(tc.expectedErr), errs)
+			continue
+		}
+		var stringErrs []string
+		for _, err := range errs {
+			stringErrs = append(stringErrs, err.Error())
+		}
+		sort.Strings(stringErrs)
+		if diff := cmp.Diff(tc.expectedErr, stringErrs); diff != "" {
+			t.Errorf("%s: Got different errors than expected: %s", tc.name, diff)
+		}
+	}
+}
@@ -3,6 +3,9 @@ package helpers
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/andygrunwald/go-jira"
 )
@@ -15,6 +18,33 @@ const (
 	ReleaseBlockerField   = "customfield_12319743"
 )
 
+// FieldMap holds the IDs of the custom fields read by the helpers, which differ between Jira
+// instances. Fields that are not set default to the IDs used by Red Hat Jira.
+type FieldMap struct {
+	TargetVersion    string `json:"target_version,omitempty"`
+	TargetVersionOld string `json:"target_version_old,omitempty"`
+	Severity         string `json:"severity,omitempty"`
+	QAContact        string `json:"qa_contact,omitempty"`
+}
+
+// WithDefaults returns a copy of the field map in which the fields that are not set hold the
+// default IDs, e.g. TargetVersionField.
+func (m FieldMap) WithDefaults() FieldMap {
+	if m.TargetVersion == "" {
+		m.TargetVersion = TargetVersionField
+	}
+	if m.TargetVersionOld == "" {
+		m.TargetVersionOld = TargetVersionFieldOld
+	}
+	if m.Severity == "" {
+		m.Severity = SeverityField
+	}
+	if m.QAContact == "" {
+		m.QAContact = QAContactField
+	}
+	return m
+}
+
 // GetUnknownField will attempt to get the specified field from the Unknowns struct and unmarshal
 // the value into the provided function. If the field is not set, the first return value of this
 // function will return false.
@@ -63,9 +93,9 @@ type SecurityLevel struct {
 	Description string `json:"description"`
 }
 
-func GetIssueQaContact(issue *jira.Issue) (*jira.User, error) {
+func GetIssueQaContact(issue *jira.Issue, fields FieldMap) (*jira.User, error) {
 	var obj *jira.User
-	isSet, err := GetUnknownField(QAContactField, issue, func() interface{} {
+	isSet, err := GetUnknownField(fields.WithDefaults().QAContact, issue, func() interface{} {
 		obj = &jira.User{}
 		return obj
 	})
@@ -75,16 +105,17 @@ func GetIssueQaContact(issue *jira.Issue) (*jira.User, error) {
 	return obj, err
 }
 
-func GetIssueTargetVersion(issue *jira.Issue) ([]*jira.Version, error) {
+func GetIssueTargetVersion(issue *jira.Issue, fields FieldMap) ([]*jira.Version, error) {
+	fields = fields.WithDefaults()
 	var obj *[]*jira.Version
-	isSet, err := GetUnknownField(TargetVersionField, issue, func() interface{} {
+	isSet, err := GetUnknownField(fields.TargetVersion, issue, func() interface{} {
 		obj = &[]*jira.Version{{}}
 		return obj
 	})
 	if isSet && obj != nil && *obj != nil {
 		return *obj, err
 	}
-	isSet, err = GetUnknownField(TargetVersionFieldOld, issue, func() interface{} {
+	isSet, err = GetUnknownField(fields.TargetVersionOld, issue, func() interface{} {
 		obj = &[]*jira.Version{{}}
 		return obj
 	})
@@ -94,16 +125,177 @@ func GetIssueTargetVersion(issue *jira.Issue) ([]*jira.Version, error) {
 	return *obj, err
 }
 
-func GetIssueSeverity(issue *jira.Issue) (*CustomField, error) {
-	var obj *CustomField
-	isSet, err := GetUnknownField(SeverityField, issue, func() interface{} {
-		obj = &CustomField{}
+func GetIssueSeverity(issue *jira.Issue, fields FieldMap) (*CustomField, error) {
+	field := fields.WithDefaults().Severity
+	var obj *json.RawMessage
+	isSet, err := GetUnknownField(field, issue, func() interface{} {
+		obj = &json.RawMessage{}
 		return obj
 	})
-	if !isSet {
+	if !isSet || err != nil {
 		return nil, err
 	}
-	return obj, err
+	// some Jira instances store the severity as plain text instead of a select value
+	var text string
+	if err := json.Unmarshal(*obj, &text); err == nil {
+		return &CustomField{Value: text}, nil
+	}
+	var severity CustomField
+	if err := json.Unmarshal(*obj, &severity); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the json to struct for %s. Error: %v", field, err)
+	}
+	return &severity, nil
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// SimplifiedSeverity returns the name of the severity from the value of the severity field.
+// The values of the severity field in Red Hat Jira have an image before them
+// (ex: <img alt=\"\" src=\"/images/icons/priorities/medium.svg\" width=\"16\" height=\"16\"> Medium),
+// while other instances store the plain name (ex: Medium), so any HTML is stripped and the
+// trailing word is returned.
+func SimplifiedSeverity(value string) string {
+	words := strings.Fields(htmlTag.ReplaceAllString(value, " "))
+	if len(words) == 0 {
+		return ""
+	}
+	return words[len(words)-1]
+}
+
+// GetIssueVerification returns the value of the verification custom field with the given ID.
+// The field may either be a text field or a select field. If the field is not set, the returned
+// value will be empty.
+func GetIssueVerification(issue *jira.Issue, field string) (string, error) {
+	return getTextOrSelectField(issue, field)
+}
+
+// GetIssueReleaseNoteType returns the value of the release note type custom field with the
+// given ID, e.g. `Bug Fix` or `Release Note Not Required`. The field may either be a text field
+// or a select field. If the field is not set, the returned value will be empty.
+func GetIssueReleaseNoteType(issue *jira.Issue, field string) (string, error) {
+	return getTextOrSelectField(issue, field)
+}
+
+// getTextOrSelectField returns the trimmed value of a custom field that may either be a text
+// field or a select field. If the field is not set, the returned value will be empty.
+func getTextOrSelectField(issue *jira.Issue, field string) (string, error) {
+	var obj *json.RawMessage
+	isSet, err := GetUnknownField(field, issue, func() interface{} {
+		obj = &json.RawMessage{}
+		return obj
+	})
+	if !isSet || err != nil {
+		return "", err
+	}
+	var text string
+	if err := json.Unmarshal(*obj, &text); err == nil {
+		return strings.TrimSpace(text), nil
+	}
+	var option CustomField
+	if err := json.Unmarshal(*obj, &option); err != nil {
+		return "", fmt.Errorf("failed to unmarshal the json to a text or select value for %s. Error: %v", field, err)
+	}
+	return strings.TrimSpace(option.Value), nil
+}
+
+// GetIssueStoryPoints returns the value of the story points custom field with the given ID.
+// The field is a number field, but numbers stored as text are accepted as well. If the field
+// is not set, the returned value will be nil.
+func GetIssueStoryPoints(issue *jira.Issue, field string) (*float64, error) {
+	var obj *json.RawMessage
+	isSet, err := GetUnknownField(field, issue, func() interface{} {
+		obj = &json.RawMessage{}
+		return obj
+	})
+	if !isSet || err != nil || string(*obj) == "null" {
+		return nil, err
+	}
+	var points float64
+	if err := json.Unmarshal(*obj, &points); err == nil {
+		return &points, nil
+	}
+	var text string
+	if err := json.Unmarshal(*obj, &text); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the json to a number for %s. Error: %v", field, err)
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	points, err = strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the value of %s as a number. Error: %v", field, err)
+	}
+	return &points, nil
+}
+
+// GetIssueContributors returns the users listed in the multi-user picker custom field with the
+// given ID, e.g. the contributors of the issue. If the field is not set, no users are returned.
+func GetIssueContributors(issue *jira.Issue, field string) ([]*jira.User, error) {
+	var obj *[]*jira.User
+	isSet, err := GetUnknownField(field, issue, func() interface{} {
+		obj = &[]*jira.User{}
+		return obj
+	})
+	if !isSet || err != nil {
+		return nil, err
+	}
+	return *obj, nil
+}
+
+// GetIssueFlagged returns whether the Jira flag, e.g. `Impediment`, is set on the issue. The flag
+// is stored in the checkbox custom field with the given ID, which holds the list of checked options
+// and is empty or null when the issue is not flagged.
+func GetIssueFlagged(issue *jira.Issue, field string) (bool, error) {
+	var obj *json.RawMessage
+	isSet, err := GetUnknownField(field, issue, func() interface{} {
+		obj = &json.RawMessage{}
+		return obj
+	})
+	if !isSet || err != nil || string(*obj) == "null" {
+		return false, err
+	}
+	var options []CustomField
+	if err := json.Unmarshal(*obj, &options); err == nil {
+		return len(options) > 0, nil
+	}
+	var option CustomField
+	if err := json.Unmarshal(*obj, &option); err != nil {
+		return false, fmt.Errorf("failed to unmarshal the json to a list of checked options for %s. Error: %v", field, err)
+	}
+	return option.Value != "", nil
+}
+
+// GetIssueBackportReady returns whether the issue is marked as ready for backport in the custom
+// field with the given ID. The field may either be a checkbox field, which marks the issue when
+// any of its options is checked, a boolean field, or a select or text field, which marks the issue
+// when its value is `Yes` or `True`. If the field is not set, the issue is not ready for backport.
+func GetIssueBackportReady(issue *jira.Issue, field string) (bool, error) {
+	var obj *json.RawMessage
+	isSet, err := GetUnknownField(field, issue, func() interface{} {
+		obj = &json.RawMessage{}
+		return obj
+	})
+	if !isSet || err != nil || string(*obj) == "null" {
+		return false, err
+	}
+	var checked bool
+	if err := json.Unmarshal(*obj, &checked); err == nil {
+		return checked, nil
+	}
+	var options []CustomField
+	if err := json.Unmarshal(*obj, &options); err == nil {
+		return len(options) > 0, nil
+	}
+	var text string
+	if err := json.Unmarshal(*obj, &text); err != nil {
+		var option CustomField
+		if err := json.Unmarshal(*obj, &option); err != nil {
+			return false, fmt.Errorf("failed to unmarshal the json to a checkbox, boolean, select or text value for %s. Error: %v", field, err)
+		}
+		text = option.Value
+	}
+	text = strings.TrimSpace(text)
+	return strings.EqualFold(text, "yes") || strings.EqualFold(text, "true"), nil
 }
 
 type CustomField struct {
@@ -0,0 +1,471 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/google/go-cmp/cmp"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+func TestGetIssueVerification(t *testing.T) {
+	const field = "customfield_1"
+	var testCases = []struct {
+		name        string
+		issue       *jira.Issue
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:  "issue without fields has no verification",
+			issue: &jira.Issue{},
+		},
+		{
+			name:  "unset field has no verification",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_2": "tested"}}},
+		},
+		{
+			name:  "null field has no verification",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: nil}}},
+		},
+		{
+			name:     "text field is returned trimmed",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: " https://polarion.example.com/testcase/1\n"}}},
+			expected: "https://polarion.example.com/testcase/1",
+		},
+		{
+			name:     "select field returns its value",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: map[string]interface{}{"id": "1", "value": "Verified by QE"}}}},
+			expected: "Verified by QE",
+		},
+		{
+			name:        "unexpected field type is an error",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: []string{"a"}}}},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verification, err := GetIssueVerification(tc.issue, field)
+			if err == nil && tc.expectedErr {
+				t.Fatal("expected an error but got none")
+			}
+			if err != nil && !tc.expectedErr {
+				t.Fatalf("expected no error but got one: %v", err)
+			}
+			if verification != tc.expected {
+				t.Errorf("expected verification %q, got %q", tc.expected, verification)
+			}
+		})
+	}
+}
+
+func TestGetIssueReleaseNoteType(t *testing.T) {
+	const field = "customfield_1"
+	var testCases = []struct {
+		name        string
+		issue       *jira.Issue
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:  "issue without fields has no release note type",
+			issue: &jira.Issue{},
+		},
+		{
+			name:  "null field has no release note type",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: nil}}},
+		},
+		{
+			name:     "select field returns its value",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: map[string]interface{}{"id": "1", "value": "Bug Fix"}}}},
+			expected: "Bug Fix",
+		},
+		{
+			name:     "text field is returned trimmed",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: "Release Note Not Required "}}},
+			expected: "Release Note Not Required",
+		},
+		{
+			name:        "unexpected field type is an error",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: 1}}},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			releaseNoteType, err := GetIssueReleaseNoteType(tc.issue, field)
+			if err == nil && tc.expectedErr {
+				t.Fatal("expected an error but got none")
+			}
+			if err != nil && !tc.expectedErr {
+				t.Fatalf("expected no error but got one: %v", err)
+			}
+			if releaseNoteType != tc.expected {
+				t.Errorf("expected release note type %q, got %q", tc.expected, releaseNoteType)
+			}
+		})
+	}
+}
+
+func TestGetIssueStoryPoints(t *testing.T) {
+	const field = "customfield_1"
+	var testCases = []struct {
+		name        string
+		issue       *jira.Issue
+		expected    *float64
+		expectedErr bool
+	}{
+		{
+			name:  "issue without fields has no story points",
+			issue: &jira.Issue{},
+		},
+		{
+			name:  "unset field has no story points",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_2": 3}}},
+		},
+		{
+			name:  "null field has no story points",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: nil}}},
+		},
+		{
+			name:     "number field is returned",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: 3.0}}},
+			expected: func() *float64 { points := 3.0; return &points }(),
+		},
+		{
+			name:     "zero is a valid estimate",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: 0}}},
+			expected: func() *float64 { points := 0.0; return &points }(),
+		},
+		{
+			name:     "number stored as text is parsed",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: " 0.5 "}}},
+			expected: func() *float64 { points := 0.5; return &points }(),
+		},
+		{
+			name:  "empty text field has no story points",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: ""}}},
+		},
+		{
+			name:        "text that is not a number is an error",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: "large"}}},
+			expectedErr: true,
+		},
+		{
+			name:        "unexpected field type is an error",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: []string{"a"}}}},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			points, err := GetIssueStoryPoints(tc.issue, field)
+			if err == nil && tc.expectedErr {
+				t.Fatal("expected an error but got none")
+			}
+			if err != nil && !tc.expectedErr {
+				t.Fatalf("expected no error but got one: %v", err)
+			}
+			switch {
+			case points == nil && tc.expected == nil:
+			case points == nil || tc.expected == nil:
+				t.Errorf("expected story points %v, got %v", tc.expected, points)
+			case *points != *tc.expected:
+				t.Errorf("expected story points %v, got %v", *tc.expected, *points)
+			}
+		})
+	}
+}
+
+func TestGetIssueContributors(t *testing.T) {
+	const field = "customfield_1"
+	var testCases = []struct {
+		name        string
+		issue       *jira.Issue
+		expected    []*jira.User
+		expectedErr bool
+	}{
+		{
+			name:  "issue without fields has no contributors",
+			issue: &jira.Issue{},
+		},
+		{
+			name:  "null field has no contributors",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: nil}}},
+		},
+		{
+			name: "users are returned",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: []interface{}{
+				map[string]interface{}{"name": "alice", "emailAddress": "alice@example.com"},
+				map[string]interface{}{"name": "bob", "emailAddress": "bob@example.com"},
+			}}}},
+			expected: []*jira.User{{Name: "alice", EmailAddress: "alice@example.com"}, {Name: "bob", EmailAddress: "bob@example.com"}},
+		},
+		{
+			name:        "unexpected field type is an error",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: "alice"}}},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contributors, err := GetIssueContributors(tc.issue, field)
+			if err == nil && tc.expectedErr {
+				t.Fatal("expected an error but got none")
+			}
+			if err != nil && !tc.expectedErr {
+				t.Fatalf("expected no error but got one: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, contributors); diff != "" {
+				t.Errorf("got unexpected contributors: %s", diff)
+			}
+		})
+	}
+}
+
+func TestGetIssueFlagged(t *testing.T) {
+	const field = "customfield_1"
+	var testCases = []struct {
+		name        string
+		issue       *jira.Issue
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			name:  "issue without fields is not flagged",
+			issue: &jira.Issue{},
+		},
+		{
+			name:  "unset field is not flagged",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_2": []interface{}{map[string]interface{}{"value": "Impediment"}}}}},
+		},
+		{
+			name:  "null field is not flagged",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: nil}}},
+		},
+		{
+			name:  "empty list of options is not flagged",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: []interface{}{}}}},
+		},
+		{
+			name:     "checked option is flagged",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: []interface{}{map[string]interface{}{"id": "1", "value": "Impediment"}}}}},
+			expected: true,
+		},
+		{
+			name:     "single option is flagged",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: map[string]interface{}{"id": "1", "value": "Impediment"}}}},
+			expected: true,
+		},
+		{
+			name:        "unexpected field type is an error",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: "Impediment"}}},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flagged, err := GetIssueFlagged(tc.issue, field)
+			if err == nil && tc.expectedErr {
+				t.Fatal("expected an error but got none")
+			}
+			if err != nil && !tc.expectedErr {
+				t.Fatalf("expected no error but got one: %v", err)
+			}
+			if flagged != tc.expected {
+				t.Errorf("expected flagged %t, got %t", tc.expected, flagged)
+			}
+		})
+	}
+}
+
+func TestGetIssueBackportReady(t *testing.T) {
+	const field = "customfield_1"
+	var testCases = []struct {
+		name        string
+		issue       *jira.Issue
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			name:  "unset field is not ready",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{"customfield_2": true}}},
+		},
+		{
+			name:  "null field is not ready",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: nil}}},
+		},
+		{
+			name:     "checked boolean is ready",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: true}}},
+			expected: true,
+		},
+		{
+			name:     "checked option is ready",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: []interface{}{map[string]interface{}{"id": "1", "value": "Ready"}}}}},
+			expected: true,
+		},
+		{
+			name:  "empty list of options is not ready",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: []interface{}{}}}},
+		},
+		{
+			name:     "selected yes is ready",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: map[string]interface{}{"id": "1", "value": "Yes"}}}},
+			expected: true,
+		},
+		{
+			name:  "selected no is not ready",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: map[string]interface{}{"id": "2", "value": "No"}}}},
+		},
+		{
+			name:     "text true is ready",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: " true "}}},
+			expected: true,
+		},
+		{
+			name:        "unexpected field type is an error",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{field: 1}}},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ready, err := GetIssueBackportReady(tc.issue, field)
+			if err == nil && tc.expectedErr {
+				t.Fatal("expected an error but got none")
+			}
+			if err != nil && !tc.expectedErr {
+				t.Fatalf("expected no error but got one: %v", err)
+			}
+			if ready != tc.expected {
+				t.Errorf("expected ready %t, got %t", tc.expected, ready)
+			}
+		})
+	}
+}
+
+func TestGetIssueSeverity(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		issue       *jira.Issue
+		fields      FieldMap
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:  "unset field has no severity",
+			issue: &jira.Issue{Fields: &jira.IssueFields{}},
+		},
+		{
+			name: "select field with HTML image maps to the severity name",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{
+				SeverityField: map[string]interface{}{"value": `<img alt="" src="/images/icons/priorities/critical.svg" width="16" height="16"> Critical`},
+			}}},
+			expected: "Critical",
+		},
+		{
+			name:     "plain text field maps to the severity name",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{SeverityField: "Important"}}},
+			expected: "Important",
+		},
+		{
+			name:     "plain text select value maps to the severity name",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{SeverityField: map[string]interface{}{"value": "Low"}}}},
+			expected: "Low",
+		},
+		{
+			name:        "unexpected field type is an error",
+			issue:       &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{SeverityField: []string{"Critical"}}}},
+			expectedErr: true,
+		},
+		{
+			name:     "custom field ID is read instead of the default",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{SeverityField: "Low", "customfield_1": "Important"}}},
+			fields:   FieldMap{Severity: "customfield_1"},
+			expected: "Important",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			severity, err := GetIssueSeverity(tc.issue, tc.fields)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %t, got: %v", tc.expectedErr, err)
+			}
+			var actual string
+			if severity != nil {
+				actual = SimplifiedSeverity(severity.Value)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected severity %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGetIssueTargetVersion(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		issue    *jira.Issue
+		fields   FieldMap
+		expected []string
+	}{
+		{
+			name:  "unset field has no target version",
+			issue: &jira.Issue{Fields: &jira.IssueFields{}},
+		},
+		{
+			name:     "default field is read",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{TargetVersionField: []map[string]interface{}{{"name": "4.12"}}}}},
+			expected: []string{"4.12"},
+		},
+		{
+			name:     "old default field is read if the default field is unset",
+			issue:    &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{TargetVersionFieldOld: []map[string]interface{}{{"name": "4.11"}}}}},
+			expected: []string{"4.11"},
+		},
+		{
+			name: "custom field ID is read instead of the default",
+			issue: &jira.Issue{Fields: &jira.IssueFields{Unknowns: tcontainer.MarshalMap{
+				TargetVersionField: []map[string]interface{}{{"name": "4.12"}},
+				"customfield_1":    []map[string]interface{}{{"name": "4.13"}},
+			}}},
+			fields:   FieldMap{TargetVersion: "customfield_1"},
+			expected: []string{"4.13"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			versions, err := GetIssueTargetVersion(tc.issue, tc.fields)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var actual []string
+			for _, version := range versions {
+				actual = append(actual, version.Name)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("unexpected target versions: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSimplifiedSeverity(t *testing.T) {
+	var testCases = []struct {
+		value    string
+		expected string
+	}{
+		{value: `<img alt="" src="/images/icons/priorities/medium.svg" width="16" height="16"> Moderate`, expected: "Moderate"},
+		{value: `<img alt="" src="/images/icons/priorities/low.svg" width="16" height="16">Low`, expected: "Low"},
+		{value: "Informational", expected: "Informational"},
+		{value: " Critical ", expected: "Critical"},
+		{value: "", expected: ""},
+	}
+	for _, tc := range testCases {
+		if actual := SimplifiedSeverity(tc.value); actual != tc.expected {
+			t.Errorf("%q: expected severity %q, got %q", tc.value, tc.expected, actual)
+		}
+	}
+}
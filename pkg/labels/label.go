@@ -10,4 +10,6 @@ const (
 	SeverityModerate      = "jira/severity-moderate"
 	SeverityLow           = "jira/severity-low"
 	SeverityInformational = "jira/severity-informational"
+	// SeverityPrefix is the prefix shared by the severity labels
+	SeverityPrefix = "jira/severity-"
 )